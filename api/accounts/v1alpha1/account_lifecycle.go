@@ -9,6 +9,11 @@ const (
 	AccountConditionSigningKeysUpdated = "SigningKeysUpdated"
 	AccountConditionJWTSecretReady     = "JWTSecretReady"
 	AccountConditionJWTPushed          = "JWTPushed"
+	AccountConditionAuthorizationReady = "AuthorizationReady"
+
+	// AccountConditionUsageWithinLimits warns when the usage of the Account approaches its limits, it is informational
+	// and does not affect the Ready condition.
+	AccountConditionUsageWithinLimits = "UsageWithinLimits"
 )
 
 var accountConditionSet = apis.NewLivingConditionSet(
@@ -19,6 +24,7 @@ var accountConditionSet = apis.NewLivingConditionSet(
 	AccountConditionSigningKeysUpdated,
 	AccountConditionJWTSecretReady,
 	AccountConditionJWTPushed,
+	AccountConditionAuthorizationReady,
 )
 
 func (*Account) GetConditionSet() apis.ConditionSet {
@@ -88,21 +94,33 @@ func (s *AccountStatus) MarkSigningKeysUpdateUnknown(reason, messageFormat strin
 	accountConditionSet.Manage(s).MarkUnknown(AccountConditionSigningKeysUpdated, reason, messageFormat, messageA...)
 }
 
-func (s *AccountStatus) MarkJWTSecretReady() {
+func (s *AccountStatus) MarkJWTSecretReady(claims ClaimsSummary) {
+	s.Claims = &claims
+
 	accountConditionSet.Manage(s).MarkTrue(AccountConditionJWTSecretReady)
 }
 
 func (s *AccountStatus) MarkJWTSecretFailed(reason, messageFormat string, messageA ...interface{}) {
+	s.Claims = nil
+
 	accountConditionSet.Manage(s).MarkFalse(AccountConditionJWTSecretReady, reason, messageFormat, messageA...)
 }
 
 func (s *AccountStatus) MarkJWTSecretUnknown(reason, messageFormat string, messageA ...interface{}) {
+	s.Claims = nil
+
 	accountConditionSet.Manage(s).MarkUnknown(AccountConditionJWTSecretReady, reason, messageFormat, messageA...)
 }
 
 func (s *AccountStatus) MarkSeedSecretReady(kp KeyPair) {
 	s.KeyPair = &kp
 
+	if kp.ExternallyManaged {
+		accountConditionSet.Manage(s).MarkTrueWithReason(KeyPairableConditionSeedSecretReady, ReasonExternallyManaged, "seed adopted from secret %s", kp.SeedSecretName)
+
+		return
+	}
+
 	accountConditionSet.Manage(s).MarkTrue(KeyPairableConditionSeedSecretReady)
 }
 
@@ -129,3 +147,45 @@ func (s *AccountStatus) MarkJWTPushFailed(reason, messageFormat string, messageA
 func (s *AccountStatus) MarkJWTPushUnknown(reason, messageFormat string, messageA ...interface{}) {
 	accountConditionSet.Manage(s).MarkUnknown(AccountConditionJWTPushed, reason, messageFormat, messageA...)
 }
+
+func (s *AccountStatus) MarkAuthorizationReady(authorization *AccountAuthorizationStatus) {
+	s.Authorization = authorization
+
+	accountConditionSet.Manage(s).MarkTrue(AccountConditionAuthorizationReady)
+}
+
+func (s *AccountStatus) MarkAuthorizationFailed(reason, messageFormat string, messageA ...interface{}) {
+	s.Authorization = nil
+
+	accountConditionSet.Manage(s).MarkFalse(AccountConditionAuthorizationReady, reason, messageFormat, messageA...)
+}
+
+func (s *AccountStatus) MarkAuthorizationUnknown(reason, messageFormat string, messageA ...interface{}) {
+	s.Authorization = nil
+
+	accountConditionSet.Manage(s).MarkUnknown(AccountConditionAuthorizationReady, reason, messageFormat, messageA...)
+}
+
+func (s *AccountStatus) MarkUsageWithinLimits(usage AccountUsage) {
+	s.Usage = &usage
+
+	accountConditionSet.Manage(s).MarkTrue(AccountConditionUsageWithinLimits)
+}
+
+func (s *AccountStatus) MarkUsageApproachingLimits(usage AccountUsage, messageFormat string, messageA ...interface{}) {
+	s.Usage = &usage
+
+	accountConditionSet.Manage(s).MarkFalse(AccountConditionUsageWithinLimits, ReasonApproachingLimits, messageFormat, messageA...)
+}
+
+func (s *AccountStatus) MarkUsageUnknown(reason, messageFormat string, messageA ...interface{}) {
+	accountConditionSet.Manage(s).MarkUnknown(AccountConditionUsageWithinLimits, reason, messageFormat, messageA...)
+}
+
+// ClearUsage removes the usage summary and condition when usage collection is disabled.
+func (s *AccountStatus) ClearUsage() {
+	s.Usage = nil
+
+	// UsageWithinLimits is not a dependent of Ready, so this cannot fail
+	_ = accountConditionSet.Manage(s).ClearCondition(AccountConditionUsageWithinLimits)
+}
@@ -26,6 +26,7 @@ SOFTWARE.
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -62,8 +63,16 @@ type AccountSpec struct {
 	// JWTSecretName is the name of the Secret that will be created to hold the JWT signing key for this Account.
 	JWTSecretName string `json:"jwtSecretName"`
 
-	// SeedSecretName is the name of the Secret that will be created to hold the seed for this Account.
-	SeedSecretName string `json:"seedSecretName"`
+	// SeedSecretName is the name of the Secret that will be created to hold the seed for this Account. Required unless
+	// ExistingSeedSecretRef is set.
+	// +optional
+	SeedSecretName string `json:"seedSecretName,omitempty"`
+
+	// ExistingSeedSecretRef adopts an existing account seed, for example one previously managed by nsc, instead of
+	// generating a new one. The referenced Secret must be in the same namespace, is never modified or deleted, and takes
+	// precedence over SeedSecretName.
+	// +optional
+	ExistingSeedSecretRef *v1.SecretKeySelector `json:"existingSeedSecretRef,omitempty"`
 
 	// SigningKeysSelector is the label selector to restrict which SigningKeys can be used to sign JWTs for this
 	// Account. SigningKeys must be in the same namespace as the Account.
@@ -77,6 +86,35 @@ type AccountSpec struct {
 
 	// Limits is a JWT claim for the Account.
 	Limits *OperatorLimits `json:"limits,omitempty"`
+
+	// Authorization configures auth callout for this Account, delegating the authentication of Users to an external
+	// service.
+	// +optional
+	Authorization *AccountAuthorization `json:"authorization,omitempty"`
+}
+
+// AccountAuthorization is used to configure auth callout for an Account, see
+// https://docs.nats.io/running-a-nats-service/configuration/securing_nats/auth_callout for details.
+type AccountAuthorization struct {
+	// AuthUsers are references to the Users which the auth callout service will connect as. Connections from these
+	// Users bypass auth callout. Namespace defaults to the namespace of the Account.
+	// +kubebuilder:validation:MinItems=1
+	AuthUsers []InferredObjectReference `json:"authUsers"`
+
+	// AllowedAccounts are references to the Accounts which the auth callout service may issue Users for. When empty,
+	// Users may only be issued for this Account. Namespace defaults to the namespace of the Account.
+	// +optional
+	AllowedAccounts []InferredObjectReference `json:"allowedAccounts,omitempty"`
+
+	// XKey enables encryption of auth callout requests and responses. When set, the controller will generate a curve
+	// key pair and store it in the referenced Secret, this should be mounted into the auth callout service.
+	// +optional
+	XKey *AccountAuthorizationXKey `json:"xkey,omitempty"`
+}
+
+type AccountAuthorizationXKey struct {
+	// SeedSecretName is the name of the Secret that will be created to hold the xkey seed for this Account.
+	SeedSecretName string `json:"seedSecretName"`
 }
 
 type AccountImport struct {
@@ -111,6 +149,11 @@ type OperatorLimits struct {
 	Nats      NatsLimits      `json:"nats,omitempty"`
 	Account   AccountLimits   `json:"account,omitempty"`
 	JetStream JetStreamLimits `json:"jetStream,omitempty"`
+
+	// TieredJetStream defines JetStream limits per replication tier, keyed by the tier name (e.g. "R1" or "R3"). This
+	// allows replicated storage to be limited separately to non-replicated storage. TieredJetStream and JetStream are
+	// mutually exclusive, the controller will refuse to sign the Account JWT if both are set.
+	TieredJetStream map[string]JetStreamLimits `json:"tieredJetStream,omitempty"`
 }
 
 type NatsLimits struct {
@@ -146,6 +189,59 @@ type AccountStatus struct {
 	KeyPair     *KeyPair                   `json:"keyPair,omitempty"`
 	SigningKeys []SigningKeyEmbeddedStatus `json:"signingKeys,omitempty"`
 	OperatorRef *InferredObjectReference   `json:"operatorRef,omitempty"`
+
+	// Authorization contains the resolved public keys of the auth callout configuration for this Account.
+	Authorization *AccountAuthorizationStatus `json:"authorization,omitempty"`
+
+	// Claims summarises the Account JWT currently held in the JWT Secret.
+	Claims *ClaimsSummary `json:"claims,omitempty"`
+
+	// LastHandledForcePushAt is the value of the accounts.nats.io/force-push-at annotation which was last handled.
+	LastHandledForcePushAt string `json:"lastHandledForcePushAt,omitempty"`
+
+	// Usage summarises the usage of the Account across the NATS servers of its Operator, refreshed every
+	// Operator.spec.usageRefreshInterval.
+	// +optional
+	Usage *AccountUsage `json:"usage,omitempty"`
+}
+
+// AccountUsage summarises the usage of an Account reported by the NATS servers.
+type AccountUsage struct {
+	// Connections is the number of client connections to the Account.
+	Connections int64 `json:"connections"`
+
+	// LeafNodes is the number of leaf node connections to the Account.
+	LeafNodes int64 `json:"leafNodes"`
+
+	// Subscriptions is the number of subscriptions in the Account.
+	Subscriptions int64 `json:"subscriptions"`
+
+	// LastActivity is the most recent activity of any client connected to the Account, it is unset if no clients
+	// are connected.
+	// +optional
+	LastActivity *metav1.Time `json:"lastActivity,omitempty"`
+
+	// JetStream is the JetStream storage used by the Account, it is unset if JetStream is not enabled for the
+	// Account.
+	// +optional
+	JetStream *JetStreamUsage `json:"jetStream,omitempty"`
+
+	// ObservedAt is the time the usage above was first collected, it is not updated by polls which observe no change.
+	ObservedAt metav1.Time `json:"observedAt"`
+}
+
+// JetStreamUsage is the JetStream storage used by an Account and its limits, a limit of 0 is unlimited.
+type JetStreamUsage struct {
+	MemoryBytes      int64 `json:"memoryBytes"`
+	MemoryLimitBytes int64 `json:"memoryLimitBytes,omitempty"`
+	DiskBytes        int64 `json:"diskBytes"`
+	DiskLimitBytes   int64 `json:"diskLimitBytes,omitempty"`
+}
+
+type AccountAuthorizationStatus struct {
+	AuthUsers       []KeyPairReference `json:"authUsers,omitempty"`
+	AllowedAccounts []KeyPairReference `json:"allowedAccounts,omitempty"`
+	XKey            *KeyPair           `json:"xkey,omitempty"`
 }
 
 type OperatorRef struct {
@@ -159,6 +255,8 @@ type OperatorRef struct {
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Public Key",type=string,JSONPath=`.status.keyPair.publicKey`
 //+kubebuilder:printcolumn:name="Operator",type=string,JSONPath=`.status.operatorRef.name`
+//+kubebuilder:printcolumn:name="Issuer",type=string,JSONPath=`.status.claims.issuer`
+//+kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.claims.expires`
 //+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`
 
 // Account is the Schema for the accounts API
@@ -1,6 +1,7 @@
 package v1alpha1
 
 import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
 )
@@ -67,3 +68,41 @@ type KeyPairReference struct {
 	InferredObjectReference `json:",inline"`
 	PublicKey               string `json:"publicKey"`
 }
+
+// ClaimsSummary summarises the claims of the JWT currently held in a resource's JWT Secret, so the effective claims
+// can be inspected without decoding the Secret.
+type ClaimsSummary struct {
+	// JTI is the unique ID of the JWT, this changes every time the JWT is re-issued.
+	JTI string `json:"jti"`
+
+	// Issuer is the public key which signed the JWT, this is either the identity key of the issuing Operator or
+	// Account, or one of its signing keys.
+	Issuer string `json:"issuer"`
+
+	// IssuerAccount is the public key of the Account which issued a User JWT, when it was signed by one of the
+	// Account's signing keys.
+	// +optional
+	IssuerAccount string `json:"issuerAccount,omitempty"`
+
+	// IssuedAt is the time the JWT was issued.
+	IssuedAt metav1.Time `json:"issuedAt"`
+
+	// Expires is the time the JWT expires, it is unset if the JWT does not expire.
+	// +optional
+	Expires *metav1.Time `json:"expires,omitempty"`
+
+	// Hash is the hex encoded SHA-256 hash of the encoded JWT, which can be compared to the JWT held by a resolver.
+	Hash string `json:"hash"`
+
+	// Exports is the number of exports in an Account JWT.
+	// +optional
+	Exports int `json:"exports,omitempty"`
+
+	// Imports is the number of imports in an Account JWT.
+	// +optional
+	Imports int `json:"imports,omitempty"`
+
+	// SigningKeys is the number of signing keys in an Operator or Account JWT.
+	// +optional
+	SigningKeys int `json:"signingKeys,omitempty"`
+}
@@ -14,4 +14,24 @@ const (
 	ReasonInvalidJWTSecret         = "InvalidJWTSecret"
 	ReasonInvalidCredentialsSecret = "InvalidCredentialsSecret"
 	ReasonJWTPushError             = "JWTPushError"
+	ReasonInvalidLimits            = "InvalidLimits"
+	ReasonStrictSigningKeyUsage    = "StrictSigningKeyUsage"
+	ReasonOfflineIdentity          = "OfflineIdentity"
+	ReasonOfflineJWTOutdated       = "OfflineJWTOutdated"
+	ReasonExternallyManaged        = "ExternallyManaged"
+	ReasonApproachingLimits        = "ApproachingLimits"
+	ReasonUsageUnavailable         = "UsageUnavailable"
+	ReasonServerUnreachable        = "ServerUnreachable"
+	ReasonUnlabelledSecret         = "UnlabelledSecret"
+)
+
+const (
+	// AnnotationPaused pauses reconciliation of a resource while set to "true", so no Secrets are changed and no JWTs
+	// are pushed. When set on an Operator, reconciliation of every Account issued by the Operator is also paused.
+	AnnotationPaused = "accounts.nats.io/paused"
+
+	// AnnotationForcePushAt requests a fresh re-sign of the JWT of an Operator, Account or User, and a re-push of an
+	// Account JWT, even if the claims are unchanged. The value is typically the current timestamp, each new value is
+	// handled once and echoed into status.lastHandledForcePushAt.
+	AnnotationForcePushAt = "accounts.nats.io/force-push-at"
 )
@@ -15,6 +15,24 @@ const (
 type KeyPair struct {
 	PublicKey      string `json:"publicKey"`
 	SeedSecretName string `json:"seedSecretName"`
+
+	// SeedSecretKey is the key within the seed Secret holding the seed, defaults to "seed.nk" when empty.
+	// +optional
+	SeedSecretKey string `json:"seedSecretKey,omitempty"`
+
+	// ExternallyManaged is true when the seed Secret was adopted from an existing Secret, rather than created by this
+	// controller. Externally managed Secrets are never modified or deleted by the controller.
+	// +optional
+	ExternallyManaged bool `json:"externallyManaged,omitempty"`
+}
+
+// GetSeedSecretKey returns the key within the seed Secret which holds the seed.
+func (kp *KeyPair) GetSeedSecretKey() string {
+	if kp.SeedSecretKey == "" {
+		return NatsSecretSeedKey
+	}
+
+	return kp.SeedSecretKey
 }
 
 // +k8s:deepcopy-gen=false
@@ -8,6 +8,14 @@ const (
 	OperatorConditionSigningKeysUpdated    = "SigningKeysUpdated"
 	OperatorConditionJWTSecretReady        = "JWTSecretReady"
 	OperatorConditionSeedSecretReady       = "SeedSecretReady"
+
+	// OperatorConditionBootstrapReady is only set when OperatorSpec.Bootstrap is configured, it is informational and
+	// does not affect the Ready condition since the bootstrapped resources depend on the Operator.
+	OperatorConditionBootstrapReady = "BootstrapReady"
+
+	// OperatorConditionServerReachable reports whether the account server is reachable with the system account
+	// credentials, it is informational and does not affect the Ready condition.
+	OperatorConditionServerReachable = "ServerReachable"
 )
 
 var operatorConditionSet = apis.NewLivingConditionSet(
@@ -74,24 +82,43 @@ func (os *OperatorStatus) MarkSigningKeysUpdateUnknown(reason, messageFormat str
 	operatorConditionSet.Manage(os).MarkUnknown(OperatorConditionSigningKeysUpdated, reason, messageFormat, messageA...)
 }
 
-func (os *OperatorStatus) MarkJWTSecretReady() {
+func (os *OperatorStatus) MarkJWTSecretReady(claims ClaimsSummary) {
+	os.Claims = &claims
+
 	operatorConditionSet.Manage(os).MarkTrue(OperatorConditionJWTSecretReady)
 }
 
 func (os *OperatorStatus) MarkJWTSecretFailed(reason, messageFormat string, messageA ...interface{}) {
+	os.Claims = nil
+
 	operatorConditionSet.Manage(os).MarkFalse(OperatorConditionJWTSecretReady, reason, messageFormat, messageA...)
 }
 
 func (os *OperatorStatus) MarkJWTSecretUnknown(reason, messageFormat string, messageA ...interface{}) {
+	os.Claims = nil
+
 	operatorConditionSet.Manage(os).MarkUnknown(OperatorConditionJWTSecretReady, reason, messageFormat, messageA...)
 }
 
 func (os *OperatorStatus) MarkSeedSecretReady(kp KeyPair) {
 	os.KeyPair = &kp
 
+	if kp.ExternallyManaged {
+		operatorConditionSet.Manage(os).MarkTrueWithReason(OperatorConditionSeedSecretReady, ReasonExternallyManaged, "seed adopted from secret %s", kp.SeedSecretName)
+
+		return
+	}
+
 	operatorConditionSet.Manage(os).MarkTrue(OperatorConditionSeedSecretReady)
 }
 
+// MarkSeedSecretOffline records the public key of an Operator whose identity seed is held outside the cluster.
+func (os *OperatorStatus) MarkSeedSecretOffline(publicKey string) {
+	os.KeyPair = &KeyPair{PublicKey: publicKey}
+
+	operatorConditionSet.Manage(os).MarkTrueWithReason(OperatorConditionSeedSecretReady, ReasonOfflineIdentity, "operator identity seed is held offline")
+}
+
 func (os *OperatorStatus) MarkSeedSecretFailed(reason, messageFormat string, messageA ...interface{}) {
 	os.KeyPair = nil
 
@@ -103,3 +130,42 @@ func (os *OperatorStatus) MarkSeedSecretUnknown(reason, messageFormat string, me
 
 	operatorConditionSet.Manage(os).MarkUnknown(OperatorConditionSeedSecretReady, reason, messageFormat, messageA...)
 }
+
+func (os *OperatorStatus) MarkBootstrapReady(resources []BootstrapResourceStatus) {
+	os.Bootstrap = resources
+
+	operatorConditionSet.Manage(os).MarkTrue(OperatorConditionBootstrapReady)
+}
+
+func (os *OperatorStatus) MarkBootstrapNotReady(resources []BootstrapResourceStatus, reason, messageFormat string, messageA ...interface{}) {
+	os.Bootstrap = resources
+
+	operatorConditionSet.Manage(os).MarkUnknown(OperatorConditionBootstrapReady, reason, messageFormat, messageA...)
+}
+
+func (os *OperatorStatus) MarkBootstrapFailed(reason, messageFormat string, messageA ...interface{}) {
+	operatorConditionSet.Manage(os).MarkFalse(OperatorConditionBootstrapReady, reason, messageFormat, messageA...)
+}
+
+func (os *OperatorStatus) MarkBootstrapUnknown(reason, messageFormat string, messageA ...interface{}) {
+	operatorConditionSet.Manage(os).MarkUnknown(OperatorConditionBootstrapReady, reason, messageFormat, messageA...)
+}
+
+// ClearBootstrap removes the bootstrap status and condition when OperatorSpec.Bootstrap is not configured.
+func (os *OperatorStatus) ClearBootstrap() {
+	os.Bootstrap = nil
+
+	// BootstrapReady is not a dependent of Ready, so this cannot fail
+	_ = operatorConditionSet.Manage(os).ClearCondition(OperatorConditionBootstrapReady)
+}
+
+func (os *OperatorStatus) MarkServerReachable(server OperatorServerStatus) {
+	os.Server = &server
+
+	operatorConditionSet.Manage(os).MarkTrue(OperatorConditionServerReachable)
+}
+
+// MarkServerUnreachable marks the account server as unreachable, the last observed server status is kept.
+func (os *OperatorStatus) MarkServerUnreachable(reason, messageFormat string, messageA ...interface{}) {
+	operatorConditionSet.Manage(os).MarkFalse(OperatorConditionServerReachable, reason, messageFormat, messageA...)
+}
@@ -40,13 +40,52 @@ type TLSConfig struct {
 	CAFile *v1.SecretKeySelector `json:"caFile,omitempty"`
 }
 
+// OperatorOfflineIdentity references an Operator JWT which has been signed outside the cluster with the Operator
+// identity key.
+type OperatorOfflineIdentity struct {
+	// JWTSecretRef is a reference to the key of a Secret, in the same namespace as the Operator, containing the
+	// pre-signed Operator JWT. The JWT must list the public keys of every SigningKey owned by this Operator, and the
+	// public key of the system account. The Secret must be labelled nats.accounts.io/secret-type=jwt, so that changes
+	// to it are watched.
+	JWTSecretRef v1.SecretKeySelector `json:"jwtSecretRef"`
+}
+
+// OperatorBootstrap configures the Operator to create the resources making up a complete trust hierarchy: the system
+// Account referenced by OperatorSpec.SystemAccountRef, a SigningKey for the Operator which issues the system Account, a
+// SigningKey for the system Account, and a system User issued by it with credentials. The resources are controlled by
+// the Operator and deleted with it. Resources which already exist and are not controlled by the Operator are used
+// as-is.
+type OperatorBootstrap struct {
+	// SystemUserName is the name of the system User, its credentials are stored in the Secret <name>-creds. Defaults to
+	// <operator>-system.
+	// +optional
+	SystemUserName string `json:"systemUserName,omitempty"`
+
+	// Labels are added to every bootstrapped resource, so they can be matched by the Operator and Account selectors.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
 // OperatorSpec defines the desired state of Operator
 type OperatorSpec struct {
 	// JWTSecretName is the name of the secret containing the self-signed Operator JWT.
 	JWTSecretName string `json:"jwtSecretName"`
 
-	// SeedSecretName is the name of the secret containing the seed for this Operator.
-	SeedSecretName string `json:"seedSecretName"`
+	// SeedSecretName is the name of the secret containing the seed for this Operator. Required unless OfflineIdentity
+	// is set.
+	// +optional
+	SeedSecretName string `json:"seedSecretName,omitempty"`
+
+	// ExistingSeedSecretRef adopts an existing operator identity seed, for example one previously managed by nsc,
+	// instead of generating a new one. The referenced Secret must be in the same namespace, is never modified or
+	// deleted, and takes precedence over SeedSecretName.
+	// +optional
+	ExistingSeedSecretRef *v1.SecretKeySelector `json:"existingSeedSecretRef,omitempty"`
+
+	// OfflineIdentity configures the Operator to keep its identity seed out of the cluster. The Operator JWT must be
+	// signed offline and supplied in a Secret, and Accounts must be issued by one of the Operator's SigningKeys.
+	// +optional
+	OfflineIdentity *OperatorOfflineIdentity `json:"offlineIdentity,omitempty"`
 
 	// AccountsNamespaceSelector defines which namespaces are allowed to contain Accounts managed by this Operator. By
 	// default, the Operator will manage Accounts in the same namespace as the Operator, it can be set to an empty
@@ -75,6 +114,32 @@ type OperatorSpec struct {
 
 	// OperatorServiceURLs is a JWT claim for the Operator
 	OperatorServiceURLs []string `json:"operatorServiceURLs,omitempty"`
+
+	// StrictSigningKeyUsage is a JWT claim for the Operator. When enabled, the NATS server rejects Accounts signed by
+	// the Operator identity key, and the controller requires Accounts to be issued by one of the Operator's
+	// SigningKeys.
+	// +optional
+	StrictSigningKeyUsage bool `json:"strictSigningKeyUsage,omitempty"`
+
+	// AssertServerVersion is a JWT claim for the Operator, NATS servers older than this version will refuse to start
+	// with this Operator. Must be of the form <major>.<minor>.<update>.
+	// +kubebuilder:validation:Pattern=`^[0-9]+\.[0-9]+\.[0-9]+$`
+	// +optional
+	AssertServerVersion string `json:"assertServerVersion,omitempty"`
+
+	// Tags is a JWT claim for the Operator.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
+
+	// Bootstrap creates the system Account, SigningKeys and a system User for this Operator, so a complete trust
+	// hierarchy can be created from a single resource.
+	// +optional
+	Bootstrap *OperatorBootstrap `json:"bootstrap,omitempty"`
+
+	// UsageRefreshInterval is how often the usage of this Operator's Accounts is queried from the account server and
+	// written to their status. Defaults to 5m, setting it to 0s disables usage collection.
+	// +optional
+	UsageRefreshInterval *metav1.Duration `json:"usageRefreshInterval,omitempty"`
 }
 
 // OperatorStatus defines the observed state of Operator
@@ -92,6 +157,50 @@ type OperatorStatus struct {
 	// ResolvedSystemAccount is the Account that this Operator will use as it's system account. This is the same as the
 	// resource defined in OperatorSpec.SystemAccountRef, but validated that the resource exists.
 	ResolvedSystemAccount *KeyPairReference `json:"resolvedSystemAccount,omitempty"`
+
+	// Claims summarises the Operator JWT currently held in the JWT Secret.
+	Claims *ClaimsSummary `json:"claims,omitempty"`
+
+	// LastHandledForcePushAt is the value of the accounts.nats.io/force-push-at annotation which was last handled.
+	LastHandledForcePushAt string `json:"lastHandledForcePushAt,omitempty"`
+
+	// Bootstrap reports the readiness of each resource created by OperatorSpec.Bootstrap.
+	Bootstrap []BootstrapResourceStatus `json:"bootstrap,omitempty"`
+
+	// Server describes the NATS server behind AccountServerURL, as last observed by the ServerReachable check.
+	// +optional
+	Server *OperatorServerStatus `json:"server,omitempty"`
+}
+
+// OperatorServerStatus describes a NATS server learned from $SYS.REQ.SERVER.PING.
+type OperatorServerStatus struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+
+	// JetStream is true if JetStream is enabled on the server.
+	JetStream bool `json:"jetStream"`
+
+	// ResolverType is "full" or "cache" for the NATS based resolvers, it is empty if the server does not accept
+	// account JWTs over the $SYS.REQ.CLAIMS subjects.
+	// +optional
+	ResolverType string `json:"resolverType,omitempty"`
+
+	// ObservedAt is the time the server was first observed with the name, version and configuration above, it is not
+	// updated by probes which observe no change.
+	ObservedAt metav1.Time `json:"observedAt"`
+}
+
+// BootstrapResourceStatus is the readiness of a resource created by OperatorSpec.Bootstrap.
+type BootstrapResourceStatus struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+
+	// Ready mirrors the Ready condition of the resource.
+	Ready bool `json:"ready"`
+
+	// Message is the message of the Ready condition when the resource is not Ready.
+	// +optional
+	Message string `json:"message,omitempty"`
 }
 
 func (os *OperatorStatus) GetConditions() apis.Conditions {
@@ -108,7 +217,11 @@ func (os *OperatorStatus) SetConditions(conditions apis.Conditions) {
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Public Key",type=string,JSONPath=`.status.keyPair.publicKey`
 //+kubebuilder:printcolumn:name="System Account",type=string,JSONPath=`.status.resolvedSystemAccount.name`
+//+kubebuilder:printcolumn:name="Issuer",type=string,JSONPath=`.status.claims.issuer`
+//+kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.claims.expires`
 //+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`
+//+kubebuilder:printcolumn:name="Server Reachable",type=string,JSONPath=`.status.conditions[?(@.type=='ServerReachable')].status`,priority=1
+//+kubebuilder:printcolumn:name="Server Version",type=string,JSONPath=`.status.server.version`,priority=1
 
 // Operator is the Schema for the operators API
 type Operator struct {
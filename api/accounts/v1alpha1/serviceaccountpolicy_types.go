@@ -0,0 +1,84 @@
+/*
+MIT License
+
+Copyright (c) 2024 Versori Ltd
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ServiceAccountPolicySpec defines which Kubernetes ServiceAccounts may connect to NATS via the embedded auth callout
+// service, and the claims of the short-lived User JWTs issued to them.
+type ServiceAccountPolicySpec struct {
+	// ServiceAccountNames are the names of the ServiceAccounts, in the same namespace as this policy, which this policy
+	// applies to.
+	// +kubebuilder:validation:MinItems=1
+	ServiceAccountNames []string `json:"serviceAccountNames"`
+
+	// AccountRef is a reference to the Account which Users will be placed into. The Account must be the auth callout
+	// Account or one of its allowed accounts, and must allow Users from this namespace following its
+	// usersNamespaceSelector and usersSelector. Namespace defaults to the namespace of this policy.
+	AccountRef InferredObjectReference `json:"accountRef"`
+
+	// Permissions is a JWT claim for the issued Users.
+	// +optional
+	Permissions *UserPermissions `json:"permissions,omitempty"`
+
+	// Limits is a JWT claim for the issued Users.
+	// +optional
+	Limits UserLimits `json:"limits,omitempty"`
+
+	// TTL is how long issued User JWTs are valid for. When omitted, the default TTL of the auth callout service is used.
+	// +optional
+	TTL *metav1.Duration `json:"ttl,omitempty"`
+}
+
+//+genclient
+//+genclient:noStatus
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:shortName=natssap
+//+kubebuilder:printcolumn:name="Account",type=string,JSONPath=`.spec.accountRef.name`
+//+kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ServiceAccountPolicy is the Schema for the serviceaccountpolicies API
+type ServiceAccountPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ServiceAccountPolicySpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// ServiceAccountPolicyList contains a list of ServiceAccountPolicy
+type ServiceAccountPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ServiceAccountPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ServiceAccountPolicy{}, &ServiceAccountPolicyList{})
+}
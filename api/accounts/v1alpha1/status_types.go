@@ -3,6 +3,11 @@ package v1alpha1
 import "github.com/versori-oss/nats-account-operator/pkg/apis"
 
 type Status struct {
+	// ObservedGeneration is the metadata.generation of the resource which was last reconciled. Each condition also
+	// records the generation at which it was last evaluated.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
 	// Conditions the latest available observations of a resource's current state.
 	// +optional
 	// +patchMergeKey=type
@@ -10,7 +15,10 @@ type Status struct {
 	Conditions apis.Conditions `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
 }
 
-var _ apis.ConditionsAccessor = (*Status)(nil)
+var (
+	_ apis.ConditionsAccessor         = (*Status)(nil)
+	_ apis.ObservedGenerationAccessor = (*Status)(nil)
+)
 
 func (s *Status) GetConditions() apis.Conditions {
 	return s.Conditions
@@ -20,6 +28,10 @@ func (s *Status) SetConditions(conditions apis.Conditions) {
 	s.Conditions = conditions
 }
 
+func (s *Status) GetObservedGeneration() int64 {
+	return s.ObservedGeneration
+}
+
 // +k8s:deepcopy-gen=false
 
 // StatusAccessor provides a way to access our standard Status subresource which contains Conditions.
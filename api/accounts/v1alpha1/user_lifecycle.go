@@ -68,21 +68,33 @@ func (s *UserStatus) MarkIssuerResolveUnknown(reason, messageFormat string, mess
 	userConditionSet.Manage(s).MarkUnknown(UserConditionIssuerResolved, reason, messageFormat, messageA...)
 }
 
-func (s *UserStatus) MarkJWTSecretReady() {
+func (s *UserStatus) MarkJWTSecretReady(claims ClaimsSummary) {
+	s.Claims = &claims
+
 	userConditionSet.Manage(s).MarkTrue(UserConditionJWTSecretReady)
 }
 
 func (s *UserStatus) MarkJWTSecretFailed(reason, messageFormat string, messageA ...interface{}) {
+	s.Claims = nil
+
 	userConditionSet.Manage(s).MarkFalse(UserConditionJWTSecretReady, reason, messageFormat, messageA...)
 }
 
 func (s *UserStatus) MarkJWTSecretUnknown(reason, messageFormat string, messageA ...interface{}) {
+	s.Claims = nil
+
 	userConditionSet.Manage(s).MarkUnknown(UserConditionJWTSecretReady, reason, messageFormat, messageA...)
 }
 
 func (s *UserStatus) MarkSeedSecretReady(kp KeyPair) {
 	s.KeyPair = &kp
 
+	if kp.ExternallyManaged {
+		userConditionSet.Manage(s).MarkTrueWithReason(KeyPairableConditionSeedSecretReady, ReasonExternallyManaged, "seed adopted from secret %s", kp.SeedSecretName)
+
+		return
+	}
+
 	userConditionSet.Manage(s).MarkTrue(KeyPairableConditionSeedSecretReady)
 }
 
@@ -26,6 +26,7 @@ SOFTWARE.
 package v1alpha1
 
 import (
+	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"github.com/versori-oss/nats-account-operator/pkg/apis"
@@ -41,8 +42,16 @@ type UserSpec struct {
 	// JWTSecretName is the name of the Secret that will be created to store the JWT for this User.
 	JWTSecretName string `json:"jwtSecretName"`
 
-	// SeedSecretName is the name of the Secret that will be created to store the seed for this User.
-	SeedSecretName string `json:"seedSecretName"`
+	// SeedSecretName is the name of the Secret that will be created to store the seed for this User. Required unless
+	// ExistingSeedSecretRef is set.
+	// +optional
+	SeedSecretName string `json:"seedSecretName,omitempty"`
+
+	// ExistingSeedSecretRef adopts an existing user seed, for example one previously managed by nsc, instead of
+	// generating a new one. The referenced Secret must be in the same namespace, is never modified or deleted, and takes
+	// precedence over SeedSecretName.
+	// +optional
+	ExistingSeedSecretRef *v1.SecretKeySelector `json:"existingSeedSecretRef,omitempty"`
 
 	// CredentialsSecretName is the name of the Secret that will be created to store the credentials for this User.
 	CredentialsSecretName string `json:"credentialsSecretName"`
@@ -58,8 +67,31 @@ type UserSpec struct {
 	// BearerToken is a JWT claim for the User.
 	// +optional
 	BearerToken *bool `json:"bearerToken,omitempty"`
+
+	// AllowedConnectionTypes restricts which types of connection the User may use. When empty, all connection types
+	// are allowed.
+	// +optional
+	AllowedConnectionTypes []ConnectionType `json:"allowedConnectionTypes,omitempty"`
+
+	// Tags is a JWT claim for the User.
+	// +optional
+	Tags []string `json:"tags,omitempty"`
 }
 
+// ConnectionType is one of the connection types a User may be restricted to, these match the jwt.ConnectionType*
+// constants in github.com/nats-io/jwt/v2.
+// +kubebuilder:validation:Enum=STANDARD;WEBSOCKET;LEAFNODE;LEAFNODE_WS;MQTT;MQTT_WS
+type ConnectionType string
+
+const (
+	ConnectionTypeStandard   ConnectionType = "STANDARD"
+	ConnectionTypeWebsocket  ConnectionType = "WEBSOCKET"
+	ConnectionTypeLeafnode   ConnectionType = "LEAFNODE"
+	ConnectionTypeLeafnodeWS ConnectionType = "LEAFNODE_WS"
+	ConnectionTypeMqtt       ConnectionType = "MQTT"
+	ConnectionTypeMqttWS     ConnectionType = "MQTT_WS"
+)
+
 type UserPermissions struct {
 	Pub  Permission      `json:"pub,omitempty"`
 	Sub  Permission      `json:"sub,omitempty"`
@@ -99,6 +131,36 @@ type UserStatus struct {
 
 	KeyPair    *KeyPair                 `json:"keyPair,omitempty"`
 	AccountRef *InferredObjectReference `json:"accountRef,omitempty"`
+
+	// Claims summarises the User JWT currently held in the JWT Secret.
+	Claims *ClaimsSummary `json:"claims,omitempty"`
+
+	// LastHandledForcePushAt is the value of the accounts.nats.io/force-push-at annotation which was last handled.
+	LastHandledForcePushAt string `json:"lastHandledForcePushAt,omitempty"`
+
+	// Connections records the connections made with this User's credentials, observed from the connect and
+	// disconnect events published by the NATS servers.
+	// +optional
+	Connections *UserConnections `json:"connections,omitempty"`
+}
+
+// UserConnections is the connection activity of a User. It reflects the connect and disconnect events observed by the
+// controller, and Active is re-synchronised with the account server whenever the controller subscribes to the events.
+type UserConnections struct {
+	// Active is the number of open connections using the User's credentials.
+	Active int64 `json:"active"`
+
+	// LastConnectedAt is the time the User last connected.
+	// +optional
+	LastConnectedAt *metav1.Time `json:"lastConnectedAt,omitempty"`
+
+	// LastDisconnectedAt is the time a connection of the User was last closed.
+	// +optional
+	LastDisconnectedAt *metav1.Time `json:"lastDisconnectedAt,omitempty"`
+
+	// LastClientIP is the address the User last connected from.
+	// +optional
+	LastClientIP string `json:"lastClientIP,omitempty"`
 }
 
 func (s *UserStatus) GetConditions() apis.Conditions {
@@ -115,6 +177,9 @@ func (s *UserStatus) SetConditions(conditions apis.Conditions) {
 //+kubebuilder:subresource:status
 //+kubebuilder:printcolumn:name="Public Key",type=string,JSONPath=`.status.keyPair.publicKey`
 //+kubebuilder:printcolumn:name="Account",type=string,JSONPath=`.status.accountRef.name`
+//+kubebuilder:printcolumn:name="Issuer",type=string,JSONPath=`.status.claims.issuer`
+//+kubebuilder:printcolumn:name="Expires",type=date,JSONPath=`.status.claims.expires`
+//+kubebuilder:printcolumn:name="Last Connected",type=date,JSONPath=`.status.connections.lastConnectedAt`,priority=1
 //+kubebuilder:printcolumn:name="Ready",type=string,JSONPath=`.status.conditions[?(@.type=='Ready')].status`
 
 // User is the Schema for the users API
@@ -126,6 +191,16 @@ type User struct {
 	Status UserStatus `json:"status,omitempty"`
 }
 
+var _ KeyPairable = (*User)(nil)
+
+func (u *User) GetStatus() *Status {
+	return &u.Status.Status
+}
+
+func (u *User) GetKeyPair() *KeyPair {
+	return u.Status.KeyPair
+}
+
 //+kubebuilder:object:root=true
 
 // UserList contains a list of User
@@ -63,6 +63,81 @@ func (in *Account) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountAuthorization) DeepCopyInto(out *AccountAuthorization) {
+	*out = *in
+	if in.AuthUsers != nil {
+		in, out := &in.AuthUsers, &out.AuthUsers
+		*out = make([]InferredObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedAccounts != nil {
+		in, out := &in.AllowedAccounts, &out.AllowedAccounts
+		*out = make([]InferredObjectReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.XKey != nil {
+		in, out := &in.XKey, &out.XKey
+		*out = new(AccountAuthorizationXKey)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountAuthorization.
+func (in *AccountAuthorization) DeepCopy() *AccountAuthorization {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountAuthorization)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountAuthorizationStatus) DeepCopyInto(out *AccountAuthorizationStatus) {
+	*out = *in
+	if in.AuthUsers != nil {
+		in, out := &in.AuthUsers, &out.AuthUsers
+		*out = make([]KeyPairReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedAccounts != nil {
+		in, out := &in.AllowedAccounts, &out.AllowedAccounts
+		*out = make([]KeyPairReference, len(*in))
+		copy(*out, *in)
+	}
+	if in.XKey != nil {
+		in, out := &in.XKey, &out.XKey
+		*out = new(KeyPair)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountAuthorizationStatus.
+func (in *AccountAuthorizationStatus) DeepCopy() *AccountAuthorizationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountAuthorizationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountAuthorizationXKey) DeepCopyInto(out *AccountAuthorizationXKey) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountAuthorizationXKey.
+func (in *AccountAuthorizationXKey) DeepCopy() *AccountAuthorizationXKey {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountAuthorizationXKey)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AccountExport) DeepCopyInto(out *AccountExport) {
 	*out = *in
@@ -199,6 +274,11 @@ func (in *AccountSpec) DeepCopyInto(out *AccountSpec) {
 		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ExistingSeedSecretRef != nil {
+		in, out := &in.ExistingSeedSecretRef, &out.ExistingSeedSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.SigningKeysSelector != nil {
 		in, out := &in.SigningKeysSelector, &out.SigningKeysSelector
 		*out = new(v1.LabelSelector)
@@ -221,6 +301,11 @@ func (in *AccountSpec) DeepCopyInto(out *AccountSpec) {
 		*out = new(OperatorLimits)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Authorization != nil {
+		in, out := &in.Authorization, &out.Authorization
+		*out = new(AccountAuthorization)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountSpec.
@@ -252,6 +337,21 @@ func (in *AccountStatus) DeepCopyInto(out *AccountStatus) {
 		*out = new(InferredObjectReference)
 		**out = **in
 	}
+	if in.Authorization != nil {
+		in, out := &in.Authorization, &out.Authorization
+		*out = new(AccountAuthorizationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = new(ClaimsSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(AccountUsage)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountStatus.
@@ -264,6 +364,66 @@ func (in *AccountStatus) DeepCopy() *AccountStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AccountUsage) DeepCopyInto(out *AccountUsage) {
+	*out = *in
+	if in.LastActivity != nil {
+		in, out := &in.LastActivity, &out.LastActivity
+		*out = (*in).DeepCopy()
+	}
+	if in.JetStream != nil {
+		in, out := &in.JetStream, &out.JetStream
+		*out = new(JetStreamUsage)
+		**out = **in
+	}
+	in.ObservedAt.DeepCopyInto(&out.ObservedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AccountUsage.
+func (in *AccountUsage) DeepCopy() *AccountUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(AccountUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BootstrapResourceStatus) DeepCopyInto(out *BootstrapResourceStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BootstrapResourceStatus.
+func (in *BootstrapResourceStatus) DeepCopy() *BootstrapResourceStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BootstrapResourceStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClaimsSummary) DeepCopyInto(out *ClaimsSummary) {
+	*out = *in
+	in.IssuedAt.DeepCopyInto(&out.IssuedAt)
+	if in.Expires != nil {
+		in, out := &in.Expires, &out.Expires
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClaimsSummary.
+func (in *ClaimsSummary) DeepCopy() *ClaimsSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(ClaimsSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Identity) DeepCopyInto(out *Identity) {
 	*out = *in
@@ -325,6 +485,21 @@ func (in *JetStreamLimits) DeepCopy() *JetStreamLimits {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *JetStreamUsage) DeepCopyInto(out *JetStreamUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JetStreamUsage.
+func (in *JetStreamUsage) DeepCopy() *JetStreamUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(JetStreamUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KeyPair) DeepCopyInto(out *KeyPair) {
 	*out = *in
@@ -413,12 +588,41 @@ func (in *Operator) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorBootstrap) DeepCopyInto(out *OperatorBootstrap) {
+	*out = *in
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorBootstrap.
+func (in *OperatorBootstrap) DeepCopy() *OperatorBootstrap {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorBootstrap)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorLimits) DeepCopyInto(out *OperatorLimits) {
 	*out = *in
 	in.Nats.DeepCopyInto(&out.Nats)
 	in.Account.DeepCopyInto(&out.Account)
 	out.JetStream = in.JetStream
+	if in.TieredJetStream != nil {
+		in, out := &in.TieredJetStream, &out.TieredJetStream
+		*out = make(map[string]JetStreamLimits, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorLimits.
@@ -463,6 +667,22 @@ func (in *OperatorList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorOfflineIdentity) DeepCopyInto(out *OperatorOfflineIdentity) {
+	*out = *in
+	in.JWTSecretRef.DeepCopyInto(&out.JWTSecretRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorOfflineIdentity.
+func (in *OperatorOfflineIdentity) DeepCopy() *OperatorOfflineIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorOfflineIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorRef) DeepCopyInto(out *OperatorRef) {
 	*out = *in
@@ -478,9 +698,35 @@ func (in *OperatorRef) DeepCopy() *OperatorRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OperatorServerStatus) DeepCopyInto(out *OperatorServerStatus) {
+	*out = *in
+	in.ObservedAt.DeepCopyInto(&out.ObservedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorServerStatus.
+func (in *OperatorServerStatus) DeepCopy() *OperatorServerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(OperatorServerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *OperatorSpec) DeepCopyInto(out *OperatorSpec) {
 	*out = *in
+	if in.ExistingSeedSecretRef != nil {
+		in, out := &in.ExistingSeedSecretRef, &out.ExistingSeedSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.OfflineIdentity != nil {
+		in, out := &in.OfflineIdentity, &out.OfflineIdentity
+		*out = new(OperatorOfflineIdentity)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.AccountsNamespaceSelector != nil {
 		in, out := &in.AccountsNamespaceSelector, &out.AccountsNamespaceSelector
 		*out = new(v1.LabelSelector)
@@ -507,6 +753,21 @@ func (in *OperatorSpec) DeepCopyInto(out *OperatorSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = new(OperatorBootstrap)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UsageRefreshInterval != nil {
+		in, out := &in.UsageRefreshInterval, &out.UsageRefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorSpec.
@@ -538,6 +799,21 @@ func (in *OperatorStatus) DeepCopyInto(out *OperatorStatus) {
 		*out = new(KeyPairReference)
 		**out = **in
 	}
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = new(ClaimsSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bootstrap != nil {
+		in, out := &in.Bootstrap, &out.Bootstrap
+		*out = make([]BootstrapResourceStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.Server != nil {
+		in, out := &in.Server, &out.Server
+		*out = new(OperatorServerStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OperatorStatus.
@@ -591,6 +867,96 @@ func (in *RespPermission) DeepCopy() *RespPermission {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountPolicy) DeepCopyInto(out *ServiceAccountPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountPolicy.
+func (in *ServiceAccountPolicy) DeepCopy() *ServiceAccountPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceAccountPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountPolicyList) DeepCopyInto(out *ServiceAccountPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ServiceAccountPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountPolicyList.
+func (in *ServiceAccountPolicyList) DeepCopy() *ServiceAccountPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ServiceAccountPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceAccountPolicySpec) DeepCopyInto(out *ServiceAccountPolicySpec) {
+	*out = *in
+	if in.ServiceAccountNames != nil {
+		in, out := &in.ServiceAccountNames, &out.ServiceAccountNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	out.AccountRef = in.AccountRef
+	if in.Permissions != nil {
+		in, out := &in.Permissions, &out.Permissions
+		*out = new(UserPermissions)
+		(*in).DeepCopyInto(*out)
+	}
+	in.Limits.DeepCopyInto(&out.Limits)
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceAccountPolicySpec.
+func (in *ServiceAccountPolicySpec) DeepCopy() *ServiceAccountPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceAccountPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *SigningKey) DeepCopyInto(out *SigningKey) {
 	*out = *in
@@ -822,6 +1188,29 @@ func (in *User) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UserConnections) DeepCopyInto(out *UserConnections) {
+	*out = *in
+	if in.LastConnectedAt != nil {
+		in, out := &in.LastConnectedAt, &out.LastConnectedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.LastDisconnectedAt != nil {
+		in, out := &in.LastDisconnectedAt, &out.LastDisconnectedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserConnections.
+func (in *UserConnections) DeepCopy() *UserConnections {
+	if in == nil {
+		return nil
+	}
+	out := new(UserConnections)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UserLimits) DeepCopyInto(out *UserLimits) {
 	*out = *in
@@ -906,6 +1295,11 @@ func (in *UserPermissions) DeepCopy() *UserPermissions {
 func (in *UserSpec) DeepCopyInto(out *UserSpec) {
 	*out = *in
 	out.Issuer = in.Issuer
+	if in.ExistingSeedSecretRef != nil {
+		in, out := &in.ExistingSeedSecretRef, &out.ExistingSeedSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Permissions != nil {
 		in, out := &in.Permissions, &out.Permissions
 		*out = new(UserPermissions)
@@ -917,6 +1311,16 @@ func (in *UserSpec) DeepCopyInto(out *UserSpec) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.AllowedConnectionTypes != nil {
+		in, out := &in.AllowedConnectionTypes, &out.AllowedConnectionTypes
+		*out = make([]ConnectionType, len(*in))
+		copy(*out, *in)
+	}
+	if in.Tags != nil {
+		in, out := &in.Tags, &out.Tags
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserSpec.
@@ -943,6 +1347,16 @@ func (in *UserStatus) DeepCopyInto(out *UserStatus) {
 		*out = new(InferredObjectReference)
 		**out = **in
 	}
+	if in.Claims != nil {
+		in, out := &in.Claims, &out.Claims
+		*out = new(ClaimsSummary)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Connections != nil {
+		in, out := &in.Connections, &out.Connections
+		*out = new(UserConnections)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UserStatus.
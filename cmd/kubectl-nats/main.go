@@ -0,0 +1,272 @@
+/*
+MIT License
+
+Copyright (c) 2024 Versori Ltd
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+*/
+
+// Command kubectl-nats is a kubectl plugin for inspecting the resources managed by the NATS account operator and
+// fetching User credentials. Install it by placing the binary on your PATH, then run it as 'kubectl nats'.
+//
+// Usage:
+//
+//	kubectl nats tree [flags]
+//	kubectl nats claims <kind> <name> [flags]
+//	kubectl nats creds <user> [flags]
+//	kubectl nats status <kind> <name> [flags]
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"k8s.io/client-go/kubernetes"
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned"
+	"github.com/versori-oss/nats-account-operator/pkg/kubectlnats"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "tree":
+		err = runTree(os.Args[2:])
+	case "claims":
+		err = runClaims(os.Args[2:])
+	case "creds":
+		err = runCreds(os.Args[2:])
+	case "status":
+		err = runStatus(os.Args[2:])
+	case "-h", "--help", "help":
+		usage()
+
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprint(os.Stderr, `Usage: kubectl nats <command> [flags]
+
+Commands:
+  tree                     list the Operator -> Account -> User tree with the readiness of each resource
+  claims <kind> <name>     print the decoded JWT claims of an Operator, Account or User
+  creds <user>             write the creds file of a User locally
+  status <kind> <name>     summarise the conditions of a resource to show why it is not Ready
+
+Kinds may be given as operator, account, user or signingkey, their plurals or short names, and <kind> <name> may also
+be written as <kind>/<name>.
+
+Run 'kubectl nats <command> -h' for the flags of each command, which include the standard kubeconfig flags.
+`)
+}
+
+// config holds the kubeconfig flags shared by every command.
+type config struct {
+	loadingRules *clientcmd.ClientConfigLoadingRules
+	overrides    *clientcmd.ConfigOverrides
+}
+
+func newFlagSet(name string) (*pflag.FlagSet, *config) {
+	cfg := &config{
+		loadingRules: clientcmd.NewDefaultClientConfigLoadingRules(),
+		overrides:    &clientcmd.ConfigOverrides{},
+	}
+
+	fs := pflag.NewFlagSet(name, pflag.ExitOnError)
+	fs.StringVar(&cfg.loadingRules.ExplicitPath, clientcmd.RecommendedConfigPathFlag, "", "Path to the kubeconfig file to use.")
+	clientcmd.BindOverrideFlags(cfg.overrides, fs, clientcmd.RecommendedConfigOverrideFlags(""))
+
+	return fs, cfg
+}
+
+// plugin returns the Plugin and the namespace to use, which is the --namespace flag or the namespace of the current
+// kubeconfig context.
+func (c *config) plugin() (*kubectlnats.Plugin, string, error) {
+	clientConfig := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(c.loadingRules, c.overrides)
+
+	namespace, _, err := clientConfig.Namespace()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to determine namespace: %w", err)
+	}
+
+	restConfig, err := clientConfig.ClientConfig()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	core, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	accounts, err := versioned.NewForConfig(restConfig)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create accounts client: %w", err)
+	}
+
+	return kubectlnats.New(accounts.AccountsV1alpha1(), core.CoreV1()), namespace, nil
+}
+
+// kindAndName parses the <kind> <name> or <kind>/<name> arguments of a command.
+func kindAndName(args []string) (kubectlnats.Kind, string, error) {
+	if len(args) == 1 {
+		args = strings.SplitN(args[0], "/", 2)
+	}
+
+	if len(args) != 2 {
+		return "", "", fmt.Errorf("expected <kind> <name> or <kind>/<name>")
+	}
+
+	kind, err := kubectlnats.ParseKind(args[0])
+	if err != nil {
+		return "", "", err
+	}
+
+	return kind, args[1], nil
+}
+
+func runTree(args []string) error {
+	var allNamespaces bool
+
+	fs, cfg := newFlagSet("tree")
+	fs.BoolVarP(&allNamespaces, "all-namespaces", "A", false, "List resources across all namespaces.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	plugin, namespace, err := cfg.plugin()
+	if err != nil {
+		return err
+	}
+
+	if allNamespaces {
+		namespace = ""
+	}
+
+	return plugin.Tree(context.Background(), os.Stdout, namespace)
+}
+
+func runClaims(args []string) error {
+	fs, cfg := newFlagSet("claims")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	kind, name, err := kindAndName(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	plugin, namespace, err := cfg.plugin()
+	if err != nil {
+		return err
+	}
+
+	return plugin.Claims(context.Background(), os.Stdout, kind, namespace, name)
+}
+
+func runCreds(args []string) error {
+	var output string
+
+	fs, cfg := newFlagSet("creds")
+	fs.StringVarP(&output, "output", "o", "", "The file to write the creds to, defaults to <user>.creds, '-' writes to stdout.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		return fmt.Errorf("expected <user>")
+	}
+
+	name := strings.TrimPrefix(fs.Arg(0), "user/")
+
+	plugin, namespace, err := cfg.plugin()
+	if err != nil {
+		return err
+	}
+
+	creds, err := plugin.Creds(context.Background(), namespace, name)
+	if err != nil {
+		return err
+	}
+
+	if output == "-" {
+		_, err = os.Stdout.Write(creds)
+
+		return err
+	}
+
+	if output == "" {
+		output = name + ".creds"
+	}
+
+	if err := os.WriteFile(output, creds, 0o600); err != nil {
+		return fmt.Errorf("failed to write creds: %w", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "wrote creds for user %s/%s to %s\n", namespace, name, output)
+
+	return nil
+}
+
+func runStatus(args []string) error {
+	fs, cfg := newFlagSet("status")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	kind, name, err := kindAndName(fs.Args())
+	if err != nil {
+		return err
+	}
+
+	plugin, namespace, err := cfg.plugin()
+	if err != nil {
+		return err
+	}
+
+	return plugin.Status(context.Background(), os.Stdout, kind, namespace, name)
+}
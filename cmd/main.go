@@ -26,11 +26,16 @@ SOFTWARE.
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	"go.uber.org/zap/zapcore"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
@@ -40,14 +45,17 @@ import (
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	accountsv1alpha1 "github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/internal/authcallout"
 	accountscontroller "github.com/versori-oss/nats-account-operator/internal/controller/accounts"
-	"github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned"
+	"github.com/versori-oss/nats-account-operator/internal/tracing"
 	"github.com/versori-oss/nats-account-operator/pkg/nsc"
 	// +kubebuilder:scaffold:imports
 )
@@ -70,6 +78,20 @@ func main() {
 	var probeAddr string
 	var secureMetrics bool
 	var enableHTTP2 bool
+	var authCalloutURL string
+	var authCalloutAccount string
+	var authCalloutUser string
+	var authCalloutAudiences string
+	var authCalloutTTL time.Duration
+	var operatorConcurrency int
+	var accountConcurrency int
+	var signingKeyConcurrency int
+	var userConcurrency int
+	var accountServerRateLimit float64
+	var accountServerBurst int
+	var connectionFlushInterval time.Duration
+	var serverProbeInterval time.Duration
+	var tracingOpts tracing.Options
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
 	flag.BoolVar(&enableLeaderElection, "leader-elect", false,
@@ -79,6 +101,46 @@ func main() {
 		"If set the metrics endpoint is served securely")
 	flag.BoolVar(&enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.StringVar(&authCalloutURL, "auth-callout-url", "",
+		"The NATS server URL the embedded auth callout service connects to. "+
+			"The service is disabled unless this is set.")
+	flag.StringVar(&authCalloutAccount, "auth-callout-account", "",
+		"The Account, in the form namespace/name, with auth callout configured.")
+	flag.StringVar(&authCalloutUser, "auth-callout-user", "",
+		"The auth User, in the form namespace/name, which the auth callout service connects as.")
+	flag.StringVar(&authCalloutAudiences, "auth-callout-token-audiences", "",
+		"Comma separated audiences which ServiceAccount tokens must be issued for, "+
+			"defaults to the API server audience.")
+	flag.DurationVar(&authCalloutTTL, "auth-callout-default-ttl", time.Hour,
+		"The lifetime of User JWTs issued by the auth callout service when not set by the ServiceAccountPolicy.")
+	flag.IntVar(&operatorConcurrency, "operator-concurrency", 1,
+		"The maximum number of Operators reconciled concurrently.")
+	flag.IntVar(&accountConcurrency, "account-concurrency", 1,
+		"The maximum number of Accounts reconciled concurrently.")
+	flag.IntVar(&signingKeyConcurrency, "signing-key-concurrency", 1,
+		"The maximum number of SigningKeys reconciled concurrently.")
+	flag.IntVar(&userConcurrency, "user-concurrency", 1,
+		"The maximum number of Users reconciled concurrently.")
+	flag.Float64Var(&accountServerRateLimit, "account-server-rate-limit", 10,
+		"The maximum number of JWT pushes and deletes per second sent to the account server of each Operator. "+
+			"Set to 0 to disable rate limiting.")
+	flag.IntVar(&accountServerBurst, "account-server-burst", 20,
+		"The maximum burst of JWT pushes and deletes sent to the account server of each Operator.")
+	flag.DurationVar(&connectionFlushInterval, "user-connection-flush-interval",
+		accountscontroller.DefaultConnectionFlushInterval,
+		"How often User connect and disconnect events are written to User status. Set to 0 to disable tracking.")
+	flag.DurationVar(&serverProbeInterval, "server-probe-interval", accountscontroller.DefaultServerProbeInterval,
+		"How often the account server of each Operator is probed for the ServerReachable condition and the "+
+			"readiness check. Set to 0 to disable probing.")
+	flag.StringVar(&tracingOpts.Exporter, "tracing-exporter", tracing.ExporterNone,
+		"The OpenTelemetry trace exporter, one of \"otlp\" or \"stdout\". Tracing is disabled when empty.")
+	flag.StringVar(&tracingOpts.Endpoint, "tracing-otlp-endpoint", "",
+		"The host:port of the OTLP gRPC collector. Defaults to the OTEL_EXPORTER_OTLP_ENDPOINT environment variable, "+
+			"or localhost:4317.")
+	flag.BoolVar(&tracingOpts.Insecure, "tracing-otlp-insecure", false,
+		"If set, TLS is disabled when connecting to the OTLP collector.")
+	flag.Float64Var(&tracingOpts.SampleRatio, "tracing-sample-ratio", 1,
+		"The fraction of reconciles which are traced, between 0 and 1.")
 	opts := zap.Options{
 		Development:     true,
 		Level:           zapcore.InfoLevel,
@@ -89,6 +151,14 @@ func main() {
 
 	ctrl.SetLogger(zap.New(zap.UseFlagOptions(&opts)))
 
+	ctx := ctrl.SetupSignalHandler()
+
+	shutdownTracing, err := tracing.Setup(ctx, tracingOpts)
+	if err != nil {
+		setupLog.Error(err, "unable to set up tracing")
+		os.Exit(1)
+	}
+
 	// if the enable-http2 flag is false (the default), http/2 should be disabled
 	// due to its vulnerabilities. More specifically, disabling http/2 will
 	// prevent from being vulnerable to the HTTP/2 Stream Cancellation and
@@ -111,6 +181,9 @@ func main() {
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
 		Scheme: scheme,
+		Cache: cache.Options{
+			ByObject: accountscontroller.CacheByObject(),
+		},
 		Metrics: metricsserver.Options{
 			BindAddress:   metricsAddr,
 			SecureServing: secureMetrics,
@@ -143,61 +216,98 @@ func main() {
 		os.Exit(1)
 	}
 
-	coreV1CS := clientset.CoreV1()
+	if err := accountscontroller.SetupFieldIndexes(context.Background(), mgr.GetFieldIndexer()); err != nil {
+		setupLog.Error(err, "unable to set up field indexes")
+		os.Exit(1)
+	}
+
+	k8sClient := accountscontroller.NewClient(mgr.GetClient(), mgr.GetAPIReader())
 
-	accountsCS, err := versioned.NewForConfigAndClient(mgr.GetConfig(), mgr.GetHTTPClient())
-	if err != nil {
-		setupLog.Error(err, "unable to create accounts Clientset")
+	if err := metrics.Registry.Register(accountscontroller.NewResourceCollector(mgr.GetClient())); err != nil {
+		setupLog.Error(err, "unable to register resource metrics collector")
 		os.Exit(1)
 	}
+	systemCredentials := nsc.NewSystemAccountLoader(k8sClient)
+	accountServerLimiter := accountscontroller.NewOperatorRateLimiter(accountServerRateLimit, accountServerBurst)
 
-	accountsV1Alpha1 := accountsCS.AccountsV1alpha1()
+	newBase := func(concurrency int) *accountscontroller.BaseReconciler {
+		return &accountscontroller.BaseReconciler{
+			Client:                  k8sClient,
+			Scheme:                  mgr.GetScheme(),
+			AccountServer:           nsc.NATSAccountServer{},
+			SystemCredentials:       systemCredentials,
+			AccountServerLimiter:    accountServerLimiter,
+			MaxConcurrentReconciles: concurrency,
+		}
+	}
 
 	if err = (&accountscontroller.OperatorReconciler{
-		BaseReconciler: &accountscontroller.BaseReconciler{
-			Client:           mgr.GetClient(),
-			Scheme:           mgr.GetScheme(),
-			CoreV1:           coreV1CS,
-			AccountsV1Alpha1: accountsV1Alpha1,
-		},
+		BaseReconciler: newBase(operatorConcurrency),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Operator")
 		os.Exit(1)
 	}
 	if err = (&accountscontroller.AccountReconciler{
-		BaseReconciler: &accountscontroller.BaseReconciler{
-			Client:           mgr.GetClient(),
-			Scheme:           mgr.GetScheme(),
-			CoreV1:           coreV1CS,
-			AccountsV1Alpha1: accountsV1Alpha1,
-		},
-		SysAccountLoader: nsc.NewSystemAccountLoader(accountsV1Alpha1, coreV1CS),
+		BaseReconciler: newBase(accountConcurrency),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Account")
 		os.Exit(1)
 	}
 	if err = (&accountscontroller.SigningKeyReconciler{
-		Client:           mgr.GetClient(),
-		Scheme:           mgr.GetScheme(),
-		CoreV1:           coreV1CS,
-		AccountsV1Alpha1: accountsV1Alpha1,
+		BaseReconciler: newBase(signingKeyConcurrency),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "SigningKey")
 		os.Exit(1)
 	}
 	if err = (&accountscontroller.UserReconciler{
-		BaseReconciler: &accountscontroller.BaseReconciler{
-			Client:           mgr.GetClient(),
-			Scheme:           mgr.GetScheme(),
-			CoreV1:           coreV1CS,
-			AccountsV1Alpha1: accountsV1Alpha1,
-		},
+		BaseReconciler: newBase(userConcurrency),
 	}).SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "User")
 		os.Exit(1)
 	}
 	// +kubebuilder:scaffold:builder
 
+	if err := mgr.Add(accountscontroller.NewUsagePoller(newBase(0))); err != nil {
+		setupLog.Error(err, "unable to set up account usage poller")
+		os.Exit(1)
+	}
+	if connectionFlushInterval > 0 {
+		if err := mgr.Add(accountscontroller.NewConnectionTracker(newBase(0), connectionFlushInterval)); err != nil {
+			setupLog.Error(err, "unable to set up user connection tracker")
+			os.Exit(1)
+		}
+	}
+
+	if authCalloutURL != "" {
+		account, err := parseNamespacedName(authCalloutAccount)
+		if err != nil {
+			setupLog.Error(err, "invalid --auth-callout-account")
+			os.Exit(1)
+		}
+
+		user, err := parseNamespacedName(authCalloutUser)
+		if err != nil {
+			setupLog.Error(err, "invalid --auth-callout-user")
+			os.Exit(1)
+		}
+
+		var audiences []string
+		if authCalloutAudiences != "" {
+			audiences = strings.Split(authCalloutAudiences, ",")
+		}
+
+		if err := mgr.Add(authcallout.NewResponder(k8sClient, clientset.AuthenticationV1().TokenReviews(), authcallout.Options{
+			URL:        authCalloutURL,
+			Account:    account,
+			User:       user,
+			Audiences:  audiences,
+			DefaultTTL: authCalloutTTL,
+		})); err != nil {
+			setupLog.Error(err, "unable to set up auth callout responder")
+			os.Exit(1)
+		}
+	}
+
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
 		setupLog.Error(err, "unable to set up health check")
 		os.Exit(1)
@@ -206,10 +316,38 @@ func main() {
 		setupLog.Error(err, "unable to set up ready check")
 		os.Exit(1)
 	}
+	if serverProbeInterval > 0 {
+		prober := accountscontroller.NewServerProber(newBase(0), serverProbeInterval, mgr.Elected())
+		if err := mgr.Add(prober); err != nil {
+			setupLog.Error(err, "unable to set up account server prober")
+			os.Exit(1)
+		}
+		if err := mgr.AddReadyzCheck("account-servers", prober.Checker); err != nil {
+			setupLog.Error(err, "unable to set up account server ready check")
+			os.Exit(1)
+		}
+	}
 
 	setupLog.Info("starting manager")
-	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
 		os.Exit(1)
 	}
+
+	// ctx is cancelled by now, so flush the remaining spans with a fresh deadline
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		setupLog.Error(err, "unable to flush traces")
+	}
+}
+
+func parseNamespacedName(s string) (types.NamespacedName, error) {
+	namespace, name, ok := strings.Cut(s, "/")
+	if !ok || namespace == "" || name == "" {
+		return types.NamespacedName{}, fmt.Errorf("expected namespace/name, got %q", s)
+	}
+
+	return types.NamespacedName{Namespace: namespace, Name: name}, nil
 }
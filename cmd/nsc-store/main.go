@@ -0,0 +1,221 @@
+/*
+MIT License
+
+Copyright (c) 2024 Versori Ltd
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+*/
+
+// Command nsc-store migrates between an nsc store and the resources managed by the NATS account operator.
+//
+// Usage:
+//
+//	nsc-store import [flags]
+//	nsc-store export [flags]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"k8s.io/client-go/kubernetes"
+	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
+	_ "k8s.io/client-go/plugin/pkg/client/auth"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+
+	"github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned"
+	"github.com/versori-oss/nats-account-operator/pkg/nscstore"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	var err error
+
+	switch os.Args[1] {
+	case "import":
+		err = runImport(os.Args[2:])
+	case "export":
+		err = runExport(os.Args[2:])
+	case "-h", "-help", "--help", "help":
+		usage()
+
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	fmt.Fprintf(os.Stderr, `Usage: nsc-store <command> [flags]
+
+Commands:
+  import    convert an nsc store into Operator, Account, SigningKey and User manifests and seed Secrets
+  export    write the Operators, Accounts, Users and SigningKeys in a cluster to an nsc store
+
+Run 'nsc-store <command> -h' for the flags of each command.
+`)
+}
+
+func runImport(args []string) error {
+	defaults, err := nscstore.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	var (
+		store  nscstore.Store
+		opts   nscstore.ImportOptions
+		output string
+		strict bool
+	)
+
+	fs := flag.NewFlagSet("import", flag.ExitOnError)
+	fs.StringVar(&store.StoresDir, "stores", defaults.StoresDir, "The nsc stores directory.")
+	fs.StringVar(&store.KeysDir, "keys", defaults.KeysDir, "The nsc keystore directory, defaults to $NKEYS_PATH.")
+	fs.StringVar(&opts.Namespace, "namespace", "default", "The namespace to create the resources in.")
+	fs.StringVar(&opts.Operator, "operator", "", "Only import the named operator, all operators are imported when empty.")
+	fs.StringVar(&output, "output", "-", "The file to write the manifests to, '-' writes to stdout.")
+	fs.BoolVar(&strict, "strict", false, "Fail without writing manifests if any claims cannot be represented.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	result, err := nscstore.Import(store, opts)
+	if err != nil {
+		return err
+	}
+
+	for _, w := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+
+	if strict && len(result.Warnings) > 0 {
+		return fmt.Errorf("%d claims cannot be represented", len(result.Warnings))
+	}
+
+	var w io.Writer = os.Stdout
+
+	if output != "-" {
+		f, err := os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0o600)
+		if err != nil {
+			return fmt.Errorf("failed to open output: %w", err)
+		}
+
+		defer f.Close()
+
+		w = f
+	}
+
+	for _, obj := range result.Objects {
+		data, err := yaml.Marshal(obj)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s: %w", obj.GetName(), err)
+		}
+
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func runExport(args []string) error {
+	defaults, err := nscstore.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	var (
+		store      nscstore.Store
+		opts       nscstore.ExportOptions
+		kubeconfig string
+		kubectx    string
+	)
+
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	fs.StringVar(&store.StoresDir, "stores", defaults.StoresDir, "The nsc stores directory to write to.")
+	fs.StringVar(&store.KeysDir, "keys", defaults.KeysDir, "The nsc keystore directory to write to, defaults to $NKEYS_PATH.")
+	fs.StringVar(&opts.Namespace, "namespace", "", "Only export resources in this namespace, all namespaces are exported when empty.")
+	fs.BoolVar(&opts.OmitSeeds, "omit-seeds", false, "Do not write seeds or creds, so the export can be shared with read-only auditors.")
+	fs.BoolVar(&opts.Force, "force", false, "Write into a stores directory which is not empty, overwriting entities with the same name.")
+	fs.StringVar(&kubeconfig, "kubeconfig", "", "Path to the kubeconfig file, defaults to $KUBECONFIG or ~/.kube/config.")
+	fs.StringVar(&kubectx, "context", "", "The kubeconfig context to use.")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = kubeconfig
+
+	config, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		loadingRules,
+		&clientcmd.ConfigOverrides{CurrentContext: kubectx},
+	).ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	core, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create kubernetes client: %w", err)
+	}
+
+	accounts, err := versioned.NewForConfig(config)
+	if err != nil {
+		return fmt.Errorf("failed to create accounts client: %w", err)
+	}
+
+	result, err := nscstore.NewExporter(accounts.AccountsV1alpha1(), core.CoreV1()).Export(context.Background(), store, opts)
+	if errors.Is(err, nscstore.ErrStoreNotEmpty) {
+		return fmt.Errorf("%w, choose an empty --stores directory or pass --force to overwrite it", err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	for _, w := range result.Warnings {
+		fmt.Fprintf(os.Stderr, "warning: %s\n", w)
+	}
+
+	fmt.Fprintf(os.Stderr, "exported %d operators, %d accounts, %d users and %d signing keys to %s\n",
+		result.Operators, result.Accounts, result.Users, result.SigningKeys, store.StoresDir)
+
+	return nil
+}
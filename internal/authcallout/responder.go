@@ -0,0 +1,540 @@
+package authcallout
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/helpers"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+)
+
+const (
+	// SubjectAuthRequest is the subject the NATS server publishes auth callout requests to.
+	SubjectAuthRequest = "$SYS.REQ.USER.AUTH"
+
+	// HeaderServerXKey is the header containing the server's curve public key when requests are encrypted.
+	HeaderServerXKey = "Nats-Server-Xkey"
+
+	serviceAccountUsernamePrefix = "system:serviceaccount:"
+
+	queueGroup = "nats-account-operator"
+
+	// connectRetryMin and connectRetryMax bound the exponential backoff between attempts to connect, such as while
+	// the auth user's credentials Secret has not been created yet.
+	connectRetryMin = time.Second
+	connectRetryMax = time.Minute
+)
+
+var ErrNotAuthorized = errors.New("not authorized")
+
+// errCredentialsChanged is returned by serve when the auth user's credentials change, so the responder reconnects with
+// the new credentials.
+var errCredentialsChanged = errors.New("auth callout user credentials changed")
+
+//+kubebuilder:rbac:groups=accounts.nats.io,resources=serviceaccountpolicies,verbs=get;list;watch
+//+kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+
+// TokenReviewer is satisfied by the TokenReviews client in k8s.io/client-go/kubernetes/typed/authentication/v1.
+type TokenReviewer interface {
+	Create(ctx context.Context, tokenReview *authenticationv1.TokenReview, opts metav1.CreateOptions) (*authenticationv1.TokenReview, error)
+}
+
+type Options struct {
+	// URL is the NATS server URL to connect to.
+	URL string
+
+	// Account is the Account with auth callout configured, requests are signed with its identity key.
+	Account types.NamespacedName
+
+	// User is one of the Account's auth users, its credentials Secret is used to connect to NATS.
+	User types.NamespacedName
+
+	// Audiences are the audiences which presented ServiceAccount tokens must be issued for. When empty, the API
+	// server's default audience is used.
+	Audiences []string
+
+	// DefaultTTL is the lifetime of issued User JWTs when the matching ServiceAccountPolicy does not specify one.
+	DefaultTTL time.Duration
+
+	// CredentialsCheckInterval is how often the auth user's credentials Secret is checked for changes, the responder
+	// reconnects with the new credentials when they change. Defaults to 30s.
+	CredentialsCheckInterval time.Duration
+
+	// NatsOptions are passed to nats.Connect.
+	NatsOptions []nats.Option
+}
+
+// Responder is an auth callout service which authenticates clients presenting a Kubernetes ServiceAccount token and
+// issues short-lived User JWTs according to the ServiceAccountPolicy matching the ServiceAccount.
+type Responder struct {
+	client        client.Client
+	tokenReviewer TokenReviewer
+	opts          Options
+	logger        logr.Logger
+}
+
+var _ manager.Runnable = (*Responder)(nil)
+var _ manager.LeaderElectionRunnable = (*Responder)(nil)
+
+func NewResponder(c client.Client, tokenReviewer TokenReviewer, opts Options) *Responder {
+	if opts.DefaultTTL == 0 {
+		opts.DefaultTTL = time.Hour
+	}
+
+	if opts.CredentialsCheckInterval == 0 {
+		opts.CredentialsCheckInterval = 30 * time.Second
+	}
+
+	return &Responder{
+		client:        c,
+		tokenReviewer: tokenReviewer,
+		opts:          opts,
+		logger:        log.Log.WithName("auth-callout"),
+	}
+}
+
+// NeedLeaderElection returns false since every replica can respond to auth requests, the subscription uses a queue
+// group so each request is only handled once.
+func (r *Responder) NeedLeaderElection() bool {
+	return false
+}
+
+// Start connects to NATS and responds to auth callout requests until the context is cancelled. Failures to connect,
+// such as before the auth user's credentials exist on a fresh install, are retried with backoff rather than stopping
+// the manager.
+func (r *Responder) Start(ctx context.Context) error {
+	ctx = log.IntoContext(ctx, r.logger)
+
+	delay := connectRetryMin
+
+	for {
+		subscribed, err := r.serve(ctx)
+
+		switch {
+		case ctx.Err() != nil:
+			return nil
+		case errors.Is(err, errCredentialsChanged):
+			r.logger.Info("auth callout user credentials changed, reconnecting")
+
+			delay = connectRetryMin
+
+			continue
+		case subscribed:
+			delay = connectRetryMin
+		}
+
+		r.logger.Error(err, "auth callout responder failed, retrying", "delay", delay)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(delay):
+		}
+
+		delay = min(2*delay, connectRetryMax)
+	}
+}
+
+// serve connects as the auth user and responds to auth callout requests until the context is cancelled, or until the
+// auth user's credentials change. subscribed is true if requests were being served before an error was returned.
+func (r *Responder) serve(ctx context.Context) (subscribed bool, err error) {
+	ujwt, seed, err := r.loadUserCredentials(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	options := append(make([]nats.Option, 0, len(r.opts.NatsOptions)+2), r.opts.NatsOptions...)
+	options = append(options,
+		nats.UserJWTAndSeed(ujwt, string(seed)),
+		nats.Name("nats-account-operator-auth-callout"),
+	)
+
+	conn, err := nats.Connect(r.opts.URL, options...)
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to nats: %w", err)
+	}
+	defer conn.Close()
+
+	sub, err := conn.QueueSubscribe(SubjectAuthRequest, queueGroup, func(msg *nats.Msg) {
+		r.handle(ctx, msg)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to subscribe to %s: %w", SubjectAuthRequest, err)
+	}
+
+	defer func() {
+		if err := sub.Drain(); err != nil {
+			r.logger.Error(err, "failed to drain auth callout subscription")
+		}
+	}()
+
+	r.logger.Info("auth callout responder started", "account", r.opts.Account, "user", r.opts.User)
+
+	ticker := time.NewTicker(r.opts.CredentialsCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return true, nil
+		case <-ticker.C:
+			newJWT, newSeed, err := r.loadUserCredentials(ctx)
+			if err != nil {
+				// keep serving with the existing connection, the credentials may still be valid
+				r.logger.Error(err, "failed to check auth callout user credentials")
+
+				continue
+			}
+
+			if newJWT != ujwt || !bytes.Equal(newSeed, seed) {
+				return true, errCredentialsChanged
+			}
+		}
+	}
+}
+
+func (r *Responder) handle(ctx context.Context, msg *nats.Msg) {
+	account := new(v1alpha1.Account)
+	if err := r.client.Get(ctx, r.opts.Account, account); err != nil {
+		r.logger.Error(err, "failed to get auth callout account")
+
+		return
+	}
+
+	accountKP, err := r.loadSeed(ctx, account.Namespace, account.Status.KeyPair, nkeys.PrefixByteAccount)
+	if err != nil {
+		r.logger.Error(err, "failed to load auth callout account seed")
+
+		return
+	}
+
+	var xkp nkeys.KeyPair
+
+	serverXKey := msg.Header.Get(HeaderServerXKey)
+	if account.Status.Authorization != nil && account.Status.Authorization.XKey != nil {
+		xkp, err = r.loadSeed(ctx, account.Namespace, account.Status.Authorization.XKey, nkeys.PrefixByteCurve)
+		if err != nil {
+			r.logger.Error(err, "failed to load auth callout xkey seed")
+
+			return
+		}
+	}
+
+	data := msg.Data
+	if xkp != nil && serverXKey != "" {
+		data, err = xkp.Open(msg.Data, serverXKey)
+		if err != nil {
+			r.logger.Error(err, "failed to decrypt auth request")
+
+			return
+		}
+	}
+
+	req, err := jwt.DecodeAuthorizationRequestClaims(string(data))
+	if err != nil {
+		r.logger.Error(err, "failed to decode auth request")
+
+		return
+	}
+
+	resp := jwt.NewAuthorizationResponseClaims(req.UserNkey)
+	resp.Audience = req.Server.ID
+
+	ujwt, err := r.authorize(ctx, account, req)
+	if err != nil {
+		r.logger.Info("auth request denied", "client", req.ClientInformation.Host, "reason", err.Error())
+
+		resp.Error = ErrNotAuthorized.Error()
+	} else {
+		resp.Jwt = ujwt
+	}
+
+	token, err := resp.Encode(accountKP)
+	if err != nil {
+		r.logger.Error(err, "failed to encode auth response")
+
+		return
+	}
+
+	payload := []byte(token)
+	if xkp != nil && serverXKey != "" {
+		payload, err = xkp.Seal(payload, serverXKey)
+		if err != nil {
+			r.logger.Error(err, "failed to encrypt auth response")
+
+			return
+		}
+	}
+
+	if err := msg.Respond(payload); err != nil {
+		r.logger.Error(err, "failed to respond to auth request")
+	}
+}
+
+// authorize validates the ServiceAccount token presented in the request and returns a User JWT for the user nkey in
+// the request, signed by the Account referenced by the ServiceAccountPolicy.
+func (r *Responder) authorize(ctx context.Context, account *v1alpha1.Account, req *jwt.AuthorizationRequestClaims) (string, error) {
+	token := req.ConnectOptions.Token
+	if token == "" {
+		token = req.ConnectOptions.Password
+	}
+
+	if token == "" {
+		return "", fmt.Errorf("no token presented")
+	}
+
+	namespace, name, err := r.reviewToken(ctx, token)
+	if err != nil {
+		return "", err
+	}
+
+	policy, err := r.findPolicy(ctx, namespace, name)
+	if err != nil {
+		return "", err
+	}
+
+	target, err := r.resolveTargetAccount(ctx, account, policy)
+	if err != nil {
+		return "", err
+	}
+
+	if err := r.validateAccountSelector(ctx, target, policy); err != nil {
+		return "", err
+	}
+
+	targetKP, err := r.loadSeed(ctx, target.Namespace, target.Status.KeyPair, nkeys.PrefixByteAccount)
+	if err != nil {
+		return "", fmt.Errorf("failed to load account seed: %w", err)
+	}
+
+	ttl := r.opts.DefaultTTL
+	if policy.Spec.TTL != nil {
+		ttl = policy.Spec.TTL.Duration
+	}
+
+	// the User only exists for the duration of the request, it allows reuse of the claim conversion logic.
+	usr := &v1alpha1.User{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      serviceAccountUsernamePrefix + namespace + ":" + name,
+			Namespace: namespace,
+		},
+		Spec: v1alpha1.UserSpec{
+			Permissions: policy.Spec.Permissions,
+			Limits:      policy.Spec.Limits,
+		},
+		Status: v1alpha1.UserStatus{
+			KeyPair: &v1alpha1.KeyPair{PublicKey: req.UserNkey},
+		},
+	}
+
+	_, ujwt, err := nsc.CreateUserClaims(usr, target, targetKP, nsc.WithExpiry(time.Now().Add(ttl)))
+	if err != nil {
+		return "", fmt.Errorf("failed to create user claims: %w", err)
+	}
+
+	return ujwt, nil
+}
+
+// reviewToken validates the token with the Kubernetes API server, returning the namespace and name of the
+// ServiceAccount it belongs to.
+func (r *Responder) reviewToken(ctx context.Context, token string) (namespace, name string, err error) {
+	review, err := r.tokenReviewer.Create(ctx, &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{
+			Token:     token,
+			Audiences: r.opts.Audiences,
+		},
+	}, metav1.CreateOptions{})
+	if err != nil {
+		return "", "", fmt.Errorf("failed to review token: %w", err)
+	}
+
+	if !review.Status.Authenticated {
+		return "", "", fmt.Errorf("token not authenticated: %s", review.Status.Error)
+	}
+
+	username, ok := strings.CutPrefix(review.Status.User.Username, serviceAccountUsernamePrefix)
+	if !ok {
+		return "", "", fmt.Errorf("token does not belong to a service account: %s", review.Status.User.Username)
+	}
+
+	namespace, name, ok = strings.Cut(username, ":")
+	if !ok || namespace == "" || name == "" {
+		return "", "", fmt.Errorf("invalid service account username: %s", review.Status.User.Username)
+	}
+
+	return namespace, name, nil
+}
+
+// findPolicy returns the single ServiceAccountPolicy in the namespace which applies to the named ServiceAccount.
+func (r *Responder) findPolicy(ctx context.Context, namespace, name string) (*v1alpha1.ServiceAccountPolicy, error) {
+	policies := new(v1alpha1.ServiceAccountPolicyList)
+	if err := r.client.List(ctx, policies, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed to list service account policies: %w", err)
+	}
+
+	var matched []v1alpha1.ServiceAccountPolicy
+
+	for _, policy := range policies.Items {
+		for _, saName := range policy.Spec.ServiceAccountNames {
+			if saName == name {
+				matched = append(matched, policy)
+
+				break
+			}
+		}
+	}
+
+	switch len(matched) {
+	case 0:
+		return nil, fmt.Errorf("no service account policy found for %s/%s", namespace, name)
+	case 1:
+		return &matched[0], nil
+	default:
+		return nil, fmt.Errorf("multiple service account policies found for %s/%s", namespace, name)
+	}
+}
+
+// resolveTargetAccount returns the Account referenced by the policy, checking it is either the auth callout Account
+// itself or one of its allowed accounts.
+func (r *Responder) resolveTargetAccount(ctx context.Context, account *v1alpha1.Account, policy *v1alpha1.ServiceAccountPolicy) (*v1alpha1.Account, error) {
+	ref := policy.Spec.AccountRef
+
+	key := types.NamespacedName{Namespace: ref.Namespace, Name: ref.Name}
+	if key.Namespace == "" {
+		key.Namespace = policy.Namespace
+	}
+
+	if key == client.ObjectKeyFromObject(account) {
+		return account, nil
+	}
+
+	target := new(v1alpha1.Account)
+	if err := r.client.Get(ctx, key, target); err != nil {
+		return nil, fmt.Errorf("failed to get account %s: %w", key, err)
+	}
+
+	if target.Status.KeyPair == nil {
+		return nil, fmt.Errorf("account %s does not have a key pair", key)
+	}
+
+	if account.Status.Authorization != nil {
+		for _, allowed := range account.Status.Authorization.AllowedAccounts {
+			if allowed.PublicKey == target.Status.KeyPair.PublicKey {
+				return target, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("account %s is not an allowed account of %s", key, client.ObjectKeyFromObject(account))
+}
+
+// validateAccountSelector applies the same restrictions to the policy as the Account applies to User resources.
+func (r *Responder) validateAccountSelector(ctx context.Context, account *v1alpha1.Account, policy *v1alpha1.ServiceAccountPolicy) error {
+	ns := new(v1.Namespace)
+	if err := r.client.Get(ctx, types.NamespacedName{Name: policy.Namespace}, ns); err != nil {
+		return fmt.Errorf("failed to get policy namespace: %w", err)
+	}
+
+	valid, err := helpers.MatchNamespaceSelector(account, ns, account.Spec.UsersNamespaceSelector)
+	if err != nil {
+		return fmt.Errorf("failed to validate users namespace selector: %w", err)
+	}
+
+	if !valid {
+		return fmt.Errorf("account.spec.usersNamespaceSelector does not match policy namespace")
+	}
+
+	if account.Spec.UsersSelector == nil {
+		return nil
+	}
+
+	ls, err := metav1.LabelSelectorAsSelector(account.Spec.UsersSelector)
+	if err != nil {
+		return fmt.Errorf("failed to parse account.spec.usersSelector: %w", err)
+	}
+
+	if !ls.Matches(labels.Set(policy.Labels)) {
+		return fmt.Errorf("account.spec.usersSelector does not match policy labels")
+	}
+
+	return nil
+}
+
+func (r *Responder) loadUserCredentials(ctx context.Context) (ujwt string, seed []byte, err error) {
+	usr := new(v1alpha1.User)
+	if err := r.client.Get(ctx, r.opts.User, usr); err != nil {
+		return "", nil, fmt.Errorf("failed to get auth callout user: %w", err)
+	}
+
+	secret := new(v1.Secret)
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: usr.Namespace, Name: usr.Spec.CredentialsSecretName}, secret); err != nil {
+		return "", nil, fmt.Errorf("failed to get auth callout user credentials: %w", err)
+	}
+
+	creds, ok := secret.Data[v1alpha1.NatsSecretCredsKey]
+	if !ok {
+		return "", nil, fmt.Errorf("secret %s/%s is invalid, missing field: %s", secret.Namespace, secret.Name, v1alpha1.NatsSecretCredsKey)
+	}
+
+	ujwt, err = jwt.ParseDecoratedJWT(creds)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse user JWT from credentials: %w", err)
+	}
+
+	kp, err := jwt.ParseDecoratedUserNKey(creds)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to parse user seed from credentials: %w", err)
+	}
+
+	seed, err = kp.Seed()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get user seed: %w", err)
+	}
+
+	return ujwt, seed, nil
+}
+
+func (r *Responder) loadSeed(ctx context.Context, namespace string, keyPair *v1alpha1.KeyPair, wantPrefix nkeys.PrefixByte) (nkeys.KeyPair, error) {
+	if keyPair == nil {
+		return nil, fmt.Errorf("key pair is not resolved")
+	}
+
+	secret := new(v1.Secret)
+	if err := r.client.Get(ctx, types.NamespacedName{Namespace: namespace, Name: keyPair.SeedSecretName}, secret); err != nil {
+		return nil, fmt.Errorf("failed to get seed secret: %w", err)
+	}
+
+	seed, ok := secret.Data[keyPair.GetSeedSecretKey()]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s is invalid, missing field: %s", secret.Namespace, secret.Name, keyPair.GetSeedSecretKey())
+	}
+
+	prefix, _, err := nkeys.DecodeSeed(seed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse seed: %w", err)
+	}
+
+	if prefix != wantPrefix {
+		return nil, fmt.Errorf("unexpected seed prefix, wanted %q but got %q", wantPrefix.String(), prefix.String())
+	}
+
+	return nkeys.FromSeed(seed)
+}
@@ -0,0 +1,513 @@
+package authcallout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+const testNamespace = "default"
+
+type fakeTokenReviewer map[string]string
+
+func (f fakeTokenReviewer) Create(_ context.Context, tr *authenticationv1.TokenReview, _ metav1.CreateOptions) (*authenticationv1.TokenReview, error) {
+	username, ok := f[tr.Spec.Token]
+
+	tr.Status.Authenticated = ok
+	tr.Status.User.Username = username
+
+	return tr, nil
+}
+
+type testEnv struct {
+	server  *server.Server
+	objects []client.Object
+
+	calloutPub string
+	targetPub  string
+
+	// rotatedAuthUserPub and rotatedCreds are the credentials of a second auth user, used to rotate the credentials of
+	// the auth user.
+	rotatedAuthUserPub string
+	rotatedCreds       []byte
+
+	// sentinelJWT and sentinelSeed are the bearer credentials clients connect with to trigger auth callout.
+	sentinelJWT  string
+	sentinelSeed string
+}
+
+func mustKeyPair(t *testing.T, create func() (nkeys.KeyPair, error)) (kp nkeys.KeyPair, pub string, seed []byte) {
+	t.Helper()
+
+	kp, err := create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err = kp.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seed, err = kp.Seed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return kp, pub, seed
+}
+
+func seedSecret(name string, seed []byte) *v1.Secret {
+	return &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Data:       map[string][]byte{v1alpha1.NatsSecretSeedKey: seed},
+	}
+}
+
+// newTestEnv starts an in-process nats-server in operator mode with a memory resolver containing an auth callout
+// Account, an allowed target Account and the system Account, and returns the matching Kubernetes objects.
+func newTestEnv(t *testing.T, withXKey bool) *testEnv {
+	t.Helper()
+
+	operatorKP, operatorPub, _ := mustKeyPair(t, nkeys.CreateOperator)
+	_, sysPub, _ := mustKeyPair(t, nkeys.CreateAccount)
+	calloutKP, calloutPub, calloutSeed := mustKeyPair(t, nkeys.CreateAccount)
+	_, targetPub, targetSeed := mustKeyPair(t, nkeys.CreateAccount)
+	_, authUserPub, authUserSeed := mustKeyPair(t, nkeys.CreateUser)
+	_, rotatedAuthUserPub, rotatedAuthUserSeed := mustKeyPair(t, nkeys.CreateUser)
+
+	operatorClaims := jwt.NewOperatorClaims(operatorPub)
+	operatorClaims.SystemAccount = sysPub
+
+	operatorJWT, err := operatorClaims.Encode(operatorKP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opClaims, err := jwt.DecodeOperatorClaims(operatorJWT)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resolver := &server.MemAccResolver{}
+
+	storeAccount := func(claims *jwt.AccountClaims) {
+		ajwt, err := claims.Encode(operatorKP)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := resolver.Store(claims.Subject, ajwt); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	storeAccount(jwt.NewAccountClaims(sysPub))
+	storeAccount(jwt.NewAccountClaims(targetPub))
+
+	calloutClaims := jwt.NewAccountClaims(calloutPub)
+	calloutClaims.Authorization.AuthUsers.Add(authUserPub, rotatedAuthUserPub)
+	calloutClaims.Authorization.AllowedAccounts.Add(targetPub)
+
+	authorizationStatus := &v1alpha1.AccountAuthorizationStatus{
+		AuthUsers: []v1alpha1.KeyPairReference{{
+			InferredObjectReference: v1alpha1.InferredObjectReference{Name: "auth-user"},
+			PublicKey:               authUserPub,
+		}},
+		AllowedAccounts: []v1alpha1.KeyPairReference{{
+			InferredObjectReference: v1alpha1.InferredObjectReference{Name: "target"},
+			PublicKey:               targetPub,
+		}},
+	}
+
+	objects := []client.Object{
+		&v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: testNamespace}},
+		seedSecret("callout-seed", calloutSeed),
+		seedSecret("target-seed", targetSeed),
+	}
+
+	if withXKey {
+		_, xkeyPub, xkeySeed := mustKeyPair(t, nkeys.CreateCurveKeys)
+
+		calloutClaims.Authorization.XKey = xkeyPub
+		authorizationStatus.XKey = &v1alpha1.KeyPair{PublicKey: xkeyPub, SeedSecretName: "callout-xkey"}
+
+		objects = append(objects, seedSecret("callout-xkey", xkeySeed))
+	}
+
+	storeAccount(calloutClaims)
+
+	authUserClaims := jwt.NewUserClaims(authUserPub)
+
+	authUserJWT, err := authUserClaims.Encode(calloutKP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	creds, err := jwt.FormatUserConfig(authUserJWT, authUserSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotatedAuthUserJWT, err := jwt.NewUserClaims(rotatedAuthUserPub).Encode(calloutKP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotatedCreds, err := jwt.FormatUserConfig(rotatedAuthUserJWT, rotatedAuthUserSeed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, sentinelPub, sentinelSeed := mustKeyPair(t, nkeys.CreateUser)
+
+	sentinelClaims := jwt.NewUserClaims(sentinelPub)
+	sentinelClaims.BearerToken = true
+	sentinelClaims.Pub.Deny.Add(">")
+	sentinelClaims.Sub.Deny.Add(">")
+
+	sentinelJWT, err := sentinelClaims.Encode(calloutKP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	objects = append(objects,
+		&v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "callout", Namespace: testNamespace},
+			Status: v1alpha1.AccountStatus{
+				KeyPair:       &v1alpha1.KeyPair{PublicKey: calloutPub, SeedSecretName: "callout-seed"},
+				Authorization: authorizationStatus,
+			},
+		},
+		&v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "target", Namespace: testNamespace},
+			Status: v1alpha1.AccountStatus{
+				KeyPair: &v1alpha1.KeyPair{PublicKey: targetPub, SeedSecretName: "target-seed"},
+			},
+		},
+		&v1alpha1.User{
+			ObjectMeta: metav1.ObjectMeta{Name: "auth-user", Namespace: testNamespace},
+			Spec:       v1alpha1.UserSpec{CredentialsSecretName: "auth-user-creds"},
+		},
+		&v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "auth-user-creds", Namespace: testNamespace},
+			Data:       map[string][]byte{v1alpha1.NatsSecretCredsKey: creds},
+		},
+	)
+
+	srv, err := server.NewServer(&server.Options{
+		Host:             "127.0.0.1",
+		Port:             -1,
+		NoLog:            true,
+		NoSigs:           true,
+		TrustedOperators: []*jwt.OperatorClaims{opClaims},
+		SystemAccount:    sysPub,
+		AccountResolver:  resolver,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Start()
+	t.Cleanup(srv.Shutdown)
+
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats-server not ready for connections")
+	}
+
+	return &testEnv{
+		server:             srv,
+		objects:            objects,
+		calloutPub:         calloutPub,
+		targetPub:          targetPub,
+		rotatedAuthUserPub: rotatedAuthUserPub,
+		rotatedCreds:       rotatedCreds,
+		sentinelJWT:        sentinelJWT,
+		sentinelSeed:       string(sentinelSeed),
+	}
+}
+
+func TestResponder(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	reviewer := fakeTokenReviewer{
+		"app-token":   serviceAccountUsernamePrefix + testNamespace + ":app",
+		"other-token": serviceAccountUsernamePrefix + testNamespace + ":other",
+		"user-token":  "alice",
+	}
+
+	tests := []struct {
+		name        string
+		withXKey    bool
+		accountRef  string
+		token       string
+		wantAccount string
+		wantErr     bool
+	}{
+		{
+			name:        "callout account",
+			accountRef:  "callout",
+			token:       "app-token",
+			wantAccount: "callout",
+		},
+		{
+			name:        "allowed account",
+			accountRef:  "target",
+			token:       "app-token",
+			wantAccount: "target",
+		},
+		{
+			name:        "allowed account with xkey",
+			withXKey:    true,
+			accountRef:  "target",
+			token:       "app-token",
+			wantAccount: "target",
+		},
+		{
+			name:       "invalid token",
+			accountRef: "target",
+			token:      "invalid-token",
+			wantErr:    true,
+		},
+		{
+			name:       "not a service account",
+			accountRef: "target",
+			token:      "user-token",
+			wantErr:    true,
+		},
+		{
+			name:       "no matching policy",
+			accountRef: "target",
+			token:      "other-token",
+			wantErr:    true,
+		},
+		{
+			name:       "account not allowed",
+			accountRef: "missing",
+			token:      "app-token",
+			wantErr:    true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			env := newTestEnv(t, tt.withXKey)
+
+			policy := &v1alpha1.ServiceAccountPolicy{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: testNamespace},
+				Spec: v1alpha1.ServiceAccountPolicySpec{
+					ServiceAccountNames: []string{"app"},
+					AccountRef:          v1alpha1.InferredObjectReference{Name: tt.accountRef},
+					Permissions: &v1alpha1.UserPermissions{
+						Pub: v1alpha1.Permission{Allow: []string{"orders.>"}},
+					},
+					TTL: &metav1.Duration{Duration: time.Minute},
+				},
+			}
+
+			c := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(append(env.objects, policy)...).
+				Build()
+
+			responder := NewResponder(c, reviewer, Options{
+				URL:     env.server.ClientURL(),
+				Account: types.NamespacedName{Namespace: testNamespace, Name: "callout"},
+				User:    types.NamespacedName{Namespace: testNamespace, Name: "auth-user"},
+			})
+
+			ctx, cancel := context.WithCancel(context.Background())
+			errCh := make(chan error, 1)
+
+			go func() {
+				errCh <- responder.Start(ctx)
+			}()
+
+			t.Cleanup(func() {
+				cancel()
+
+				if err := <-errCh; err != nil {
+					t.Errorf("responder returned error: %v", err)
+				}
+			})
+
+			waitForSubscription(t, env.server, env.calloutPub)
+
+			nc, err := nats.Connect(env.server.ClientURL(),
+				nats.UserJWTAndSeed(env.sentinelJWT, env.sentinelSeed),
+				nats.Token(tt.token),
+				nats.NoReconnect(),
+			)
+			if tt.wantErr {
+				if err == nil {
+					nc.Close()
+					t.Fatal("expected connection to be rejected")
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("failed to connect: %v", err)
+			}
+			defer nc.Close()
+
+			wantPub := env.calloutPub
+			if tt.wantAccount == "target" {
+				wantPub = env.targetPub
+			}
+
+			connz, err := env.server.Connz(&server.ConnzOptions{Username: true, Subscriptions: true})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var found bool
+
+			for _, conn := range connz.Conns {
+				if conn.AuthorizedUser != serviceAccountUsernamePrefix+testNamespace+":app" {
+					continue
+				}
+
+				found = true
+
+				if conn.Account != wantPub {
+					t.Errorf("connection account = %s, want %s", conn.Account, wantPub)
+				}
+			}
+
+			if !found {
+				t.Error("connection for service account not found")
+			}
+		})
+	}
+}
+
+func TestResponder_Credentials(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	env := newTestEnv(t, false)
+
+	// the credentials Secret of the auth user does not exist yet, as on a fresh install
+	var credsSecret *v1.Secret
+
+	objects := make([]client.Object, 0, len(env.objects))
+
+	for _, obj := range env.objects {
+		if secret, ok := obj.(*v1.Secret); ok && secret.Name == "auth-user-creds" {
+			credsSecret = secret
+
+			continue
+		}
+
+		objects = append(objects, obj)
+	}
+
+	c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+
+	responder := NewResponder(c, fakeTokenReviewer{}, Options{
+		URL:                      env.server.ClientURL(),
+		Account:                  types.NamespacedName{Namespace: testNamespace, Name: "callout"},
+		User:                     types.NamespacedName{Namespace: testNamespace, Name: "auth-user"},
+		CredentialsCheckInterval: 10 * time.Millisecond,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	errCh := make(chan error, 1)
+
+	go func() {
+		errCh <- responder.Start(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+
+		if err := <-errCh; err != nil {
+			t.Errorf("responder returned error: %v", err)
+		}
+	})
+
+	select {
+	case err := <-errCh:
+		t.Fatalf("responder returned before the credentials exist: %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	if err := c.Create(ctx, credsSecret); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForSubscription(t, env.server, env.calloutPub)
+
+	credsSecret.Data[v1alpha1.NatsSecretCredsKey] = env.rotatedCreds
+
+	if err := c.Update(ctx, credsSecret); err != nil {
+		t.Fatal(err)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for {
+		connz, err := env.server.Connz(&server.ConnzOptions{Username: true, Account: env.calloutPub})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(connz.Conns) == 1 && connz.Conns[0].AuthorizedUser == env.rotatedAuthUserPub {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("responder did not reconnect with the rotated credentials, connections = %+v", connz.Conns)
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// waitForSubscription waits for the responder to subscribe to auth requests in the auth callout account.
+func waitForSubscription(t *testing.T, srv *server.Server, account string) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+
+	for time.Now().Before(deadline) {
+		acc, err := srv.LookupAccount(account)
+		if err == nil && acc.SubscriptionInterest(SubjectAuthRequest) {
+			return
+		}
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	t.Fatalf("timed out waiting for subscription to %s", SubjectAuthRequest)
+}
@@ -30,6 +30,7 @@ import (
 	"fmt"
 	"time"
 
+	goerrors "github.com/go-faster/errors"
 	"github.com/go-logr/logr"
 	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nkeys"
@@ -41,6 +42,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
@@ -58,7 +60,6 @@ const AccountFinalizer = "accounts.nats.io/finalizer"
 // AccountReconciler reconciles an Account object
 type AccountReconciler struct {
 	*BaseReconciler
-	SysAccountLoader *nsc.SystemAccountLoader
 }
 
 // +kubebuilder:rbac:groups=accounts.nats.io,resources=accounts,verbs=get;list;watch;create;update;patch;delete
@@ -71,6 +72,9 @@ type AccountReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.13.0/pkg/reconcile
 func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := startSpan(ctx, "AccountReconciler.Reconcile", keyAttributes(req.Namespace, req.Name)...)
+	defer func() { endSpan(span, err) }()
+
 	logger := log.FromContext(ctx)
 
 	logger.V(1).Info("reconciling account", "account", req.Name)
@@ -80,8 +84,18 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		return result, client.IgnoreNotFound(err)
 	}
 
+	if isPaused(r.EventRecorder, acc) {
+		return ctrl.Result{}, nil
+	}
+
+	if paused, err := r.isOperatorPaused(ctx, acc); paused || err != nil {
+		return ctrl.Result{}, err
+	}
+
 	originalStatus := acc.Status.DeepCopy()
 
+	acc.Status.ObservedGeneration = acc.Generation
+
 	acc.Status.InitializeConditions()
 
 	defer func() {
@@ -128,8 +142,8 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		return ctrl.Result{}, nil
 	}
 
-	kp, _, result, err := r.reconcileSeedSecret(ctx, acc, nkeys.CreateAccount, acc.Spec.SeedSecretName,
-		resources.Immutable(), resources.WithDeletionPrevention())
+	kp, _, result, err := r.reconcileKeyPair(ctx, acc, nkeys.CreateAccount, nkeys.PrefixByteAccount,
+		acc.Spec.ExistingSeedSecretRef, acc.Spec.SeedSecretName, resources.Immutable(), resources.WithDeletionPrevention())
 	if err != nil {
 		logger.Error(err, "failed to reconcile seed secret")
 
@@ -157,6 +171,11 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		return AsResult(err)
 	}
 
+	// the Operator may have changed since the check above, or this is the first time it has been resolved
+	if r.pausedByOperator(acc, operator) {
+		return ctrl.Result{}, nil
+	}
+
 	if err := r.validateOperatorSelector(ctx, operator, acc); err != nil {
 		MarkCondition(err, acc.Status.MarkOperatorResolveFailed, acc.Status.MarkOperatorResolveUnknown)
 
@@ -181,6 +200,14 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		return ctrl.Result{}, err
 	}
 
+	if err := r.ensureAuthorizationReady(ctx, acc); err != nil {
+		logger.Info("failed to ensure authorization was ready", "error", err.Error())
+
+		MarkCondition(err, acc.Status.MarkAuthorizationFailed, acc.Status.MarkAuthorizationUnknown)
+
+		return AsResult(err)
+	}
+
 	issuerKP, ok, err := r.loadIssuerSeed(ctx, acc, keyPairable)
 	if err != nil || !ok {
 		if ok {
@@ -195,16 +222,27 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		return ctrl.Result{}, err
 	}
 
-	accountJWT, result, err := r.reconcileJWTSecret(ctx, acc, issuerKP)
+	forcePush := forcePushRequest(acc, acc.Status.LastHandledForcePushAt)
+
+	accountJWT, result, err := r.reconcileJWTSecret(ctx, acc, issuerKP, forcePush != "")
+	if err != nil {
+		MarkCondition(err, acc.Status.MarkJWTSecretFailed, acc.Status.MarkJWTSecretUnknown)
+
+		return AsResult(err)
+	}
+
+	claims, err := newClaimsSummary(accountJWT)
 	if err != nil {
 		MarkCondition(err, acc.Status.MarkJWTSecretFailed, acc.Status.MarkJWTSecretUnknown)
 
 		return AsResult(err)
 	}
 
-	acc.Status.MarkJWTSecretReady()
+	acc.Status.MarkJWTSecretReady(claims)
 
-	if !result.IsZero() {
+	// a forced push carries on to push the JWT it has just re-signed, otherwise the next reconcile would see the request
+	// as pending and re-sign it again
+	if !result.IsZero() && forcePush == "" {
 		return result, nil
 	}
 
@@ -212,11 +250,83 @@ func (r *AccountReconciler) Reconcile(ctx context.Context, req ctrl.Request) (re
 		return ctrl.Result{}, err
 	}
 
-	return ctrl.Result{}, nil
+	if forcePush != "" {
+		acc.Status.LastHandledForcePushAt = forcePush
+
+		r.EventRecorder.Eventf(acc, v1.EventTypeNormal, "ForcePushed", "re-signed and pushed JWT for %s=%s", v1alpha1.AnnotationForcePushAt, forcePush)
+	}
+
+	return result, nil
+}
+
+// isOperatorPaused returns true, and records an Event, if reconciliation is paused on the Operator in
+// acc.Status.OperatorRef. Accounts which have not resolved their Operator yet are checked once it is resolved.
+func (r *AccountReconciler) isOperatorPaused(ctx context.Context, acc *v1alpha1.Account) (bool, error) {
+	ref := acc.Status.OperatorRef
+	if ref == nil {
+		return false, nil
+	}
+
+	operator := new(v1alpha1.Operator)
+
+	err := r.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, operator)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+
+		return false, fmt.Errorf("failed to get operator: %w", err)
+	}
+
+	return r.pausedByOperator(acc, operator), nil
+}
+
+// pausedByOperator returns true, and records an Event, if the AnnotationPaused annotation is set on operator.
+func (r *AccountReconciler) pausedByOperator(acc *v1alpha1.Account, operator *v1alpha1.Operator) bool {
+	if operator.Annotations[v1alpha1.AnnotationPaused] != "true" {
+		return false
+	}
+
+	r.EventRecorder.Eventf(acc, v1.EventTypeNormal, "ReconcilePaused", "reconciliation is paused by the %s annotation on Operator %s/%s",
+		v1alpha1.AnnotationPaused, operator.Namespace, operator.Name)
+
+	return true
+}
+
+// resolveIssuer resolves the Account's issuer the same as BaseReconciler.resolveIssuer, additionally refusing to use
+// the Operator identity key when the Operator has strict signing key usage enabled, or when the identity key is held
+// offline.
+func (r *AccountReconciler) resolveIssuer(ctx context.Context, issuer v1alpha1.IssuerReference, fallbackNamespace string) (v1alpha1.KeyPairable, error) {
+	keyPairable, err := r.BaseReconciler.resolveIssuer(ctx, issuer, fallbackNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if operator, ok := keyPairable.(*v1alpha1.Operator); ok && operator.Spec.OfflineIdentity != nil {
+		return nil, TerminalError(ConditionFailed(
+			v1alpha1.ReasonOfflineIdentity,
+			"operator %s/%s identity seed is held offline, the account issuer must be an operator SigningKey",
+			operator.Namespace,
+			operator.Name,
+		))
+	}
+
+	if operator, ok := keyPairable.(*v1alpha1.Operator); ok && operator.Spec.StrictSigningKeyUsage {
+		return nil, TerminalError(ConditionFailed(
+			v1alpha1.ReasonStrictSigningKeyUsage,
+			"operator %s/%s has strictSigningKeyUsage enabled, the account issuer must be an operator SigningKey",
+			operator.Namespace,
+			operator.Name,
+		))
+	}
+
+	return keyPairable, nil
 }
 
 func (r *AccountReconciler) validateOperatorSelector(ctx context.Context, operator *v1alpha1.Operator, account *v1alpha1.Account) error {
-	ns, err := r.CoreV1.Namespaces().Get(ctx, account.Namespace, metav1.GetOptions{})
+	ns := new(v1.Namespace)
+
+	err := r.Get(ctx, client.ObjectKey{Name: account.Namespace}, ns)
 	if err != nil {
 		return TemporaryError(fmt.Errorf("failed to get account namespace: %w", err))
 	}
@@ -251,6 +361,9 @@ func (r *AccountReconciler) validateOperatorSelector(ctx context.Context, operat
 // .status.operatorRef field. If the provided keyPair is a SigningKey this will correctly resolve the owner to an
 // Operator.
 func (r *AccountReconciler) resolveOperator(ctx context.Context, acc *v1alpha1.Account, keyPair v1alpha1.KeyPairable) (operator *v1alpha1.Operator, err error) {
+	ctx, span := startSpan(ctx, "AccountReconciler.resolveOperator")
+	defer func() { endSpan(span, err) }()
+
 	logger := log.FromContext(ctx)
 
 	switch v := keyPair.(type) {
@@ -302,13 +415,9 @@ func (r *AccountReconciler) ensureSigningKeysUpdated(ctx context.Context, acc *v
 		}
 	}
 
-	var listOptions metav1.ListOptions
+	skList := new(v1alpha1.SigningKeyList)
 
-	if !labelSelector.Empty() {
-		listOptions.LabelSelector = labelSelector.String()
-	}
-
-	skList, err := r.AccountsV1Alpha1.SigningKeys(acc.Namespace).List(ctx, listOptions)
+	err := r.List(ctx, skList, client.InNamespace(acc.Namespace), client.MatchingLabelsSelector{Selector: labelSelector})
 	if err != nil {
 		// this should be a temporary error on the api-server so don't record and event and hope it goes away
 		return fmt.Errorf("failed to list SigningKeys: %w", err)
@@ -325,7 +434,175 @@ func (r *AccountReconciler) ensureSigningKeysUpdated(ctx context.Context, acc *v
 	return nil
 }
 
-func (r *AccountReconciler) reconcileJWTSecret(ctx context.Context, acc *v1alpha1.Account, issuerKP nkeys.KeyPair) (ajwt string, result reconcile.Result, err error) {
+// ensureAuthorizationReady resolves the public keys of the auth callout Users and allowed Accounts, and reconciles the
+// xkey secret if required.
+//
+// Auth callout Users are issued by this Account, so we only require that their seed secrets are ready rather than the
+// Users themselves, otherwise neither would ever become ready.
+func (r *AccountReconciler) ensureAuthorizationReady(ctx context.Context, acc *v1alpha1.Account) error {
+	spec := acc.Spec.Authorization
+	if spec == nil {
+		acc.Status.MarkAuthorizationReady(nil)
+
+		return nil
+	}
+
+	status := &v1alpha1.AccountAuthorizationStatus{
+		AuthUsers:       make([]v1alpha1.KeyPairReference, 0, len(spec.AuthUsers)),
+		AllowedAccounts: make([]v1alpha1.KeyPairReference, 0, len(spec.AllowedAccounts)),
+	}
+
+	for _, ref := range spec.AuthUsers {
+		kpRef, err := r.resolveKeyPairReference(ctx, new(v1alpha1.User), "User", ref, acc.Namespace)
+		if err != nil {
+			return err
+		}
+
+		status.AuthUsers = append(status.AuthUsers, kpRef)
+	}
+
+	for _, ref := range spec.AllowedAccounts {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = acc.Namespace
+		}
+
+		// the status of this Account may not have been persisted yet, so use the in-memory KeyPair instead.
+		if namespace == acc.Namespace && ref.Name == acc.Name {
+			status.AllowedAccounts = append(status.AllowedAccounts, v1alpha1.KeyPairReference{
+				InferredObjectReference: v1alpha1.InferredObjectReference{
+					Namespace: namespace,
+					Name:      ref.Name,
+				},
+				PublicKey: acc.Status.KeyPair.PublicKey,
+			})
+
+			continue
+		}
+
+		kpRef, err := r.resolveKeyPairReference(ctx, new(v1alpha1.Account), "Account", ref, acc.Namespace)
+		if err != nil {
+			return err
+		}
+
+		status.AllowedAccounts = append(status.AllowedAccounts, kpRef)
+	}
+
+	if spec.XKey != nil {
+		xkey, err := r.reconcileXKeySecret(ctx, acc)
+		if err != nil {
+			return err
+		}
+
+		status.XKey = xkey
+	}
+
+	acc.Status.MarkAuthorizationReady(status)
+
+	return nil
+}
+
+// resolveKeyPairReference fetches the KeyPairable referenced by ref into obj and returns a reference to its public key.
+// Only the SeedSecretReady condition is required to be true.
+func (r *AccountReconciler) resolveKeyPairReference(ctx context.Context, obj client.Object, kind string, ref v1alpha1.InferredObjectReference, fallbackNamespace string) (v1alpha1.KeyPairReference, error) {
+	if ref.Namespace != "" {
+		fallbackNamespace = ref.Namespace
+	}
+
+	err := r.Get(ctx, client.ObjectKey{Namespace: fallbackNamespace, Name: ref.Name}, obj)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return v1alpha1.KeyPairReference{}, TemporaryError(ConditionFailed(
+				v1alpha1.ReasonNotFound, "%s, %s/%s: not found", kind, fallbackNamespace, ref.Name))
+		}
+
+		return v1alpha1.KeyPairReference{}, ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to get %s: %w", kind, err)
+	}
+
+	keyPairable, ok := obj.(v1alpha1.KeyPairable)
+	if !ok {
+		return v1alpha1.KeyPairReference{}, TerminalError(ConditionFailed(
+			v1alpha1.ReasonUnknownError, "%s does not implement KeyPairable interface", kind))
+	}
+
+	conditions := keyPairable.GetConditionSet().Manage(keyPairable.GetStatus())
+
+	// Initialize the conditions if they are not already set, not doing this causes a nil-pointer dereference panic
+	conditions.InitializeConditions()
+
+	if !conditions.GetCondition(v1alpha1.KeyPairableConditionSeedSecretReady).IsTrue() || keyPairable.GetKeyPair() == nil {
+		return v1alpha1.KeyPairReference{}, TemporaryError(ConditionUnknown(
+			v1alpha1.ReasonNotReady, "%s, %s/%s: seed secret is not ready", kind, fallbackNamespace, ref.Name))
+	}
+
+	return v1alpha1.KeyPairReference{
+		InferredObjectReference: v1alpha1.InferredObjectReference{
+			Namespace: fallbackNamespace,
+			Name:      ref.Name,
+		},
+		PublicKey: keyPairable.GetKeyPair().PublicKey,
+	}, nil
+}
+
+// reconcileXKeySecret ensures the curve key pair used to encrypt auth callout requests exists, creating a new one if
+// required.
+func (r *AccountReconciler) reconcileXKeySecret(ctx context.Context, acc *v1alpha1.Account) (*v1alpha1.KeyPair, error) {
+	logger := log.FromContext(ctx)
+
+	secretName := acc.Spec.Authorization.XKey.SeedSecretName
+
+	got, err := r.getSecret(ctx, acc.Namespace, secretName)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return nil, TemporaryError(ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to get xkey secret: %w", err))
+		}
+
+		logger.V(1).Info("xkey secret does not exist, creating")
+
+		kp, err := nkeys.CreateCurveKeys()
+		if err != nil {
+			return nil, TemporaryError(ConditionFailed(v1alpha1.ReasonUnknownError, "failed to generate new curve KeyPair: %w", err))
+		}
+
+		secret, err := resources.NewXKeySecretBuilder(r.Scheme).Build(acc, kp, resources.Immutable())
+		if err != nil {
+			return nil, TemporaryError(ConditionFailed(v1alpha1.ReasonUnknownError, "failed to build xkey secret: %w", err))
+		}
+
+		if err = r.Client.Create(ctx, secret); err != nil {
+			return nil, TemporaryError(ConditionFailed(v1alpha1.ReasonUnknownError, "failed to create xkey secret: %w", err))
+		}
+
+		r.EventRecorder.Eventf(acc, v1.EventTypeNormal, "XKeySecretCreated", "created secret: %s/%s", secret.Namespace, secret.Name)
+
+		return &v1alpha1.KeyPair{
+			PublicKey:      secret.Labels[resources.LabelSubject],
+			SeedSecretName: secret.Name,
+		}, nil
+	}
+
+	seed, ok := got.Data[v1alpha1.NatsSecretSeedKey]
+	if !ok {
+		return nil, TerminalError(ConditionFailed(v1alpha1.ReasonInvalidSeedSecret, "xkey secret does not contain seed data, delete the secret for a new keypair"))
+	}
+
+	kp, err := nkeys.FromCurveSeed(seed)
+	if err != nil {
+		return nil, TerminalError(ConditionFailed(v1alpha1.ReasonInvalidSeedSecret, "failed to parse xkey seed: %w", err))
+	}
+
+	pubkey, err := kp.PublicKey()
+	if err != nil {
+		return nil, TerminalError(ConditionFailed(v1alpha1.ReasonMalformedSeedSecret, "failed to get PublicKey from xkey KeyPair: %w", err))
+	}
+
+	return &v1alpha1.KeyPair{
+		PublicKey:      pubkey,
+		SeedSecretName: got.Name,
+	}, nil
+}
+
+func (r *AccountReconciler) reconcileJWTSecret(ctx context.Context, acc *v1alpha1.Account, issuerKP nkeys.KeyPair, force bool) (ajwt string, result reconcile.Result, err error) {
 	logger := log.FromContext(ctx)
 
 	// we want to check that any existing secret decodes to match wantClaims, if it doesn't then we will use nextJWT
@@ -333,10 +610,17 @@ func (r *AccountReconciler) reconcileJWTSecret(ctx context.Context, acc *v1alpha
 	// timestamped with the `iat` claim so will never match.
 	wantClaims, nextJWT, err := nsc.CreateAccountClaims(acc, issuerKP)
 	if err != nil {
-		return "", reconcile.Result{}, TerminalError(ConditionFailed(v1alpha1.ReasonUnknownError, "failed to create account JWT claims: %w", err))
+		reason := v1alpha1.ReasonUnknownError
+		if goerrors.Is(err, nsc.ErrInvalidLimits) {
+			reason = v1alpha1.ReasonInvalidLimits
+		}
+
+		return "", reconcile.Result{}, TerminalError(ConditionFailed(reason, "failed to create account JWT claims: %w", err))
 	}
 
-	got, err := r.CoreV1.Secrets(acc.Namespace).Get(ctx, acc.Spec.JWTSecretName, metav1.GetOptions{})
+	defer func() { observeJWTSigned("Account", result, err) }()
+
+	got, err := r.getSecret(ctx, acc.Namespace, acc.Spec.JWTSecretName)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.V(1).Info("JWT secret not found, creating new secret")
@@ -347,7 +631,7 @@ func (r *AccountReconciler) reconcileJWTSecret(ctx context.Context, acc *v1alpha
 		return "", reconcile.Result{}, TemporaryError(ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to get JWT secret: %w", err))
 	}
 
-	return r.ensureJWTSecretUpToDate(ctx, acc, wantClaims, got, nextJWT)
+	return r.ensureJWTSecretUpToDate(ctx, acc, wantClaims, got, nextJWT, force)
 }
 
 func (r *AccountReconciler) loadIssuerSeed(ctx context.Context, acc *v1alpha1.Account, issuer v1alpha1.KeyPairable) (nkeys.KeyPair, bool, error) {
@@ -362,7 +646,7 @@ func (r *AccountReconciler) loadIssuerSeed(ctx context.Context, acc *v1alpha1.Ac
 		return nil, false, nil
 	}
 
-	skSeedSecret, err := r.CoreV1.Secrets(issuer.GetNamespace()).Get(ctx, keyPair.SeedSecretName, metav1.GetOptions{})
+	skSeedSecret, err := r.getSecret(ctx, issuer.GetNamespace(), keyPair.SeedSecretName)
 	if err != nil {
 		logger.V(1).Info("failed to get issuer seed", "issuer", issuer.GetName())
 
@@ -376,9 +660,9 @@ func (r *AccountReconciler) loadIssuerSeed(ctx context.Context, acc *v1alpha1.Ac
 		return nil, false, err
 	}
 
-	seed, ok := skSeedSecret.Data[v1alpha1.NatsSecretSeedKey]
+	seed, ok := skSeedSecret.Data[keyPair.GetSeedSecretKey()]
 	if !ok {
-		acc.Status.MarkIssuerResolveFailed(v1alpha1.ReasonMalformedSeedSecret, "secret missing required field: %s", v1alpha1.NatsSecretSeedKey)
+		acc.Status.MarkIssuerResolveFailed(v1alpha1.ReasonMalformedSeedSecret, "secret missing required field: %s", keyPair.GetSeedSecretKey())
 
 		return nil, false, nil
 	}
@@ -431,10 +715,13 @@ func (r *AccountReconciler) loadIssuerSeed(ctx context.Context, acc *v1alpha1.Ac
 	return kp, true, nil
 }
 
-func (r *AccountReconciler) ensureJWTPushed(ctx context.Context, acc *v1alpha1.Account, operator *v1alpha1.Operator, issuer nkeys.KeyPair, ajwt string) error {
+func (r *AccountReconciler) ensureJWTPushed(ctx context.Context, acc *v1alpha1.Account, operator *v1alpha1.Operator, issuer nkeys.KeyPair, ajwt string) (err error) {
+	ctx, span := startSpan(ctx, "AccountReconciler.ensureJWTPushed", keyAttributes(operator.Namespace, operator.Name)...)
+	defer func() { endSpan(span, err) }()
+
 	logger := log.FromContext(ctx)
 
-	sysSeed, err := r.SysAccountLoader.Load(ctx, operator)
+	sysSeed, err := r.SystemCredentials.Load(ctx, operator)
 	if err != nil {
 		logger.Error(err, "failed to load system account")
 
@@ -452,8 +739,18 @@ func (r *AccountReconciler) ensureJWTPushed(ctx context.Context, acc *v1alpha1.A
 		return err
 	}
 
-	nscClient, err := nsc.Connect(operator.Spec.AccountServerURL, issuer, sysSeed, opts...)
+	if err = r.AccountServerLimiter.Wait(ctx, operator, "push"); err != nil {
+		acc.Status.MarkJWTPushFailed(v1alpha1.ReasonUnknownError, err.Error())
+
+		return err
+	}
+
+	start := time.Now()
+
+	publisher, err := r.AccountServer.Connect(operator.Spec.AccountServerURL, issuer, sysSeed, opts...)
 	if err != nil {
+		observeAccountServerRequest(operator, "push", start, err)
+
 		logger.Error(err, "failed to connect to account server")
 
 		acc.Status.MarkJWTPushFailed(v1alpha1.ReasonUnknownError, err.Error())
@@ -461,9 +758,12 @@ func (r *AccountReconciler) ensureJWTPushed(ctx context.Context, acc *v1alpha1.A
 		return err
 	}
 
-	defer nscClient.Close()
+	defer publisher.Close()
+
+	err = publisher.Push(ctx, ajwt)
+	observeAccountServerRequest(operator, "push", start, err)
 
-	if err = nscClient.Push(ctx, ajwt); err != nil {
+	if err != nil {
 		logger.Error(err, "failed to push account JWT to account server")
 
 		acc.Status.MarkJWTPushFailed(v1alpha1.ReasonJWTPushError, err.Error())
@@ -476,7 +776,10 @@ func (r *AccountReconciler) ensureJWTPushed(ctx context.Context, acc *v1alpha1.A
 	return nil
 }
 
-func (r *AccountReconciler) finalizeAccount(ctx context.Context, acc *v1alpha1.Account) error {
+func (r *AccountReconciler) finalizeAccount(ctx context.Context, acc *v1alpha1.Account) (err error) {
+	ctx, span := startSpan(ctx, "AccountReconciler.finalizeAccount")
+	defer func() { endSpan(span, err) }()
+
 	logger := log.FromContext(ctx)
 
 	if !acc.Status.GetCondition(v1alpha1.AccountConditionJWTSecretReady).IsTrue() {
@@ -499,7 +802,9 @@ func (r *AccountReconciler) finalizeAccount(ctx context.Context, acc *v1alpha1.A
 	//  "if the user wants to do that, then it's their *** fault".
 
 	operatorRef := acc.Status.OperatorRef
-	operator, err := r.AccountsV1Alpha1.Operators(operatorRef.Namespace).Get(ctx, operatorRef.Name, metav1.GetOptions{})
+	operator := new(v1alpha1.Operator)
+
+	err = r.Get(ctx, client.ObjectKey{Namespace: operatorRef.Namespace, Name: operatorRef.Name}, operator)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("operator not found, skipping finalization")
@@ -516,14 +821,25 @@ func (r *AccountReconciler) finalizeAccount(ctx context.Context, acc *v1alpha1.A
 		return fmt.Errorf("operator not ready")
 	}
 
-	operatorSeed, err := r.CoreV1.Secrets(operator.Namespace).Get(ctx, operator.Status.KeyPair.SeedSecretName, metav1.GetOptions{})
+	if operator.Spec.OfflineIdentity != nil {
+		// deleting an account JWT from the resolver must be signed by the operator identity key, which we don't have.
+		logger.Info("operator identity seed is held offline, skipping removal of account JWT from resolver")
+
+		r.EventRecorder.Eventf(acc, v1.EventTypeWarning, "AccountJWTNotDeleted",
+			"operator %s/%s identity seed is held offline, account %s must be removed from the resolver manually",
+			operator.Namespace, operator.Name, acc.Status.KeyPair.PublicKey)
+
+		return nil
+	}
+
+	operatorSeed, err := r.getSecret(ctx, operator.Namespace, operator.Status.KeyPair.SeedSecretName)
 	if err != nil {
 		return fmt.Errorf("unable to load operator seed: %w", err)
 	}
 
-	operatorSeedData, ok := operatorSeed.Data[v1alpha1.NatsSecretSeedKey]
+	operatorSeedData, ok := operatorSeed.Data[operator.Status.KeyPair.GetSeedSecretKey()]
 	if !ok {
-		return fmt.Errorf("operator seed secret missing property, %q", v1alpha1.NatsSecretSeedKey)
+		return fmt.Errorf("operator seed secret missing property, %q", operator.Status.KeyPair.GetSeedSecretKey())
 	}
 
 	operatorKP, err := nkeys.FromSeed(operatorSeedData)
@@ -531,7 +847,7 @@ func (r *AccountReconciler) finalizeAccount(ctx context.Context, acc *v1alpha1.A
 		return fmt.Errorf("failed to parse operator seed data: %w", err)
 	}
 
-	sysSeed, err := r.SysAccountLoader.Load(ctx, operator)
+	sysSeed, err := r.SystemCredentials.Load(ctx, operator)
 	if err != nil {
 		// not sure what errors should allow finalization to skip vs fail for a retry, for now we'll only skip if the
 		// system account doesn't exist, otherwise we'll fail for a retry
@@ -555,16 +871,27 @@ func (r *AccountReconciler) finalizeAccount(ctx context.Context, acc *v1alpha1.A
 		return err
 	}
 
-	nscClient, err := nsc.Connect(operator.Spec.AccountServerURL, operatorKP, sysSeed, opts...)
+	if err = r.AccountServerLimiter.Wait(ctx, operator, "delete"); err != nil {
+		return err
+	}
+
+	start := time.Now()
+
+	publisher, err := r.AccountServer.Connect(operator.Spec.AccountServerURL, operatorKP, sysSeed, opts...)
 	if err != nil {
+		observeAccountServerRequest(operator, "delete", start, err)
+
 		logger.Error(err, "failed to connect to account server during finalization")
 
 		return err
 	}
 
-	defer nscClient.Close()
+	defer publisher.Close()
 
-	if err = nscClient.Delete(ctx, acc.Status.KeyPair.PublicKey); err != nil {
+	err = publisher.Delete(ctx, acc.Status.KeyPair.PublicKey)
+	observeAccountServerRequest(operator, "delete", start, err)
+
+	if err != nil {
 		logger.Error(err, "failed to delete account JWT")
 
 		return err
@@ -573,7 +900,7 @@ func (r *AccountReconciler) finalizeAccount(ctx context.Context, acc *v1alpha1.A
 	return nil
 }
 
-func (r *AccountReconciler) getNATSOptions(ctx context.Context, operator *v1alpha1.Operator) ([]nats.Option, error) {
+func (r *BaseReconciler) getNATSOptions(ctx context.Context, operator *v1alpha1.Operator) ([]nats.Option, error) {
 	if operator.Spec.TLSConfig == nil {
 		return nil, nil
 	}
@@ -593,8 +920,8 @@ func (r *AccountReconciler) getNATSOptions(ctx context.Context, operator *v1alph
 	}
 }
 
-func (r *AccountReconciler) loadCAFile(ctx context.Context, ns string, selector v1.SecretKeySelector) ([]byte, error) {
-	secret, err := r.CoreV1.Secrets(ns).Get(ctx, selector.Name, metav1.GetOptions{})
+func (r *BaseReconciler) loadCAFile(ctx context.Context, ns string, selector v1.SecretKeySelector) ([]byte, error) {
+	secret, err := r.getSecret(ctx, ns, selector.Name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get caFile secret: %w", err)
 	}
@@ -644,10 +971,12 @@ func (r *AccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	logger := mgr.GetLogger().WithName("AccountReconciler")
 	err := ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Account{}).
+		For(&v1alpha1.Account{}, builder.WithPredicates(specChangedPredicate())).
+		WithOptions(r.controllerOptions()).
 		Owns(&v1.Secret{}).
-		Watches(&v1alpha1.SigningKey{}, accountSigningKeyWatcher(logger)).
+		Watches(&v1alpha1.SigningKey{}, accountSigningKeyWatcher(logger, mgr.GetClient())).
 		Watches(&v1alpha1.Operator{}, accountOperatorWatcher(logger, mgr.GetClient())).
+		Watches(&v1alpha1.User{}, accountAuthUserWatcher(logger, mgr.GetClient())).
 		Complete(r)
 
 	if err != nil {
@@ -659,9 +988,10 @@ func (r *AccountReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 // accountSigningKeyWatcher will enqueue a reconcile of the owning Account if the SigningKey
 // changes. This facilitates the fact that Account.Status embeds references to all signing keys
-// belonging to it and may require an update if a SigningKey changes.
-func accountSigningKeyWatcher(logger logr.Logger) handler.EventHandler {
-	return handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
+// belonging to it and may require an update if a SigningKey changes. Accounts issued by the
+// SigningKey are also enqueued so they are re-signed when it changes.
+func accountSigningKeyWatcher(logger logr.Logger, c client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
 		signingKey, ok := obj.(*v1alpha1.SigningKey)
 		if !ok {
 			logger.Info("SigningKey watcher received non-SigningKey object",
@@ -669,28 +999,44 @@ func accountSigningKeyWatcher(logger logr.Logger) handler.EventHandler {
 			return nil
 		}
 
+		requests, err := listReferencing(ctx, c, &v1alpha1.AccountList{}, signingKey,
+			indexQuery{field: IndexSpecIssuerRef, keep: issuedBy("SigningKey")})
+		if err != nil {
+			logger.Error(err, "failed to list accounts for signing key during enqueue handler", "signingKey", client.ObjectKeyFromObject(signingKey))
+
+			return nil
+		}
+
 		ownerRef := signingKey.Status.OwnerRef
 		if ownerRef == nil {
-			return nil
+			return requests
 		}
 
 		accountGVK := v1alpha1.GroupVersion.WithKind("Account")
 		if accountGVK != ownerRef.GetGroupVersionKind() {
-			return nil
+			return requests
 		}
 
-		return []reconcile.Request{{
+		owner := reconcile.Request{
 			NamespacedName: types.NamespacedName{
 				Name:      ownerRef.Name,
 				Namespace: ownerRef.Namespace,
 			},
-		}}
+		}
+
+		for _, req := range requests {
+			if req == owner {
+				return requests
+			}
+		}
+
+		return append(requests, owner)
 	})
 }
 
-// accountOperatorWatcher will enqueue any Accounts which are managed by this Operator. In reality,
-// this will almost always be every Account in the cluster unless the environment contains multiple
-// Operators.
+// accountOperatorWatcher will enqueue any Accounts which are managed by this Operator, or which are
+// issued by it but have not resolved it yet. In reality, this will almost always be every Account in
+// the cluster unless the environment contains multiple Operators.
 func accountOperatorWatcher(logger logr.Logger, c client.Client) handler.EventHandler {
 	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
 		operator, ok := obj.(*v1alpha1.Operator)
@@ -700,25 +1046,35 @@ func accountOperatorWatcher(logger logr.Logger, c client.Client) handler.EventHa
 			return nil
 		}
 
-		var accountList v1alpha1.AccountList
+		requests, err := listReferencing(ctx, c, &v1alpha1.AccountList{}, operator,
+			indexQuery{field: IndexStatusOperatorRef},
+			indexQuery{field: IndexSpecIssuerRef, keep: issuedBy("Operator")})
+		if err != nil {
+			logger.Error(err, "failed to list accounts for operator during enqueue handler", "operator", client.ObjectKeyFromObject(operator))
 
-		if err := c.List(ctx, &accountList, client.MatchingLabels{
-			resources.LabelOperatorName: operator.Name,
-		}); err != nil {
-			logger.Error(err, "failed to list accounts for operator during enqueue handler", "operator", operator.Name)
+			return nil
+		}
 
+		return requests
+	})
+}
+
+// accountAuthUserWatcher will enqueue any Accounts which reference the User as an auth callout User, this ensures the
+// Account JWT is updated once the User's KeyPair becomes available.
+func accountAuthUserWatcher(logger logr.Logger, c client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		user, ok := obj.(*v1alpha1.User)
+		if !ok {
+			logger.Info("User watcher received non-User object",
+				"kind", obj.GetObjectKind().GroupVersionKind().String())
 			return nil
 		}
 
-		requests := make([]reconcile.Request, len(accountList.Items))
+		requests, err := listReferencing(ctx, c, &v1alpha1.AccountList{}, user, indexQuery{field: IndexSpecAuthUsers})
+		if err != nil {
+			logger.Error(err, "failed to list accounts for user during enqueue handler", "user", client.ObjectKeyFromObject(user))
 
-		for i, acc := range accountList.Items {
-			requests[i] = reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Name:      acc.Name,
-					Namespace: acc.Namespace,
-				},
-			}
+			return nil
 		}
 
 		return requests
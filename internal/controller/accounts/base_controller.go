@@ -2,24 +2,28 @@ package controllers
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 
 	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats.go"
 	"github.com/nats-io/nkeys"
+	"go.opentelemetry.io/otel/attribute"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
 	"github.com/versori-oss/nats-account-operator/internal/controller/accounts/resources"
-	accountsv1alpha1 "github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned/typed/accounts/v1alpha1"
 	"github.com/versori-oss/nats-account-operator/pkg/nsc"
 )
 
@@ -27,16 +31,63 @@ type NKeyFactory func() (nkeys.KeyPair, error)
 
 type BaseReconciler struct {
 	client.Client
-	Scheme           *runtime.Scheme
-	CoreV1           corev1.CoreV1Interface
-	AccountsV1Alpha1 accountsv1alpha1.AccountsV1alpha1Interface
-	EventRecorder    record.EventRecorder
+	Scheme        *runtime.Scheme
+	EventRecorder record.EventRecorder
+
+	// AccountServer connects to the account server of an Operator to publish Account JWTs.
+	AccountServer nsc.AccountServer
+	// SystemCredentials loads the system account seed used to authenticate with the account server.
+	SystemCredentials nsc.SystemCredentialsLoader
+	// AccountServerLimiter limits the rate of pushes and deletes to the account server of each Operator, nil does not
+	// limit.
+	AccountServerLimiter *OperatorRateLimiter
+
+	// MaxConcurrentReconciles is the maximum number of concurrent reconciles of the controller, defaults to 1.
+	MaxConcurrentReconciles int
+}
+
+func (r *BaseReconciler) controllerOptions() controller.Options {
+	return controller.Options{MaxConcurrentReconciles: r.MaxConcurrentReconciles}
+}
+
+// getSecret reads the Secret namespace/name through the reconciler's client. The manager only caches Secrets labelled
+// with resources.LabelSecretType, so user-provided Secrets fall through to the API server, see NewClient.
+func (r *BaseReconciler) getSecret(ctx context.Context, namespace, name string) (_ *v1.Secret, err error) {
+	ctx, span := startSpan(ctx, "BaseReconciler.getSecret", keyAttributes(namespace, name)...)
+	defer func() { endSpan(span, err) }()
+
+	var secret v1.Secret
+
+	if err := r.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, &secret); err != nil {
+		return nil, err
+	}
+
+	return &secret, nil
+}
+
+// reconcileKeyPair adopts the seed referenced by existing when set, otherwise it reconciles a seed Secret named
+// secretName which is created and owned by the controller.
+func (r *BaseReconciler) reconcileKeyPair(ctx context.Context, owner client.Object, newKP NKeyFactory, wantPrefix nkeys.PrefixByte, existing *v1.SecretKeySelector, secretName string, secretOpts ...resources.SecretOption) (_ *v1alpha1.KeyPair, _ []byte, _ reconcile.Result, err error) {
+	ctx, span := startSpan(ctx, "BaseReconciler.reconcileKeyPair", keyAttributes(owner.GetNamespace(), owner.GetName())...)
+	defer func() { endSpan(span, err) }()
+
+	if existing != nil {
+		kp, seed, err := r.adoptSeedSecret(ctx, owner, wantPrefix, *existing)
+
+		return kp, seed, reconcile.Result{}, err
+	}
+
+	if secretName == "" {
+		return nil, nil, reconcile.Result{}, TerminalError(ConditionFailed(v1alpha1.ReasonMalformedSeedSecret, "one of seedSecretName or existingSeedSecretRef must be set"))
+	}
+
+	return r.reconcileSeedSecret(ctx, owner, newKP, secretName, secretOpts...)
 }
 
 func (r *BaseReconciler) reconcileSeedSecret(ctx context.Context, owner client.Object, newKP NKeyFactory, secretName string, secretOpts ...resources.SecretOption) (*v1alpha1.KeyPair, []byte, reconcile.Result, error) {
 	logger := log.FromContext(ctx)
 
-	got, err := r.CoreV1.Secrets(owner.GetNamespace()).Get(ctx, secretName, metav1.GetOptions{})
+	got, err := r.getSecret(ctx, owner.GetNamespace(), secretName)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.V(1).Info("seed secret does not exist, creating")
@@ -56,6 +107,52 @@ func (r *BaseReconciler) reconcileSeedSecret(ctx context.Context, owner client.O
 	return kp, got.Data[v1alpha1.NatsSecretSeedKey], result, err
 }
 
+// adoptSeedSecret loads an existing, user-provided seed from ref without taking ownership of the Secret. The seed must
+// have the wantPrefix nkey prefix for the kind of resource adopting it. The Secret is never modified, so it will not be
+// garbage collected or rewritten when the owner is deleted or reconciled.
+func (r *BaseReconciler) adoptSeedSecret(ctx context.Context, owner client.Object, wantPrefix nkeys.PrefixByte, ref v1.SecretKeySelector) (*v1alpha1.KeyPair, []byte, error) {
+	got, err := r.getSecret(ctx, owner.GetNamespace(), ref.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, nil, TemporaryError(ConditionFailed(v1alpha1.ReasonNotFound, "existing seed secret %q not found", ref.Name))
+		}
+
+		return nil, nil, TemporaryError(ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to get existing seed secret: %w", err))
+	}
+
+	seed, ok := got.Data[ref.Key]
+	if !ok {
+		return nil, nil, TerminalError(ConditionFailed(v1alpha1.ReasonInvalidSeedSecret, "existing seed secret %q does not contain key %q", ref.Name, ref.Key))
+	}
+
+	prefix, _, err := nkeys.DecodeSeed(seed)
+	if err != nil {
+		return nil, nil, TerminalError(ConditionFailed(v1alpha1.ReasonMalformedSeedSecret, "failed to parse existing seed: %w", err))
+	}
+
+	if prefix != wantPrefix {
+		return nil, nil, TerminalError(ConditionFailed(v1alpha1.ReasonMalformedSeedSecret,
+			"existing seed has prefix %s, expected %s", prefix, wantPrefix))
+	}
+
+	kp, err := nkeys.FromSeed(seed)
+	if err != nil {
+		return nil, nil, TerminalError(ConditionFailed(v1alpha1.ReasonMalformedSeedSecret, "failed to parse existing seed: %w", err))
+	}
+
+	pubkey, err := kp.PublicKey()
+	if err != nil {
+		return nil, nil, TerminalError(ConditionFailed(v1alpha1.ReasonMalformedSeedSecret, "failed to get PublicKey from KeyPair: %w", err))
+	}
+
+	return &v1alpha1.KeyPair{
+		PublicKey:         pubkey,
+		SeedSecretName:    got.Name,
+		SeedSecretKey:     ref.Key,
+		ExternallyManaged: true,
+	}, seed, nil
+}
+
 func (r *BaseReconciler) ensureSeedSecretUpToDate(ctx context.Context, owner client.Object, got *v1.Secret, secretOpts ...resources.SecretOption) (*v1alpha1.KeyPair, reconcile.Result, error) {
 	logger := log.FromContext(ctx)
 
@@ -136,7 +233,10 @@ func (r *BaseReconciler) createSeedSecret(ctx context.Context, obj client.Object
 	}, seed, nil
 }
 
-func (r *BaseReconciler) createJWTSecret(ctx context.Context, obj client.Object, jwt string) error {
+func (r *BaseReconciler) createJWTSecret(ctx context.Context, obj client.Object, jwt string) (err error) {
+	ctx, span := startSpan(ctx, "BaseReconciler.createJWTSecret", keyAttributes(obj.GetNamespace(), obj.GetName())...)
+	defer func() { endSpan(span, err) }()
+
 	secret, err := resources.NewJWTSecretBuilder(r.Scheme).Build(obj, jwt)
 	if err != nil {
 		return TerminalError(ConditionFailed(v1alpha1.ReasonUnknownError, "failed to build account keypair secret: %w", err))
@@ -151,9 +251,81 @@ func (r *BaseReconciler) createJWTSecret(ctx context.Context, obj client.Object,
 	return nil
 }
 
+// isPaused returns true, and records an Event, if reconciliation of obj is paused by the AnnotationPaused annotation.
+func isPaused(recorder record.EventRecorder, obj client.Object) bool {
+	if obj.GetAnnotations()[v1alpha1.AnnotationPaused] != "true" {
+		return false
+	}
+
+	recorder.Eventf(obj, v1.EventTypeNormal, "ReconcilePaused", "reconciliation is paused by the %s annotation", v1alpha1.AnnotationPaused)
+
+	return true
+}
+
+// specChangedPredicate filters events for the resource being reconciled, so that updates to its own status do not
+// trigger another reconcile. Label and annotation changes are still reconciled since they affect selectors, pausing
+// and forced pushes. Changes to dependent resources are handled by the Owns and Watches of each controller.
+func specChangedPredicate() predicate.Predicate {
+	return predicate.Or(
+		predicate.GenerationChangedPredicate{},
+		predicate.LabelChangedPredicate{},
+		predicate.AnnotationChangedPredicate{},
+	)
+}
+
+// forcePushRequest returns the value of the AnnotationForcePushAt annotation on obj when it has not been handled yet,
+// otherwise it returns an empty string.
+func forcePushRequest(obj metav1.Object, lastHandled string) string {
+	requested := obj.GetAnnotations()[v1alpha1.AnnotationForcePushAt]
+	if requested == lastHandled {
+		return ""
+	}
+
+	return requested
+}
+
+// newClaimsSummary decodes token and summarises its claims for the status of the resource it was issued for.
+func newClaimsSummary(token string) (v1alpha1.ClaimsSummary, error) {
+	claims, err := jwt.Decode(token)
+	if err != nil {
+		return v1alpha1.ClaimsSummary{}, TerminalError(ConditionFailed(v1alpha1.ReasonInvalidJWTSecret, "failed to decode JWT: %w", err))
+	}
+
+	data := claims.Claims()
+	hash := sha256.Sum256([]byte(token))
+
+	summary := v1alpha1.ClaimsSummary{
+		JTI:      data.ID,
+		Issuer:   data.Issuer,
+		IssuedAt: metav1.Unix(data.IssuedAt, 0),
+		Hash:     hex.EncodeToString(hash[:]),
+	}
+
+	if data.Expires > 0 {
+		expires := metav1.Unix(data.Expires, 0)
+		summary.Expires = &expires
+	}
+
+	switch c := claims.(type) {
+	case *jwt.OperatorClaims:
+		summary.SigningKeys = len(c.SigningKeys)
+	case *jwt.AccountClaims:
+		summary.Exports = len(c.Exports)
+		summary.Imports = len(c.Imports)
+		summary.SigningKeys = len(c.SigningKeys)
+	case *jwt.UserClaims:
+		summary.IssuerAccount = c.IssuerAccount
+	}
+
+	return summary, nil
+}
+
 // ensureJWTSecretUpToDate compares that the existing JWT secret decodes and matches the expected claims, if it does not
-// match the secret will be updated with the nextJWT value.
-func (r *BaseReconciler) ensureJWTSecretUpToDate(ctx context.Context, acc client.Object, wantClaims any, got *v1.Secret, nextJWT string) (string, reconcile.Result, error) {
+// match, or force is set, the secret will be updated with the nextJWT value.
+func (r *BaseReconciler) ensureJWTSecretUpToDate(ctx context.Context, acc client.Object, wantClaims any, got *v1.Secret, nextJWT string, force bool) (_ string, _ reconcile.Result, err error) {
+	ctx, span := startSpan(ctx, "BaseReconciler.ensureJWTSecretUpToDate", keyAttributes(got.Namespace, got.Name)...)
+	defer func() { endSpan(span, err) }()
+
 	logger := log.FromContext(ctx)
 
 	gotJWT, ok := got.Data[v1alpha1.NatsSecretJWTKey]
@@ -182,6 +354,8 @@ func (r *BaseReconciler) ensureJWTSecretUpToDate(ctx context.Context, acc client
 	switch {
 	case err != nil:
 		logger.Info("failed to decode JWT from secret, updating to latest version", "reason", err.Error())
+	case force:
+		logger.Info("force push requested, updating to latest version")
 	case !nsc.Equality.DeepEqual(gotClaims, wantClaims):
 		logger.V(1).Info("existing JWT secret does not match desired claims, updating to latest version")
 	default:
@@ -217,6 +391,10 @@ func (r *BaseReconciler) ensureJWTSecretUpToDate(ctx context.Context, acc client
 // The returned bool is true when everything is ok, or if a temporary error has occurred and the
 // reconciliation should be re-enqueued.
 func (r *BaseReconciler) resolveIssuer(ctx context.Context, issuer v1alpha1.IssuerReference, fallbackNamespace string) (kp v1alpha1.KeyPairable, err error) {
+	ctx, span := startSpan(ctx, "BaseReconciler.resolveIssuer",
+		attribute.String("issuer.kind", issuer.Ref.Kind), attribute.String("issuer.name", issuer.Ref.Name))
+	defer func() { endSpan(span, err) }()
+
 	logger := log.FromContext(ctx)
 
 	issuerGVK := issuer.Ref.GetGroupVersionKind()
@@ -326,7 +504,10 @@ func (r *BaseReconciler) resolveSigningKeyOwner(ctx context.Context, sk *v1alpha
 	return owner, nil
 }
 
-func (r *BaseReconciler) loadIssuerSeed(ctx context.Context, issuer v1alpha1.KeyPairable, wantPrefix nkeys.PrefixByte) (nkeys.KeyPair, error) {
+func (r *BaseReconciler) loadIssuerSeed(ctx context.Context, issuer v1alpha1.KeyPairable, wantPrefix nkeys.PrefixByte) (_ nkeys.KeyPair, err error) {
+	ctx, span := startSpan(ctx, "BaseReconciler.loadIssuerSeed", keyAttributes(issuer.GetNamespace(), issuer.GetName())...)
+	defer func() { endSpan(span, err) }()
+
 	logger := log.FromContext(ctx)
 
 	keyPair := issuer.GetKeyPair()
@@ -336,7 +517,7 @@ func (r *BaseReconciler) loadIssuerSeed(ctx context.Context, issuer v1alpha1.Key
 		return nil, ConditionFailed(v1alpha1.ReasonUnknownError, "issuer KeyPair is nil")
 	}
 
-	skSeedSecret, err := r.CoreV1.Secrets(issuer.GetNamespace()).Get(ctx, keyPair.SeedSecretName, metav1.GetOptions{})
+	skSeedSecret, err := r.getSecret(ctx, issuer.GetNamespace(), keyPair.SeedSecretName)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			return nil, TemporaryError(ConditionFailed(
@@ -348,11 +529,11 @@ func (r *BaseReconciler) loadIssuerSeed(ctx context.Context, issuer v1alpha1.Key
 		return nil, ConditionUnknown(v1alpha1.ReasonIssuerSeedError, "failed to get issuer seed: %s", err.Error())
 	}
 
-	seed, ok := skSeedSecret.Data[v1alpha1.NatsSecretSeedKey]
+	seed, ok := skSeedSecret.Data[keyPair.GetSeedSecretKey()]
 	if !ok {
 		// TODO: this is a terminal error, but if the secret is updated, this will only trigger a
 		//  reconcile on the owning issuer, and not the user.
-		return nil, TerminalError(ConditionFailed(v1alpha1.ReasonMalformedSeedSecret, "secret missing required field: %s", v1alpha1.NatsSecretSeedKey))
+		return nil, TerminalError(ConditionFailed(v1alpha1.ReasonMalformedSeedSecret, "secret missing required field: %s", keyPair.GetSeedSecretKey()))
 	}
 
 	prefix, _, err := nkeys.DecodeSeed(seed)
@@ -392,3 +573,22 @@ func (r *BaseReconciler) loadIssuerSeed(ctx context.Context, issuer v1alpha1.Key
 
 	return kp, nil
 }
+
+// connectSystemUser connects to the account server of operator as a temporary user of its system account, without the
+// operator key pair so the publisher cannot delete accounts. extra options are applied after those of the Operator.
+func (r *BaseReconciler) connectSystemUser(ctx context.Context, operator *v1alpha1.Operator, extra ...nats.Option) (_ nsc.AccountPublisher, err error) {
+	ctx, span := startSpan(ctx, "BaseReconciler.connectSystemUser", keyAttributes(operator.Namespace, operator.Name)...)
+	defer func() { endSpan(span, err) }()
+
+	sysSeed, err := r.SystemCredentials.Load(ctx, operator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load system account: %w", err)
+	}
+
+	opts, err := r.getNATSOptions(ctx, operator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NATS options: %w", err)
+	}
+
+	return r.AccountServer.Connect(operator.Spec.AccountServerURL, nil, sysSeed, append(opts, extra...)...)
+}
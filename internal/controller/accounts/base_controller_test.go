@@ -0,0 +1,346 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+func Test_BaseReconciler_adoptSeedSecret(t *testing.T) {
+	accountKP, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountSeed, err := accountKP.Seed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountPub, err := accountKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userKP, err := nkeys.CreateUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userSeed, err := userKP.Seed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		data       map[string][]byte
+		ref        corev1.SecretKeySelector
+		wantReason string
+	}{
+		{
+			name: "adopts seed with arbitrary key",
+			data: map[string][]byte{"account.nk": accountSeed},
+			ref:  corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "nsc"}, Key: "account.nk"},
+		},
+		{
+			name:       "rejects seed with wrong prefix",
+			data:       map[string][]byte{"account.nk": userSeed},
+			ref:        corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "nsc"}, Key: "account.nk"},
+			wantReason: v1alpha1.ReasonMalformedSeedSecret,
+		},
+		{
+			name:       "rejects missing key",
+			data:       map[string][]byte{"seed.nk": accountSeed},
+			ref:        corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "nsc"}, Key: "account.nk"},
+			wantReason: v1alpha1.ReasonInvalidSeedSecret,
+		},
+		{
+			name:       "secret not found",
+			ref:        corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "account.nk"},
+			wantReason: v1alpha1.ReasonNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "nsc", Namespace: "nats"},
+				Data:       tt.data,
+			}
+
+			var writes []string
+
+			c := fake.NewClientBuilder().WithObjects(secret).WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					writes = append(writes, "create")
+					return c.Create(ctx, obj, opts...)
+				},
+				Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+					writes = append(writes, "update")
+					return c.Update(ctx, obj, opts...)
+				},
+				Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+					writes = append(writes, "patch")
+					return c.Patch(ctx, obj, patch, opts...)
+				},
+				Delete: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.DeleteOption) error {
+					writes = append(writes, "delete")
+					return c.Delete(ctx, obj, opts...)
+				},
+			}).Build()
+
+			r := &BaseReconciler{Client: c}
+
+			owner := &v1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "account", Namespace: "nats", UID: "uid"}}
+
+			kp, seed, err := r.adoptSeedSecret(context.Background(), owner, nkeys.PrefixByteAccount, tt.ref)
+			if tt.wantReason != "" {
+				cerr, ok := errors.Into[*conditionError](err)
+				if !ok {
+					t.Fatalf("expected condition error, got %v", err)
+				}
+
+				if cerr.reason != tt.wantReason {
+					t.Errorf("condition reason = %q, want %q", cerr.reason, tt.wantReason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			want := v1alpha1.KeyPair{
+				PublicKey:         accountPub,
+				SeedSecretName:    "nsc",
+				SeedSecretKey:     "account.nk",
+				ExternallyManaged: true,
+			}
+
+			if *kp != want {
+				t.Errorf("KeyPair = %+v, want %+v", *kp, want)
+			}
+
+			if string(seed) != string(accountSeed) {
+				t.Errorf("seed does not match adopted seed")
+			}
+
+			got := new(corev1.Secret)
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(secret), got); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got.OwnerReferences) != 0 || len(got.Labels) != 0 || len(got.Finalizers) != 0 {
+				t.Errorf("adopted secret was modified: %+v", got.ObjectMeta)
+			}
+
+			for _, verb := range writes {
+				t.Errorf("unexpected %s action on adopted secret", verb)
+			}
+		})
+	}
+}
+
+func Test_newClaimsSummary(t *testing.T) {
+	accountKP, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountPub, err := accountKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signingKP, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signingPub, err := signingKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userKP, err := nkeys.CreateUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userPub, err := userKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ac := jwt.NewAccountClaims(accountPub)
+	ac.Expires = 2000000000
+	ac.Exports.Add(&jwt.Export{Subject: "orders.>", Type: jwt.Service})
+	ac.Imports.Add(&jwt.Import{Subject: "billing.>", Account: accountPub, Type: jwt.Stream}, &jwt.Import{Subject: "audit.>", Account: accountPub, Type: jwt.Stream})
+	ac.SigningKeys.Add(signingPub)
+
+	ajwt, err := ac.Encode(accountKP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uc := jwt.NewUserClaims(userPub)
+	uc.IssuerAccount = accountPub
+
+	ujwt, err := uc.Encode(signingKP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		want    func(t *testing.T, got v1alpha1.ClaimsSummary)
+		wantErr bool
+	}{
+		{
+			name:  "account",
+			token: ajwt,
+			want: func(t *testing.T, got v1alpha1.ClaimsSummary) {
+				if got.JTI != ac.ID || got.Issuer != accountPub || got.IssuedAt.Unix() != ac.IssuedAt {
+					t.Errorf("got %+v, want jti %s, issuer %s and issued at %d", got, ac.ID, accountPub, ac.IssuedAt)
+				}
+
+				if got.Expires == nil || got.Expires.Unix() != ac.Expires {
+					t.Errorf("got expires %v, want %d", got.Expires, ac.Expires)
+				}
+
+				if got.Exports != 1 || got.Imports != 2 || got.SigningKeys != 1 {
+					t.Errorf("got %d exports, %d imports and %d signing keys, want 1, 2 and 1", got.Exports, got.Imports, got.SigningKeys)
+				}
+
+				if len(got.Hash) != 64 {
+					t.Errorf("got hash %q, want hex encoded SHA-256", got.Hash)
+				}
+			},
+		},
+		{
+			name:  "user issued by signing key",
+			token: ujwt,
+			want: func(t *testing.T, got v1alpha1.ClaimsSummary) {
+				if got.Issuer != signingPub || got.IssuerAccount != accountPub {
+					t.Errorf("got issuer %s and issuer account %s, want %s and %s", got.Issuer, got.IssuerAccount, signingPub, accountPub)
+				}
+
+				if got.Expires != nil {
+					t.Errorf("got expires %v, want nil", got.Expires)
+				}
+			},
+		},
+		{
+			name:    "invalid",
+			token:   "not a jwt",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newClaimsSummary(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newClaimsSummary() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.want != nil {
+				tt.want(t, got)
+			}
+		})
+	}
+}
+
+func Test_isPaused(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		want        bool
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:        "paused",
+			annotations: map[string]string{v1alpha1.AnnotationPaused: "true"},
+			want:        true,
+		},
+		{
+			name:        "not paused",
+			annotations: map[string]string{v1alpha1.AnnotationPaused: "false"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := record.NewFakeRecorder(1)
+
+			user := &v1alpha1.User{ObjectMeta: metav1.ObjectMeta{Name: "user", Annotations: tt.annotations}}
+
+			if got := isPaused(recorder, user); got != tt.want {
+				t.Errorf("isPaused() = %v, want %v", got, tt.want)
+			}
+
+			if gotEvent := len(recorder.Events) == 1; gotEvent != tt.want {
+				t.Errorf("isPaused() recorded event = %v, want %v", gotEvent, tt.want)
+			}
+		})
+	}
+}
+
+func Test_forcePushRequest(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		lastHandled string
+		want        string
+	}{
+		{
+			name: "no annotation",
+		},
+		{
+			name:        "pending",
+			annotations: map[string]string{v1alpha1.AnnotationForcePushAt: "2024-05-01T10:00:00Z"},
+			want:        "2024-05-01T10:00:00Z",
+		},
+		{
+			name:        "handled",
+			annotations: map[string]string{v1alpha1.AnnotationForcePushAt: "2024-05-01T10:00:00Z"},
+			lastHandled: "2024-05-01T10:00:00Z",
+		},
+		{
+			name:        "new request after handled",
+			annotations: map[string]string{v1alpha1.AnnotationForcePushAt: "2024-05-02T10:00:00Z"},
+			lastHandled: "2024-05-01T10:00:00Z",
+			want:        "2024-05-02T10:00:00Z",
+		},
+		{
+			name:        "annotation removed",
+			lastHandled: "2024-05-01T10:00:00Z",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := &v1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "account", Annotations: tt.annotations}}
+
+			if got := forcePushRequest(account, tt.lastHandled); got != tt.want {
+				t.Errorf("forcePushRequest() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package controllers
+
+import (
+	"context"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/internal/controller/accounts/resources"
+)
+
+// CacheByObject returns the per-object cache options for the manager. Secrets are only cached when they have the
+// resources.LabelSecretType label, which is set on every Secret created by the controllers, so the operator does not
+// hold every Secret in the cluster in memory.
+func CacheByObject() map[client.Object]cache.ByObject {
+	managed, err := labels.NewRequirement(resources.LabelSecretType, selection.Exists, nil)
+	if err != nil {
+		// the requirement is static so this can only fail if the label key is invalid
+		panic(err)
+	}
+
+	return map[client.Object]cache.ByObject{
+		&v1.Secret{}: {
+			Label: labels.NewSelector().Add(*managed),
+		},
+	}
+}
+
+// NewClient returns a client which reads through the manager cache c. Secrets which are not found in the cache are read
+// from apiReader, since user-provided Secrets, such as those referenced by existingSeedSecretRef, are not labelled and
+// therefore excluded from the cache by CacheByObject.
+func NewClient(c client.Client, apiReader client.Reader) client.Client {
+	return &secretFallbackClient{Client: c, apiReader: apiReader}
+}
+
+type secretFallbackClient struct {
+	client.Client
+	apiReader client.Reader
+}
+
+func (c *secretFallbackClient) Get(ctx context.Context, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+	err := c.Client.Get(ctx, key, obj, opts...)
+	if _, ok := obj.(*v1.Secret); !ok || !errors.IsNotFound(err) {
+		return err
+	}
+
+	return c.apiReader.Get(ctx, key, obj, opts...)
+}
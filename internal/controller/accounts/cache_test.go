@@ -0,0 +1,57 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/versori-oss/nats-account-operator/internal/controller/accounts/resources"
+)
+
+func TestCacheByObject(t *testing.T) {
+	var selector labels.Selector
+
+	for obj, opts := range CacheByObject() {
+		if _, ok := obj.(*v1.Secret); ok {
+			selector = opts.Label
+		}
+	}
+
+	if selector == nil {
+		t.Fatal("CacheByObject() has no label selector for Secrets")
+	}
+
+	if !selector.Matches(labels.Set{resources.LabelSecretType: resources.LabelSecretTypeJWT}) {
+		t.Errorf("selector %q does not match managed secrets", selector)
+	}
+
+	if selector.Matches(labels.Set{"app": "nats"}) {
+		t.Errorf("selector %q matches unmanaged secrets", selector)
+	}
+}
+
+func TestNewClient(t *testing.T) {
+	unmanaged := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "nsc", Namespace: "nats"}}
+	unmanagedNS := &v1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "nats"}}
+
+	// the cached client does not see the unlabelled objects, the API reader does
+	c := NewClient(fake.NewClientBuilder().Build(), fake.NewClientBuilder().WithObjects(unmanaged, unmanagedNS).Build())
+
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(unmanaged), new(v1.Secret)); err != nil {
+		t.Errorf("Get(Secret) error = %v, want secret read from the API reader", err)
+	}
+
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(unmanagedNS), new(v1.Namespace)); !errors.IsNotFound(err) {
+		t.Errorf("Get(Namespace) error = %v, want NotFound from the cache", err)
+	}
+
+	if err := c.Get(context.Background(), client.ObjectKey{Namespace: "nats", Name: "missing"}, new(v1.Secret)); !errors.IsNotFound(err) {
+		t.Errorf("Get(missing Secret) error = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+)
+
+var (
+	userJWTExpiryDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "user", "jwt_expiry_timestamp_seconds"),
+		"Unix time at which the JWT of a User expires, Users whose JWT does not expire are not reported.",
+		[]string{"namespace", "name"}, nil,
+	)
+
+	managedResourcesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "managed_resources"),
+		"Number of Accounts, Users and SigningKeys managed by an Operator.",
+		[]string{"operator", "kind"}, nil,
+	)
+
+	resourceReadyDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(metricsNamespace, "", "resource_ready"),
+		"The status of the Ready condition of a resource, 1 for the current status and 0 otherwise.",
+		[]string{"kind", "namespace", "name", "status"}, nil,
+	)
+
+	conditionStatuses = []corev1.ConditionStatus{corev1.ConditionTrue, corev1.ConditionFalse, corev1.ConditionUnknown}
+)
+
+// ResourceCollector is a prometheus.Collector reporting the state of the Operators, Accounts, Users and SigningKeys in
+// the cluster. The resources are listed from reader, which should be the manager's cache, every time metrics are
+// scraped.
+type ResourceCollector struct {
+	reader client.Reader
+}
+
+var _ prometheus.Collector = (*ResourceCollector)(nil)
+
+func NewResourceCollector(reader client.Reader) *ResourceCollector {
+	return &ResourceCollector{reader: reader}
+}
+
+func (c *ResourceCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- userJWTExpiryDesc
+	ch <- managedResourcesDesc
+	ch <- resourceReadyDesc
+}
+
+func (c *ResourceCollector) Collect(ch chan<- prometheus.Metric) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	logger := log.FromContext(ctx).WithName("ResourceCollector")
+
+	var (
+		operators   v1alpha1.OperatorList
+		accounts    v1alpha1.AccountList
+		users       v1alpha1.UserList
+		signingKeys v1alpha1.SigningKeyList
+	)
+
+	for _, list := range []client.ObjectList{&operators, &accounts, &users, &signingKeys} {
+		if err := c.reader.List(ctx, list); err != nil {
+			// the cache is not started until the manager is, so errors are expected until then
+			logger.V(1).Info("failed to list resources for metrics", "error", err.Error())
+
+			return
+		}
+	}
+
+	for i := range operators.Items {
+		op := &operators.Items[i]
+		collectReady(ch, "Operator", op, op.Status.GetConditions())
+	}
+
+	// accountOperators maps the namespace/name of each Account to that of its Operator
+	accountOperators := make(map[string]string, len(accounts.Items))
+	managed := make(map[[2]string]int)
+
+	for i := range accounts.Items {
+		acc := &accounts.Items[i]
+		collectReady(ch, "Account", acc, acc.Status.GetConditions())
+
+		if ref := acc.Status.OperatorRef; ref != nil {
+			operator := refIndexKey(namespaceOrDefault(ref.Namespace, acc.Namespace), ref.Name)
+
+			accountOperators[client.ObjectKeyFromObject(acc).String()] = operator
+			managed[[2]string{operator, "Account"}]++
+		}
+	}
+
+	for i := range users.Items {
+		usr := &users.Items[i]
+		collectReady(ch, "User", usr, usr.Status.GetConditions())
+
+		if claims := usr.Status.Claims; claims != nil && claims.Expires != nil {
+			ch <- prometheus.MustNewConstMetric(userJWTExpiryDesc, prometheus.GaugeValue,
+				float64(claims.Expires.Unix()), usr.Namespace, usr.Name)
+		}
+
+		if ref := usr.Status.AccountRef; ref != nil {
+			account := refIndexKey(namespaceOrDefault(ref.Namespace, usr.Namespace), ref.Name)
+			if operator, ok := accountOperators[account]; ok {
+				managed[[2]string{operator, "User"}]++
+			}
+		}
+	}
+
+	operatorGVK := v1alpha1.GroupVersion.WithKind("Operator")
+
+	for i := range signingKeys.Items {
+		sk := &signingKeys.Items[i]
+		collectReady(ch, "SigningKey", sk, sk.Status.GetConditions())
+
+		ref := sk.Status.OwnerRef
+		if ref == nil {
+			continue
+		}
+
+		owner := refIndexKey(namespaceOrDefault(ref.Namespace, sk.Namespace), ref.Name)
+		if ref.GetGroupVersionKind() == operatorGVK {
+			managed[[2]string{owner, "SigningKey"}]++
+		} else if operator, ok := accountOperators[owner]; ok {
+			managed[[2]string{operator, "SigningKey"}]++
+		}
+	}
+
+	for key, count := range managed {
+		ch <- prometheus.MustNewConstMetric(managedResourcesDesc, prometheus.GaugeValue, float64(count), key[0], key[1])
+	}
+}
+
+func collectReady(ch chan<- prometheus.Metric, kind string, obj client.Object, conditions apis.Conditions) {
+	current := corev1.ConditionUnknown
+
+	for _, cond := range conditions {
+		if cond.Type == apis.ConditionReady {
+			current = cond.Status
+		}
+	}
+
+	for _, status := range conditionStatuses {
+		value := 0.0
+		if status == current {
+			value = 1
+		}
+
+		ch <- prometheus.MustNewConstMetric(resourceReadyDesc, prometheus.GaugeValue, value,
+			kind, obj.GetNamespace(), obj.GetName(), string(status))
+	}
+}
+
+func namespaceOrDefault(namespace, fallback string) string {
+	if namespace == "" {
+		return fallback
+	}
+
+	return namespace
+}
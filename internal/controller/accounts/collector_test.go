@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+)
+
+func TestResourceCollector(t *testing.T) {
+	expires := metav1.NewTime(time.Unix(1900000000, 0))
+
+	ready := v1alpha1.Status{Conditions: apis.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}}
+
+	objs := []client.Object{
+		&v1alpha1.Operator{
+			ObjectMeta: metav1.ObjectMeta{Name: "nats", Namespace: "a"},
+			Status:     v1alpha1.OperatorStatus{Status: ready},
+		},
+		&v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "b"},
+			Status:     v1alpha1.AccountStatus{OperatorRef: &v1alpha1.InferredObjectReference{Namespace: "a", Name: "nats"}},
+		},
+		&v1alpha1.User{
+			ObjectMeta: metav1.ObjectMeta{Name: "expiring", Namespace: "b"},
+			Status: v1alpha1.UserStatus{
+				AccountRef: &v1alpha1.InferredObjectReference{Name: "app"},
+				Claims:     &v1alpha1.ClaimsSummary{Expires: &expires},
+			},
+		},
+		&v1alpha1.User{
+			ObjectMeta: metav1.ObjectMeta{Name: "unresolved", Namespace: "b"},
+		},
+		&v1alpha1.SigningKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "op-sk", Namespace: "a"},
+			Status: v1alpha1.SigningKeyStatus{OwnerRef: &v1alpha1.TypedObjectReference{
+				APIVersion: v1alpha1.GroupVersion.String(), Kind: "Operator", Namespace: "a", Name: "nats",
+			}},
+		},
+		&v1alpha1.SigningKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-sk", Namespace: "b"},
+			Status: v1alpha1.SigningKeyStatus{OwnerRef: &v1alpha1.TypedObjectReference{
+				APIVersion: v1alpha1.GroupVersion.String(), Kind: "Account", Namespace: "b", Name: "app",
+			}},
+		},
+	}
+
+	collector := NewResourceCollector(newIndexedClient(t, objs...))
+
+	want := `
+# HELP nats_account_operator_managed_resources Number of Accounts, Users and SigningKeys managed by an Operator.
+# TYPE nats_account_operator_managed_resources gauge
+nats_account_operator_managed_resources{kind="Account",operator="a/nats"} 1
+nats_account_operator_managed_resources{kind="SigningKey",operator="a/nats"} 2
+nats_account_operator_managed_resources{kind="User",operator="a/nats"} 1
+# HELP nats_account_operator_user_jwt_expiry_timestamp_seconds Unix time at which the JWT of a User expires, Users whose JWT does not expire are not reported.
+# TYPE nats_account_operator_user_jwt_expiry_timestamp_seconds gauge
+nats_account_operator_user_jwt_expiry_timestamp_seconds{name="expiring",namespace="b"} 1.9e+09
+`
+
+	if err := testutil.CollectAndCompare(collector, strings.NewReader(want),
+		"nats_account_operator_managed_resources",
+		"nats_account_operator_user_jwt_expiry_timestamp_seconds",
+	); err != nil {
+		t.Error(err)
+	}
+
+	// every resource reports all three statuses
+	if got := testutil.CollectAndCount(collector, "nats_account_operator_resource_ready"); got != 3*len(objs) {
+		t.Errorf("resource_ready series = %d, want %d", got, 3*len(objs))
+	}
+
+	wantReady := `
+# HELP nats_account_operator_resource_ready The status of the Ready condition of a resource, 1 for the current status and 0 otherwise.
+# TYPE nats_account_operator_resource_ready gauge
+nats_account_operator_resource_ready{kind="Operator",name="nats",namespace="a",status="False"} 0
+nats_account_operator_resource_ready{kind="Operator",name="nats",namespace="a",status="True"} 1
+nats_account_operator_resource_ready{kind="Operator",name="nats",namespace="a",status="Unknown"} 0
+`
+
+	operatorOnly := NewResourceCollector(newIndexedClient(t, objs[0]))
+	if err := testutil.CollectAndCompare(operatorOnly, strings.NewReader(wantReady), "nats_account_operator_resource_ready"); err != nil {
+		t.Error(err)
+	}
+}
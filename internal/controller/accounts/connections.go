@@ -0,0 +1,406 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/multierr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+)
+
+// DefaultConnectionFlushInterval is how often connection activity is written to User status by default.
+const DefaultConnectionFlushInterval = 10 * time.Second
+
+// ConnectionTracker subscribes to the client connect and disconnect events of every ready Operator and records the
+// connection activity of Users in their status. Events are aggregated per User public key and written at most once
+// every FlushInterval, so a busy cluster does not cause a status write per connection.
+type ConnectionTracker struct {
+	*BaseReconciler
+
+	// FlushInterval is how often aggregated events are written to User status, and how often the subscriptions are
+	// synchronised with the Operators.
+	FlushInterval time.Duration
+
+	logger logr.Logger
+
+	mu      sync.Mutex
+	pending map[string]*userActivity
+
+	// subscriptions is only accessed by the Start goroutine
+	subscriptions map[types.NamespacedName]*operatorSubscription
+}
+
+// userActivity is the connection activity of a User aggregated since the last flush.
+type userActivity struct {
+	// active is the number of open connections of the User reported by the account server after subscribing, when set
+	// it replaces the active count in the status before connects and disconnects are applied.
+	active *int64
+
+	connects           int64
+	disconnects        int64
+	lastConnectedAt    time.Time
+	lastDisconnectedAt time.Time
+	lastClientIP       string
+}
+
+type operatorSubscription struct {
+	publisher nsc.AccountPublisher
+	// generation of the Operator when the subscription was made, the subscription is recreated when it changes.
+	generation int64
+	// reconnected is set by the connection of publisher when it reconnects, since events may have been missed while it
+	// was disconnected. The active connections are re-synced, and it is cleared, by the next syncSubscriptions.
+	reconnected atomic.Bool
+}
+
+var _ manager.Runnable = (*ConnectionTracker)(nil)
+var _ manager.LeaderElectionRunnable = (*ConnectionTracker)(nil)
+
+func NewConnectionTracker(base *BaseReconciler, flushInterval time.Duration) *ConnectionTracker {
+	if flushInterval <= 0 {
+		flushInterval = DefaultConnectionFlushInterval
+	}
+
+	return &ConnectionTracker{
+		BaseReconciler: base,
+		FlushInterval:  flushInterval,
+		logger:         log.Log.WithName("connection-tracker"),
+		pending:        make(map[string]*userActivity),
+		subscriptions:  make(map[types.NamespacedName]*operatorSubscription),
+	}
+}
+
+// NeedLeaderElection returns true so that events are only counted once.
+func (t *ConnectionTracker) NeedLeaderElection() bool {
+	return true
+}
+
+// Start subscribes to the connection events of each Operator and flushes them to User status every FlushInterval until
+// the context is cancelled.
+func (t *ConnectionTracker) Start(ctx context.Context) error {
+	ctx = log.IntoContext(ctx, t.logger)
+
+	defer t.closeSubscriptions()
+
+	ticker := time.NewTicker(t.FlushInterval)
+	defer ticker.Stop()
+
+	t.syncSubscriptions(ctx)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			t.flush(ctx)
+			t.syncSubscriptions(ctx)
+		}
+	}
+}
+
+// syncSubscriptions subscribes to the account server of each ready Operator, and closes the subscriptions of Operators
+// which were deleted or are no longer ready.
+func (t *ConnectionTracker) syncSubscriptions(ctx context.Context) {
+	var operators v1alpha1.OperatorList
+
+	if err := t.List(ctx, &operators); err != nil {
+		t.logger.Error(err, "failed to list operators")
+
+		return
+	}
+
+	ready := make(map[types.NamespacedName]bool, len(operators.Items))
+
+	for i := range operators.Items {
+		operator := &operators.Items[i]
+		key := client.ObjectKeyFromObject(operator)
+
+		if !operator.Status.IsReady() || operator.Spec.AccountServerURL == "" {
+			continue
+		}
+
+		ready[key] = true
+
+		if sub, ok := t.subscriptions[key]; ok {
+			if sub.generation == operator.Generation {
+				if sub.reconnected.Swap(false) {
+					t.resync(ctx, operator, sub.publisher)
+				}
+
+				continue
+			}
+
+			sub.publisher.Close()
+			delete(t.subscriptions, key)
+		}
+
+		sub := &operatorSubscription{generation: operator.Generation}
+
+		publisher, err := t.subscribe(ctx, operator, nats.ReconnectHandler(func(*nats.Conn) {
+			t.logger.Info("reconnected to the account server, active user connections will be re-synced", "operator", key)
+
+			sub.reconnected.Store(true)
+		}))
+		if err != nil {
+			t.logger.Error(err, "failed to subscribe to connection events", "operator", key)
+
+			continue
+		}
+
+		sub.publisher = publisher
+		t.subscriptions[key] = sub
+
+		t.resync(ctx, operator, publisher)
+	}
+
+	for key, sub := range t.subscriptions {
+		if !ready[key] {
+			sub.publisher.Close()
+			delete(t.subscriptions, key)
+		}
+	}
+}
+
+func (t *ConnectionTracker) subscribe(ctx context.Context, operator *v1alpha1.Operator, opts ...nats.Option) (nsc.AccountPublisher, error) {
+	publisher, err := t.connectSystemUser(ctx, operator, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := publisher.SubscribeConnectionEvents(t.record); err != nil {
+		publisher.Close()
+
+		return nil, err
+	}
+
+	return publisher, nil
+}
+
+// resync calls resyncActive, logging rather than returning its error since the subscription is kept regardless.
+func (t *ConnectionTracker) resync(ctx context.Context, operator *v1alpha1.Operator, publisher nsc.AccountPublisher) {
+	if err := t.resyncActive(ctx, operator, publisher); err != nil {
+		t.logger.Error(err, "failed to re-sync active user connections", "operator", client.ObjectKeyFromObject(operator))
+	}
+}
+
+// resyncActive replaces the active connection count of the Users of operator with the open connections reported by its
+// account server. It is called after subscribing and after the subscription reconnects, so that events missed while
+// there was no subscription, such as before a restart, a change of leader or while disconnected, do not leave the
+// count wrong until the connections close.
+func (t *ConnectionTracker) resyncActive(ctx context.Context, operator *v1alpha1.Operator, publisher nsc.AccountPublisher) error {
+	var accounts v1alpha1.AccountList
+
+	if err := t.List(ctx, &accounts, client.MatchingFields{
+		IndexStatusOperatorRef: refIndexKey(operator.Namespace, operator.Name),
+	}); err != nil {
+		return fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	var errs error
+
+	for i := range accounts.Items {
+		acc := &accounts.Items[i]
+
+		if acc.Status.KeyPair == nil {
+			continue
+		}
+
+		if err := t.resyncAccountActive(ctx, acc, publisher); err != nil {
+			errs = multierr.Append(errs, fmt.Errorf("account %s: %w", client.ObjectKeyFromObject(acc), err))
+		}
+	}
+
+	return errs
+}
+
+func (t *ConnectionTracker) resyncAccountActive(ctx context.Context, acc *v1alpha1.Account, publisher nsc.AccountPublisher) error {
+	reqCtx, cancel := context.WithTimeout(ctx, usageRequestTimeout)
+	defer cancel()
+
+	conns, err := publisher.ConnectionsByUser(reqCtx, acc.Status.KeyPair.PublicKey)
+	if err != nil {
+		return err
+	}
+
+	var users v1alpha1.UserList
+
+	if err := t.List(ctx, &users, client.MatchingFields{
+		IndexStatusAccountRef: refIndexKey(acc.Namespace, acc.Name),
+	}); err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	for i := range users.Items {
+		usr := &users.Items[i]
+
+		if usr.Status.KeyPair == nil {
+			continue
+		}
+
+		// events recorded before the snapshot are already included in it
+		activity := t.pendingActivity(usr.Status.KeyPair.PublicKey)
+		activity.active = ptr.To(int64(conns[usr.Status.KeyPair.PublicKey]))
+		activity.connects, activity.disconnects = 0, 0
+	}
+
+	return nil
+}
+
+func (t *ConnectionTracker) closeSubscriptions() {
+	for key, sub := range t.subscriptions {
+		sub.publisher.Close()
+		delete(t.subscriptions, key)
+	}
+}
+
+// record aggregates event into the pending activity of its User.
+func (t *ConnectionTracker) record(event nsc.ConnectionEvent) {
+	if event.User == "" {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	activity := t.pendingActivity(event.User)
+
+	switch event.Type {
+	case nsc.ConnectionEventConnect:
+		activity.connects++
+
+		if !event.Time.Before(activity.lastConnectedAt) {
+			activity.lastConnectedAt = event.Time
+			activity.lastClientIP = event.Host
+		}
+	case nsc.ConnectionEventDisconnect:
+		activity.disconnects++
+
+		if event.Time.After(activity.lastDisconnectedAt) {
+			activity.lastDisconnectedAt = event.Time
+		}
+	}
+}
+
+// pendingActivity returns the activity recorded for the User with publicKey since the last flush, t.mu must be held.
+func (t *ConnectionTracker) pendingActivity(publicKey string) *userActivity {
+	activity, ok := t.pending[publicKey]
+	if !ok {
+		activity = &userActivity{}
+		t.pending[publicKey] = activity
+	}
+
+	return activity
+}
+
+// flush writes the activity recorded since the last flush to the status of the Users it belongs to. Events for public
+// keys which do not belong to a User, such as the operator's own temporary users, are discarded.
+func (t *ConnectionTracker) flush(ctx context.Context) {
+	t.mu.Lock()
+	pending := t.pending
+	t.pending = make(map[string]*userActivity, len(pending))
+	t.mu.Unlock()
+
+	for publicKey, activity := range pending {
+		if err := t.updateUsers(ctx, publicKey, activity); err != nil {
+			t.logger.Error(err, "failed to record user connections, retrying on the next flush", "publicKey", publicKey)
+
+			t.retry(publicKey, activity)
+		}
+	}
+}
+
+// retry returns activity which failed to be written to the pending activity of its User, ahead of any activity
+// recorded since the flush.
+func (t *ConnectionTracker) retry(publicKey string, activity *userActivity) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if later, ok := t.pending[publicKey]; ok {
+		activity.merge(later)
+	}
+
+	t.pending[publicKey] = activity
+}
+
+func (t *ConnectionTracker) updateUsers(ctx context.Context, publicKey string, activity *userActivity) error {
+	var users v1alpha1.UserList
+
+	if err := t.List(ctx, &users, client.MatchingFields{IndexStatusPublicKey: publicKey}); err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	for i := range users.Items {
+		usr := &users.Items[i]
+
+		// the activity is a delta, so it must be applied to the latest status, see patchStatusWithRetry
+		if err := patchStatusWithRetry(ctx, t.Client, usr, func(usr *v1alpha1.User) bool {
+			original := usr.Status.Connections.DeepCopy()
+
+			if usr.Status.Connections == nil {
+				usr.Status.Connections = &v1alpha1.UserConnections{}
+			}
+
+			activity.applyTo(usr.Status.Connections)
+
+			return !equality.Semantic.DeepEqual(original, usr.Status.Connections)
+		}); err != nil {
+			return fmt.Errorf("failed to patch user %s status: %w", client.ObjectKeyFromObject(usr), err)
+		}
+	}
+
+	return nil
+}
+
+// merge adds the activity recorded after a to a, so that applying a has the effect of applying both in order.
+func (a *userActivity) merge(later *userActivity) {
+	if later.active != nil {
+		// the snapshot includes the connects and disconnects recorded before it
+		a.active = later.active
+		a.connects, a.disconnects = 0, 0
+	}
+
+	a.connects += later.connects
+	a.disconnects += later.disconnects
+
+	if !later.lastConnectedAt.Before(a.lastConnectedAt) {
+		a.lastConnectedAt = later.lastConnectedAt
+		a.lastClientIP = later.lastClientIP
+	}
+
+	if later.lastDisconnectedAt.After(a.lastDisconnectedAt) {
+		a.lastDisconnectedAt = later.lastDisconnectedAt
+	}
+}
+
+func (a *userActivity) applyTo(conns *v1alpha1.UserConnections) {
+	if a.active != nil {
+		conns.Active = *a.active
+	}
+
+	conns.Active = max(conns.Active+a.connects-a.disconnects, 0)
+
+	if !a.lastConnectedAt.IsZero() && (conns.LastConnectedAt == nil || a.lastConnectedAt.After(conns.LastConnectedAt.Time)) {
+		conns.LastConnectedAt = &metav1.Time{Time: a.lastConnectedAt}
+		conns.LastClientIP = a.lastClientIP
+	}
+
+	if !a.lastDisconnectedAt.IsZero() && (conns.LastDisconnectedAt == nil || a.lastDisconnectedAt.After(conns.LastDisconnectedAt.Time)) {
+		conns.LastDisconnectedAt = &metav1.Time{Time: a.lastDisconnectedAt}
+	}
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+	nscfake "github.com/versori-oss/nats-account-operator/pkg/nsc/fake"
+)
+
+func TestConnectionTracker(t *testing.T) {
+	const (
+		accountPub = "ACCOUNT"
+		userPub    = "USER"
+	)
+
+	start := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	operator := &v1alpha1.Operator{
+		ObjectMeta: metav1.ObjectMeta{Name: "nats", Namespace: "a"},
+		Spec:       v1alpha1.OperatorSpec{AccountServerURL: "nats://nats:4222"},
+		Status: v1alpha1.OperatorStatus{Status: v1alpha1.Status{
+			Conditions: apis.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}},
+		}},
+	}
+
+	acc := &v1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "b"},
+		Status: v1alpha1.AccountStatus{
+			OperatorRef: &v1alpha1.InferredObjectReference{Namespace: "a", Name: "nats"},
+			KeyPair:     &v1alpha1.KeyPair{PublicKey: accountPub},
+		},
+	}
+
+	usr := &v1alpha1.User{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "b"},
+		Status: v1alpha1.UserStatus{
+			AccountRef: &v1alpha1.InferredObjectReference{Namespace: "b", Name: "app"},
+			KeyPair:    &v1alpha1.KeyPair{PublicKey: userPub},
+		},
+	}
+
+	server, err := nscfake.NewAccountServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	c := newIndexedClient(t, operator, acc, usr)
+	ctx := context.Background()
+
+	tracker := NewConnectionTracker(&BaseReconciler{
+		Client:            c,
+		AccountServer:     server,
+		SystemCredentials: &nscfake.SystemCredentialsLoader{},
+	}, time.Second)
+
+	tracker.syncSubscriptions(ctx)
+	defer tracker.closeSubscriptions()
+
+	event := func(eventType nsc.ConnectionEventType, user, host string, offset time.Duration) {
+		server.EmitConnectionEvent(nsc.ConnectionEvent{
+			Type: eventType, Time: start.Add(offset), Account: accountPub, User: user, Host: host,
+		})
+	}
+
+	get := func() *v1alpha1.UserConnections {
+		t.Helper()
+
+		got := new(v1alpha1.User)
+		if err := c.Get(ctx, client.ObjectKeyFromObject(usr), got); err != nil {
+			t.Fatal(err)
+		}
+
+		return got.Status.Connections
+	}
+
+	event(nsc.ConnectionEventConnect, userPub, "10.0.0.1", 0)
+	event(nsc.ConnectionEventConnect, userPub, "10.0.0.2", time.Second)
+	event(nsc.ConnectionEventDisconnect, userPub, "10.0.0.1", 2*time.Second)
+	event(nsc.ConnectionEventConnect, "UNMANAGED", "10.0.0.3", 3*time.Second)
+
+	tracker.flush(ctx)
+
+	conns := get()
+	if conns == nil {
+		t.Fatal("status.connections not set after flush")
+	}
+
+	if conns.Active != 1 || conns.LastClientIP != "10.0.0.2" ||
+		!conns.LastConnectedAt.Time.Equal(start.Add(time.Second)) ||
+		!conns.LastDisconnectedAt.Time.Equal(start.Add(2*time.Second)) {
+		t.Errorf("status.connections = %+v, want 1 active, last connected from 10.0.0.2 at +1s, disconnected at +2s", conns)
+	}
+
+	// disconnects of connections opened before the controller started must not make the count negative
+	event(nsc.ConnectionEventDisconnect, userPub, "10.0.0.2", 4*time.Second)
+	event(nsc.ConnectionEventDisconnect, userPub, "10.0.0.9", 5*time.Second)
+
+	tracker.flush(ctx)
+
+	conns = get()
+	if conns.Active != 0 || conns.LastClientIP != "10.0.0.2" || !conns.LastDisconnectedAt.Time.Equal(start.Add(5*time.Second)) {
+		t.Errorf("status.connections = %+v, want 0 active, last disconnected at +5s", conns)
+	}
+
+	// the subscription is closed once the Operator is no longer ready
+	notReady := operator.DeepCopy()
+	notReady.Status.Conditions = nil
+
+	if err := c.Status().Update(ctx, notReady); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker.syncSubscriptions(ctx)
+
+	if len(tracker.subscriptions) != 0 {
+		t.Errorf("subscriptions = %v, want none after the Operator is no longer ready", tracker.subscriptions)
+	}
+
+	// connections opened while there was no subscription are counted once the Operator is subscribed to again
+	server.SetConnections(accountPub, map[string]int{userPub: 2, "UNMANAGED": 1})
+
+	ready := new(v1alpha1.Operator)
+	if err := c.Get(ctx, client.ObjectKeyFromObject(operator), ready); err != nil {
+		t.Fatal(err)
+	}
+
+	ready.Status.Conditions = operator.Status.Conditions
+
+	if err := c.Status().Update(ctx, ready); err != nil {
+		t.Fatal(err)
+	}
+
+	tracker.syncSubscriptions(ctx)
+
+	event(nsc.ConnectionEventConnect, userPub, "10.0.0.4", 6*time.Second)
+
+	tracker.flush(ctx)
+
+	if conns = get(); conns.Active != 3 || conns.LastClientIP != "10.0.0.4" {
+		t.Errorf("status.connections = %+v, want 3 active, last connected from 10.0.0.4", conns)
+	}
+
+	// activity which fails to be written is kept for the next flush
+	tracker.Client = &failingStatusClient{Client: c}
+
+	event(nsc.ConnectionEventConnect, userPub, "10.0.0.5", 7*time.Second)
+
+	tracker.flush(ctx)
+
+	if conns = get(); conns.Active != 3 {
+		t.Errorf("status.connections = %+v, want 3 active after a failed flush", conns)
+	}
+
+	tracker.Client = c
+
+	event(nsc.ConnectionEventDisconnect, userPub, "10.0.0.4", 8*time.Second)
+
+	tracker.flush(ctx)
+
+	if conns = get(); conns.Active != 3 || conns.LastClientIP != "10.0.0.5" ||
+		!conns.LastDisconnectedAt.Time.Equal(start.Add(8*time.Second)) {
+		t.Errorf("status.connections = %+v, want 3 active, last connected from 10.0.0.5, disconnected at +8s", conns)
+	}
+
+	// connections closed while the subscription was disconnected are counted once it reconnects
+	server.SetConnections(accountPub, map[string]int{userPub: 1})
+	server.Reconnect()
+
+	tracker.syncSubscriptions(ctx)
+	tracker.flush(ctx)
+
+	if conns = get(); conns.Active != 1 {
+		t.Errorf("status.connections = %+v, want 1 active after reconnecting", conns)
+	}
+}
+
+// failingStatusClient is a client.Client whose status writes fail.
+type failingStatusClient struct {
+	client.Client
+}
+
+func (c *failingStatusClient) Status() client.SubResourceWriter {
+	return failingStatusWriter{c.Client.Status()}
+}
+
+type failingStatusWriter struct {
+	client.SubResourceWriter
+}
+
+func (failingStatusWriter) Patch(context.Context, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+	return errors.New("the server is currently unable to handle the request")
+}
@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// Field indexes used by the watch map functions to find the resources referencing a changed object. Each reference
+// index stores the referenced object's namespace/name and, when the reference records it, its UID.
+const (
+	// IndexStatusOperatorRef indexes Accounts by status.operatorRef.
+	IndexStatusOperatorRef = "status.operatorRef"
+	// IndexStatusAccountRef indexes Users by status.accountRef.
+	IndexStatusAccountRef = "status.accountRef"
+	// IndexSpecIssuerRef indexes Accounts and Users by spec.issuer.ref.
+	IndexSpecIssuerRef = "spec.issuer.ref"
+	// IndexStatusOwnerRef indexes SigningKeys by status.ownerRef.
+	IndexStatusOwnerRef = "status.ownerRef"
+	// IndexStatusPublicKey indexes Users by status.keyPair.publicKey.
+	IndexStatusPublicKey = "status.keyPair.publicKey"
+	// IndexSpecAuthUsers indexes Accounts by spec.authorization.authUsers.
+	IndexSpecAuthUsers = "spec.authorization.authUsers"
+	// IndexSpecOfflineJWTSecretRef indexes Operators by spec.offlineIdentity.jwtSecretRef.
+	IndexSpecOfflineJWTSecretRef = "spec.offlineIdentity.jwtSecretRef"
+)
+
+type fieldIndex struct {
+	obj     client.Object
+	field   string
+	extract client.IndexerFunc
+}
+
+var fieldIndexes = []fieldIndex{
+	{obj: &v1alpha1.Account{}, field: IndexStatusOperatorRef, extract: indexAccountOperatorRef},
+	{obj: &v1alpha1.Account{}, field: IndexSpecIssuerRef, extract: indexAccountIssuerRef},
+	{obj: &v1alpha1.User{}, field: IndexStatusAccountRef, extract: indexUserAccountRef},
+	{obj: &v1alpha1.User{}, field: IndexSpecIssuerRef, extract: indexUserIssuerRef},
+	{obj: &v1alpha1.SigningKey{}, field: IndexStatusOwnerRef, extract: indexSigningKeyOwnerRef},
+	{obj: &v1alpha1.User{}, field: IndexStatusPublicKey, extract: indexUserPublicKey},
+	{obj: &v1alpha1.Account{}, field: IndexSpecAuthUsers, extract: indexAccountAuthUsers},
+	{obj: &v1alpha1.Operator{}, field: IndexSpecOfflineJWTSecretRef, extract: indexOperatorOfflineJWTSecretRef},
+}
+
+// SetupFieldIndexes registers the field indexes used by the reconcilers' watches. It must be called once before the
+// manager is started.
+func SetupFieldIndexes(ctx context.Context, indexer client.FieldIndexer) error {
+	for _, idx := range fieldIndexes {
+		if err := indexer.IndexField(ctx, idx.obj, idx.field, idx.extract); err != nil {
+			return fmt.Errorf("failed to index %T by %s: %w", idx.obj, idx.field, err)
+		}
+	}
+
+	return nil
+}
+
+// refIndexKey returns the index key of the object namespace/name.
+func refIndexKey(namespace, name string) string {
+	return types.NamespacedName{Namespace: namespace, Name: name}.String()
+}
+
+// refIndexValues returns the index values for a reference from obj, namespace defaults to that of obj.
+func refIndexValues(obj client.Object, namespace, name string, uid types.UID) []string {
+	if namespace == "" {
+		namespace = obj.GetNamespace()
+	}
+
+	values := []string{refIndexKey(namespace, name)}
+	if uid != "" {
+		values = append(values, string(uid))
+	}
+
+	return values
+}
+
+func indexAccountOperatorRef(obj client.Object) []string {
+	acc, ok := obj.(*v1alpha1.Account)
+	if !ok || acc.Status.OperatorRef == nil {
+		return nil
+	}
+
+	return refIndexValues(acc, acc.Status.OperatorRef.Namespace, acc.Status.OperatorRef.Name, "")
+}
+
+func indexAccountIssuerRef(obj client.Object) []string {
+	acc, ok := obj.(*v1alpha1.Account)
+	if !ok {
+		return nil
+	}
+
+	ref := acc.Spec.Issuer.Ref
+
+	return refIndexValues(acc, ref.Namespace, ref.Name, ref.UID)
+}
+
+func indexUserAccountRef(obj client.Object) []string {
+	usr, ok := obj.(*v1alpha1.User)
+	if !ok || usr.Status.AccountRef == nil {
+		return nil
+	}
+
+	return refIndexValues(usr, usr.Status.AccountRef.Namespace, usr.Status.AccountRef.Name, "")
+}
+
+func indexUserIssuerRef(obj client.Object) []string {
+	usr, ok := obj.(*v1alpha1.User)
+	if !ok {
+		return nil
+	}
+
+	ref := usr.Spec.Issuer.Ref
+
+	return refIndexValues(usr, ref.Namespace, ref.Name, ref.UID)
+}
+
+func indexUserPublicKey(obj client.Object) []string {
+	usr, ok := obj.(*v1alpha1.User)
+	if !ok || usr.Status.KeyPair == nil || usr.Status.KeyPair.PublicKey == "" {
+		return nil
+	}
+
+	return []string{usr.Status.KeyPair.PublicKey}
+}
+
+func indexAccountAuthUsers(obj client.Object) []string {
+	acc, ok := obj.(*v1alpha1.Account)
+	if !ok || acc.Spec.Authorization == nil {
+		return nil
+	}
+
+	var values []string
+
+	for _, ref := range acc.Spec.Authorization.AuthUsers {
+		values = append(values, refIndexValues(acc, ref.Namespace, ref.Name, "")...)
+	}
+
+	return values
+}
+
+func indexOperatorOfflineJWTSecretRef(obj client.Object) []string {
+	operator, ok := obj.(*v1alpha1.Operator)
+	if !ok || operator.Spec.OfflineIdentity == nil {
+		return nil
+	}
+
+	// the Secret is always in the namespace of the Operator
+	return refIndexValues(operator, "", operator.Spec.OfflineIdentity.JWTSecretRef.Name, "")
+}
+
+func indexSigningKeyOwnerRef(obj client.Object) []string {
+	sk, ok := obj.(*v1alpha1.SigningKey)
+	if !ok || sk.Status.OwnerRef == nil {
+		return nil
+	}
+
+	ref := sk.Status.OwnerRef
+
+	return refIndexValues(sk, ref.Namespace, ref.Name, ref.UID)
+}
+
+// indexQuery selects the objects whose field index matches a referenced object, filtered by keep when set.
+type indexQuery struct {
+	field string
+	keep  func(client.Object) bool
+}
+
+// issuedBy returns true for Accounts and Users whose spec.issuer.ref is of the given kind. The issuer index is keyed by
+// namespace/name, so an Operator and a SigningKey with the same name would otherwise both match.
+func issuedBy(kind string) func(client.Object) bool {
+	gvk := v1alpha1.GroupVersion.WithKind(kind)
+
+	return func(obj client.Object) bool {
+		switch o := obj.(type) {
+		case *v1alpha1.Account:
+			return o.Spec.Issuer.Ref.GetGroupVersionKind() == gvk
+		case *v1alpha1.User:
+			return o.Spec.Issuer.Ref.GetGroupVersionKind() == gvk
+		default:
+			return false
+		}
+	}
+}
+
+// ownedBy returns true for SigningKeys whose status.ownerRef is of the given kind.
+func ownedBy(kind string) func(client.Object) bool {
+	gvk := v1alpha1.GroupVersion.WithKind(kind)
+
+	return func(obj client.Object) bool {
+		sk, ok := obj.(*v1alpha1.SigningKey)
+
+		return ok && sk.Status.OwnerRef != nil && sk.Status.OwnerRef.GetGroupVersionKind() == gvk
+	}
+}
+
+// listReferencing runs each query against list, matching referenced by namespace/name and by UID, and returns a
+// de-duplicated reconcile.Request for every object found.
+func listReferencing(ctx context.Context, c client.Reader, list client.ObjectList, referenced client.Object, queries ...indexQuery) ([]reconcile.Request, error) {
+	keys := []string{refIndexKey(referenced.GetNamespace(), referenced.GetName())}
+	if uid := referenced.GetUID(); uid != "" {
+		keys = append(keys, string(uid))
+	}
+
+	seen := make(map[types.NamespacedName]bool)
+
+	var requests []reconcile.Request
+
+	for _, q := range queries {
+		for _, key := range keys {
+			if err := c.List(ctx, list, client.MatchingFields{q.field: key}); err != nil {
+				return nil, fmt.Errorf("failed to list by %s: %w", q.field, err)
+			}
+
+			objs, err := meta.ExtractList(list)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, o := range objs {
+				obj, ok := o.(client.Object)
+				if !ok || (q.keep != nil && !q.keep(obj)) {
+					continue
+				}
+
+				nn := client.ObjectKeyFromObject(obj)
+				if seen[nn] {
+					continue
+				}
+
+				seen[nn] = true
+
+				requests = append(requests, reconcile.Request{NamespacedName: nn})
+			}
+		}
+	}
+
+	return requests, nil
+}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/go-logr/logr"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+func newIndexedClient(t *testing.T, objs ...client.Object) client.Client {
+	t.Helper()
+
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	b := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).
+		WithStatusSubresource(&v1alpha1.Operator{}, &v1alpha1.Account{}, &v1alpha1.User{}, &v1alpha1.SigningKey{})
+	for _, idx := range fieldIndexes {
+		b = b.WithIndex(idx.obj, idx.field, idx.extract)
+	}
+
+	return b.Build()
+}
+
+// enqueued returns the sorted namespace/name of every request h enqueues for a create event of obj.
+func enqueued(t *testing.T, h handler.EventHandler, obj client.Object) []string {
+	t.Helper()
+
+	q := workqueue.NewRateLimitingQueue(workqueue.DefaultControllerRateLimiter())
+	defer q.ShutDown()
+
+	h.Create(context.Background(), event.CreateEvent{Object: obj}, q)
+
+	got := make([]string, 0, q.Len())
+	for q.Len() > 0 {
+		item, _ := q.Get()
+		got = append(got, item.(reconcile.Request).String())
+		q.Done(item)
+	}
+
+	sort.Strings(got)
+
+	return got
+}
+
+func issuerRef(kind, namespace, name string) v1alpha1.IssuerReference {
+	return v1alpha1.IssuerReference{Ref: v1alpha1.TypedObjectReference{
+		APIVersion: v1alpha1.GroupVersion.String(),
+		Kind:       kind,
+		Namespace:  namespace,
+		Name:       name,
+	}}
+}
+
+func Test_accountOperatorWatcher(t *testing.T) {
+	operator := &v1alpha1.Operator{ObjectMeta: metav1.ObjectMeta{Name: "nats", Namespace: "a", UID: "op-uid"}}
+
+	objs := []client.Object{
+		// resolved, labels are not required
+		&v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "resolved", Namespace: "a"},
+			Status:     v1alpha1.AccountStatus{OperatorRef: &v1alpha1.InferredObjectReference{Namespace: "a", Name: "nats"}},
+		},
+		// resolved in another namespace
+		&v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "remote", Namespace: "b"},
+			Status:     v1alpha1.AccountStatus{OperatorRef: &v1alpha1.InferredObjectReference{Namespace: "a", Name: "nats"}},
+		},
+		// newly created, not resolved yet
+		&v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "a"},
+			Spec:       v1alpha1.AccountSpec{Issuer: issuerRef("Operator", "", "nats")},
+		},
+		// an Operator with the same name in another namespace
+		&v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "b"},
+			Spec:       v1alpha1.AccountSpec{Issuer: issuerRef("Operator", "", "nats")},
+			Status:     v1alpha1.AccountStatus{OperatorRef: &v1alpha1.InferredObjectReference{Namespace: "b", Name: "nats"}},
+		},
+		// a SigningKey with the same name as the Operator
+		&v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "sk-issued", Namespace: "a"},
+			Spec:       v1alpha1.AccountSpec{Issuer: issuerRef("SigningKey", "", "nats")},
+		},
+	}
+
+	got := enqueued(t, accountOperatorWatcher(logr.Discard(), newIndexedClient(t, objs...)), operator)
+	want := []string{"a/new", "a/resolved", "b/remote"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("enqueued = %v, want %v", got, want)
+	}
+}
+
+func Test_accountSigningKeyWatcher(t *testing.T) {
+	signingKey := &v1alpha1.SigningKey{
+		ObjectMeta: metav1.ObjectMeta{Name: "sk", Namespace: "a"},
+		Status: v1alpha1.SigningKeyStatus{OwnerRef: &v1alpha1.TypedObjectReference{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       "Account",
+			Namespace:  "a",
+			Name:       "owner",
+		}},
+	}
+
+	objs := []client.Object{
+		&v1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "owner", Namespace: "a"}},
+		&v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "issued", Namespace: "a"},
+			Spec:       v1alpha1.AccountSpec{Issuer: issuerRef("SigningKey", "", "sk")},
+		},
+		&v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "b"},
+			Spec:       v1alpha1.AccountSpec{Issuer: issuerRef("SigningKey", "", "sk")},
+		},
+	}
+
+	got := enqueued(t, accountSigningKeyWatcher(logr.Discard(), newIndexedClient(t, objs...)), signingKey)
+	want := []string{"a/issued", "a/owner"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("enqueued = %v, want %v", got, want)
+	}
+}
+
+func Test_signingKeyAccountWatcher(t *testing.T) {
+	account := &v1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "acc", Namespace: "a", UID: "acc-uid"}}
+
+	ownerRef := func(kind, namespace, name, uid string) *v1alpha1.TypedObjectReference {
+		return &v1alpha1.TypedObjectReference{
+			APIVersion: v1alpha1.GroupVersion.String(),
+			Kind:       kind,
+			Namespace:  namespace,
+			Name:       name,
+			UID:        types.UID(uid),
+		}
+	}
+
+	objs := []client.Object{
+		&v1alpha1.SigningKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "by-name", Namespace: "a"},
+			Status:     v1alpha1.SigningKeyStatus{OwnerRef: ownerRef("Account", "a", "acc", "")},
+		},
+		&v1alpha1.SigningKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "by-uid", Namespace: "a"},
+			Status:     v1alpha1.SigningKeyStatus{OwnerRef: ownerRef("Account", "a", "acc", "acc-uid")},
+		},
+		&v1alpha1.SigningKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "b"},
+			Status:     v1alpha1.SigningKeyStatus{OwnerRef: ownerRef("Account", "b", "acc", "")},
+		},
+		&v1alpha1.SigningKey{
+			ObjectMeta: metav1.ObjectMeta{Name: "operator-owned", Namespace: "a"},
+			Status:     v1alpha1.SigningKeyStatus{OwnerRef: ownerRef("Operator", "a", "acc", "")},
+		},
+	}
+
+	got := enqueued(t, signingKeyAccountWatcher(logr.Discard(), newIndexedClient(t, objs...)), account)
+	want := []string{"a/by-name", "a/by-uid"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("enqueued = %v, want %v", got, want)
+	}
+}
+
+func Test_userAccountWatcher(t *testing.T) {
+	account := &v1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "acc", Namespace: "a"}}
+
+	objs := []client.Object{
+		&v1alpha1.User{
+			ObjectMeta: metav1.ObjectMeta{Name: "resolved", Namespace: "a"},
+			Status:     v1alpha1.UserStatus{AccountRef: &v1alpha1.InferredObjectReference{Namespace: "a", Name: "acc"}},
+		},
+		&v1alpha1.User{
+			ObjectMeta: metav1.ObjectMeta{Name: "new", Namespace: "a"},
+			Spec:       v1alpha1.UserSpec{Issuer: issuerRef("Account", "", "acc")},
+		},
+		&v1alpha1.User{
+			ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "b"},
+			Spec:       v1alpha1.UserSpec{Issuer: issuerRef("Account", "", "acc")},
+			Status:     v1alpha1.UserStatus{AccountRef: &v1alpha1.InferredObjectReference{Namespace: "b", Name: "acc"}},
+		},
+	}
+
+	got := enqueued(t, userAccountWatcher(logr.Discard(), newIndexedClient(t, objs...)), account)
+	want := []string{"a/new", "a/resolved"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("enqueued = %v, want %v", got, want)
+	}
+}
+
+func Test_operatorOfflineJWTSecretWatcher(t *testing.T) {
+	secret := &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "operator-jwt", Namespace: "a"}}
+
+	offline := func(name string) *v1alpha1.OperatorOfflineIdentity {
+		return &v1alpha1.OperatorOfflineIdentity{JWTSecretRef: v1.SecretKeySelector{
+			LocalObjectReference: v1.LocalObjectReference{Name: name},
+			Key:                  "operator.jwt",
+		}}
+	}
+
+	objs := []client.Object{
+		&v1alpha1.Operator{
+			ObjectMeta: metav1.ObjectMeta{Name: "offline", Namespace: "a"},
+			Spec:       v1alpha1.OperatorSpec{OfflineIdentity: offline("operator-jwt")},
+		},
+		&v1alpha1.Operator{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-secret", Namespace: "a"},
+			Spec:       v1alpha1.OperatorSpec{OfflineIdentity: offline("other-jwt")},
+		},
+		// the Secret is always looked up in the namespace of the Operator
+		&v1alpha1.Operator{
+			ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "b"},
+			Spec:       v1alpha1.OperatorSpec{OfflineIdentity: offline("operator-jwt")},
+		},
+		&v1alpha1.Operator{ObjectMeta: metav1.ObjectMeta{Name: "online", Namespace: "a"}},
+	}
+
+	got := enqueued(t, operatorOfflineJWTSecretWatcher(logr.Discard(), newIndexedClient(t, objs...)), secret)
+	want := []string{"a/offline"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("enqueued = %v, want %v", got, want)
+	}
+}
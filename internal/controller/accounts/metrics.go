@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+const metricsNamespace = "nats_account_operator"
+
+var (
+	jwtSignedTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Name:      "jwt_signed_total",
+		Help:      "Number of JWTs signed by the controllers, by the kind of resource the JWT is for.",
+	}, []string{"kind"})
+
+	accountServerRequestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "account_server",
+		Name:      "requests_total",
+		Help:      "Number of account JWT pushes and deletes attempted against the account server of an Operator.",
+	}, []string{"operator", "server", "operation"})
+
+	accountServerRequestFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "account_server",
+		Name:      "request_failures_total",
+		Help:      "Number of account JWT pushes and deletes which failed, including failures to connect.",
+	}, []string{"operator", "server", "operation"})
+
+	accountServerRequestSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "account_server",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of account JWT pushes and deletes, including connecting to the account server.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"operator", "server", "operation"})
+
+	accountServerThrottledTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "account_server",
+		Name:      "throttled_total",
+		Help:      "Number of account server requests delayed by the per-Operator rate limiter.",
+	}, []string{"operator", "operation"})
+
+	accountServerThrottleSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "account_server",
+		Name:      "throttle_seconds",
+		Help:      "Time account server requests spent waiting for the per-Operator rate limiter.",
+		Buckets:   prometheus.ExponentialBuckets(0.01, 2, 12),
+	}, []string{"operator", "operation"})
+
+	accountServerWaiting = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: metricsNamespace,
+		Subsystem: "account_server",
+		Name:      "waiting_requests",
+		Help:      "Number of account server requests currently waiting for the per-Operator rate limiter.",
+	}, []string{"operator"})
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		jwtSignedTotal,
+		accountServerRequestsTotal,
+		accountServerRequestFailuresTotal,
+		accountServerRequestSeconds,
+		accountServerThrottledTotal,
+		accountServerThrottleSeconds,
+		accountServerWaiting,
+	)
+}
+
+// observeJWTSigned counts a JWT signed for a resource of kind if reconcileJWTSecret created or updated its JWT Secret,
+// which it signals by requeueing without an error. Reconciles which find the Secret up to date are not counted.
+func observeJWTSigned(kind string, result reconcile.Result, err error) {
+	if err == nil && result.Requeue {
+		jwtSignedTotal.WithLabelValues(kind).Inc()
+	}
+}
+
+// observeAccountServerRequest records an attempted push or delete to the account server of operator which started at
+// start and failed if err is not nil.
+func observeAccountServerRequest(operator *v1alpha1.Operator, operation string, start time.Time, err error) {
+	labels := []string{client.ObjectKeyFromObject(operator).String(), serverLabel(operator.Spec.AccountServerURL), operation}
+
+	accountServerRequestsTotal.WithLabelValues(labels...).Inc()
+	accountServerRequestSeconds.WithLabelValues(labels...).Observe(time.Since(start).Seconds())
+
+	if err != nil {
+		accountServerRequestFailuresTotal.WithLabelValues(labels...).Inc()
+	}
+}
+
+// serverLabel returns the scheme and host of an account server URL, so that credentials in its userinfo, or tokens in
+// its query, are not exposed as metric labels. The URL is returned as is if it has no host, since it is then not a
+// URL which the account server could be reached at.
+func serverLabel(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+
+	return (&url.URL{Scheme: u.Scheme, Host: u.Host}).String()
+}
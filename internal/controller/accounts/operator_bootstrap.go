@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// bootstrapResource is implemented by every kind created by OperatorSpec.Bootstrap.
+type bootstrapResource interface {
+	client.Object
+	v1alpha1.StatusAccessor
+	v1alpha1.ConditionSetAccessor
+}
+
+// bootstrapNames returns the names of the operator SigningKey, system Account, system Account SigningKey and system
+// User created by OperatorSpec.Bootstrap.
+func bootstrapNames(operator *v1alpha1.Operator) (operatorSK, account, accountSK, user string) {
+	account = operator.Spec.SystemAccountRef.Name
+
+	user = operator.Spec.Bootstrap.SystemUserName
+	if user == "" {
+		user = operator.Name + "-system"
+	}
+
+	return operator.Name + "-sk", account, account + "-sk", user
+}
+
+// reconcileBootstrap creates the resources configured by OperatorSpec.Bootstrap and reports their readiness. It does not
+// wait for them to become Ready, each is watched so the Operator is reconciled again when their status changes.
+func (r *OperatorReconciler) reconcileBootstrap(ctx context.Context, operator *v1alpha1.Operator) error {
+	if operator.Spec.Bootstrap == nil {
+		operator.Status.ClearBootstrap()
+
+		return nil
+	}
+
+	operatorSKName, accountName, accountSKName, userName := bootstrapNames(operator)
+	apiVersion := v1alpha1.GroupVersion.String()
+
+	operatorSK := &v1alpha1.SigningKey{ObjectMeta: metav1.ObjectMeta{Name: operatorSKName, Namespace: operator.Namespace}}
+	account := &v1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: accountName, Namespace: operator.Namespace}}
+	accountSK := &v1alpha1.SigningKey{ObjectMeta: metav1.ObjectMeta{Name: accountSKName, Namespace: operator.Namespace}}
+	user := &v1alpha1.User{ObjectMeta: metav1.ObjectMeta{Name: userName, Namespace: operator.Namespace}}
+
+	issuer := func(name string) v1alpha1.IssuerReference {
+		return v1alpha1.IssuerReference{Ref: v1alpha1.TypedObjectReference{
+			APIVersion: apiVersion,
+			Kind:       "SigningKey",
+			Name:       name,
+			Namespace:  operator.Namespace,
+		}}
+	}
+
+	steps := []struct {
+		kind   string
+		obj    bootstrapResource
+		mutate func()
+	}{
+		{
+			kind: "SigningKey",
+			obj:  operatorSK,
+			mutate: func() {
+				operatorSK.Spec.SeedSecretName = operatorSKName + "-seed"
+				operatorSK.Spec.OwnerRef = v1alpha1.SigningKeyOwnerReference{APIVersion: apiVersion, Kind: "Operator", Name: operator.Name}
+			},
+		},
+		{
+			kind: "Account",
+			obj:  account,
+			mutate: func() {
+				account.Spec.Issuer = issuer(operatorSKName)
+				account.Spec.JWTSecretName = accountName + "-jwt"
+				account.Spec.SeedSecretName = accountName + "-seed"
+			},
+		},
+		{
+			kind: "SigningKey",
+			obj:  accountSK,
+			mutate: func() {
+				accountSK.Spec.SeedSecretName = accountSKName + "-seed"
+				accountSK.Spec.OwnerRef = v1alpha1.SigningKeyOwnerReference{APIVersion: apiVersion, Kind: "Account", Name: accountName}
+			},
+		},
+		{
+			kind: "User",
+			obj:  user,
+			mutate: func() {
+				user.Spec.Issuer = issuer(accountSKName)
+				user.Spec.JWTSecretName = userName + "-jwt"
+				user.Spec.SeedSecretName = userName + "-seed"
+				user.Spec.CredentialsSecretName = userName + "-creds"
+			},
+		},
+	}
+
+	resources := make([]v1alpha1.BootstrapResourceStatus, 0, len(steps))
+
+	var waiting []string
+
+	for _, step := range steps {
+		if err := r.reconcileBootstrapResource(ctx, operator, step.kind, step.obj, step.mutate); err != nil {
+			return err
+		}
+
+		status := v1alpha1.BootstrapResourceStatus{Kind: step.kind, Name: step.obj.GetName()}
+
+		if ready := step.obj.GetConditionSet().Manage(step.obj.GetStatus()).GetTopLevelCondition(); ready.IsTrue() {
+			status.Ready = true
+		} else {
+			if ready != nil {
+				status.Message = ready.Message
+			}
+
+			waiting = append(waiting, step.kind+" "+step.obj.GetName())
+		}
+
+		resources = append(resources, status)
+	}
+
+	if len(waiting) > 0 {
+		operator.Status.MarkBootstrapNotReady(resources, v1alpha1.ReasonNotReady, "waiting for %s", strings.Join(waiting, ", "))
+
+		return nil
+	}
+
+	operator.Status.MarkBootstrapReady(resources)
+
+	return nil
+}
+
+// reconcileBootstrapResource creates obj if it does not exist, or updates it if it is controlled by the Operator and
+// has drifted from mutate. Existing resources which are not controlled by the Operator are left unchanged. In all
+// cases obj is populated with the current state of the resource.
+func (r *OperatorReconciler) reconcileBootstrapResource(ctx context.Context, operator *v1alpha1.Operator, kind string, obj bootstrapResource, mutate func()) error {
+	applyLabels := func() {
+		if len(operator.Spec.Bootstrap.Labels) == 0 {
+			return
+		}
+
+		labels := obj.GetLabels()
+		if labels == nil {
+			labels = make(map[string]string, len(operator.Spec.Bootstrap.Labels))
+		}
+
+		for k, v := range operator.Spec.Bootstrap.Labels {
+			labels[k] = v
+		}
+
+		obj.SetLabels(labels)
+	}
+
+	err := r.Get(ctx, client.ObjectKeyFromObject(obj), obj)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return TemporaryError(ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to get %s %q: %w", kind, obj.GetName(), err))
+		}
+
+		mutate()
+		applyLabels()
+
+		if err := ctrl.SetControllerReference(operator, obj, r.Scheme); err != nil {
+			return TerminalError(ConditionFailed(v1alpha1.ReasonUnknownError, "failed to set controller reference on %s %q: %w", kind, obj.GetName(), err))
+		}
+
+		if err := r.Create(ctx, obj); err != nil {
+			return TemporaryError(ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to create %s %q: %w", kind, obj.GetName(), err))
+		}
+
+		r.EventRecorder.Eventf(operator, v1.EventTypeNormal, "BootstrapCreated", "created %s: %s/%s", kind, obj.GetNamespace(), obj.GetName())
+
+		return nil
+	}
+
+	if !metav1.IsControlledBy(obj, operator) {
+		return nil
+	}
+
+	original := obj.DeepCopyObject()
+
+	mutate()
+	applyLabels()
+
+	if equality.Semantic.DeepEqual(original, obj) {
+		return nil
+	}
+
+	if err := r.Update(ctx, obj); err != nil {
+		return TemporaryError(ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to update %s %q: %w", kind, obj.GetName(), err))
+	}
+
+	r.EventRecorder.Eventf(operator, v1.EventTypeNormal, "BootstrapUpdated", "updated %s: %s/%s", kind, obj.GetNamespace(), obj.GetName())
+
+	return nil
+}
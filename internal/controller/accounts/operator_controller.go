@@ -28,17 +28,19 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
 	"github.com/nats-io/jwt/v2"
 	"github.com/nats-io/nkeys"
 	"go.uber.org/multierr"
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -64,6 +66,9 @@ type OperatorReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.13.0/pkg/reconcile
 func (r *OperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := startSpan(ctx, "OperatorReconciler.Reconcile", keyAttributes(req.Namespace, req.Name)...)
+	defer func() { endSpan(span, err) }()
+
 	logger := log.FromContext(ctx)
 
 	logger.V(1).Info("reconciling operator", "name", req.Name)
@@ -73,8 +78,14 @@ func (r *OperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	if isPaused(r.EventRecorder, operator) {
+		return ctrl.Result{}, nil
+	}
+
 	originalStatus := operator.Status.DeepCopy()
 
+	operator.Status.ObservedGeneration = operator.Generation
+
 	operator.Status.InitializeConditions()
 
 	defer func() {
@@ -91,18 +102,45 @@ func (r *OperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		}
 	}()
 
-	kp, seed, result, err := r.reconcileSeedSecret(ctx, operator, nkeys.CreateOperator, operator.Spec.SeedSecretName,
-		resources.Immutable(), resources.WithDeletionPrevention())
-	if err != nil {
-		MarkCondition(err, operator.Status.MarkSeedSecretFailed, operator.Status.MarkSeedSecretUnknown)
+	var (
+		seed          []byte
+		offlineJWT    string
+		offlineClaims *jwt.OperatorClaims
+	)
 
-		return AsResult(err)
+	if operator.Spec.OfflineIdentity != nil {
+		offlineClaims, offlineJWT, err = r.loadOfflineJWT(ctx, operator)
+		if err != nil {
+			MarkCondition(err, operator.Status.MarkJWTSecretFailed, operator.Status.MarkJWTSecretUnknown)
+
+			return AsResult(err)
+		}
+
+		operator.Status.MarkSeedSecretOffline(offlineClaims.Subject)
+	} else {
+		var kp *v1alpha1.KeyPair
+
+		kp, seed, result, err = r.reconcileKeyPair(ctx, operator, nkeys.CreateOperator, nkeys.PrefixByteOperator,
+			operator.Spec.ExistingSeedSecretRef, operator.Spec.SeedSecretName, resources.Immutable(), resources.WithDeletionPrevention())
+		if err != nil {
+			MarkCondition(err, operator.Status.MarkSeedSecretFailed, operator.Status.MarkSeedSecretUnknown)
+
+			return AsResult(err)
+		}
+
+		operator.Status.MarkSeedSecretReady(*kp)
+
+		if !result.IsZero() {
+			return result, nil
+		}
 	}
 
-	operator.Status.MarkSeedSecretReady(*kp)
+	if err = r.reconcileBootstrap(ctx, operator); err != nil {
+		logger.Error(err, "failed to reconcile bootstrap resources")
+
+		MarkCondition(err, operator.Status.MarkBootstrapFailed, operator.Status.MarkBootstrapUnknown)
 
-	if !result.IsZero() {
-		return result, nil
+		return AsResult(err)
 	}
 
 	if err = r.ensureSystemAccountResolved(ctx, operator); err != nil {
@@ -126,24 +164,46 @@ func (r *OperatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (r
 		return ctrl.Result{}, err
 	}
 
-	result, err = r.reconcileJWTSecret(ctx, operator, seed)
+	var ojwt string
+
+	forcePush := forcePushRequest(operator, operator.Status.LastHandledForcePushAt)
+
+	if offlineClaims != nil {
+		ojwt, result, err = r.reconcileOfflineJWTSecret(ctx, operator, offlineClaims, offlineJWT, forcePush != "")
+	} else {
+		ojwt, result, err = r.reconcileJWTSecret(ctx, operator, seed, forcePush != "")
+	}
+
 	if err != nil {
 		MarkCondition(err, operator.Status.MarkJWTSecretFailed, operator.Status.MarkJWTSecretUnknown)
 
 		return AsResult(err)
 	}
 
-	operator.Status.MarkJWTSecretReady()
+	claims, err := newClaimsSummary(ojwt)
+	if err != nil {
+		MarkCondition(err, operator.Status.MarkJWTSecretFailed, operator.Status.MarkJWTSecretUnknown)
+
+		return AsResult(err)
+	}
+
+	operator.Status.MarkJWTSecretReady(claims)
+
+	if forcePush != "" {
+		operator.Status.LastHandledForcePushAt = forcePush
+
+		r.EventRecorder.Eventf(operator, v1.EventTypeNormal, "ForcePushed", "updated JWT secret for %s=%s", v1alpha1.AnnotationForcePushAt, forcePush)
+	}
 
 	return result, nil
 }
 
-func (r *OperatorReconciler) reconcileJWTSecret(ctx context.Context, operator *v1alpha1.Operator, seed []byte) (reconcile.Result, error) {
+func (r *OperatorReconciler) reconcileJWTSecret(ctx context.Context, operator *v1alpha1.Operator, seed []byte, force bool) (_ string, result reconcile.Result, err error) {
 	logger := log.FromContext(ctx)
 
 	signingKey, err := nkeys.FromSeed(seed)
 	if err != nil {
-		return reconcile.Result{}, TerminalError(ConditionFailed(v1alpha1.ReasonUnknownError, "failed to get signing key from seed: %w", err))
+		return "", reconcile.Result{}, TerminalError(ConditionFailed(v1alpha1.ReasonUnknownError, "failed to get signing key from seed: %w", err))
 	}
 
 	// we want to check that any existing secret decodes to match wantClaims, if it doesn't then we will use nextJWT
@@ -151,30 +211,123 @@ func (r *OperatorReconciler) reconcileJWTSecret(ctx context.Context, operator *v
 	// timestamped with the `iat` claim so will never match.
 	wantClaims, nextJWT, err := nsc.CreateOperatorClaims(operator, signingKey)
 	if err != nil {
-		return reconcile.Result{}, TerminalError(ConditionFailed(v1alpha1.ReasonUnknownError, "failed to create account JWT claims: %w", err))
+		return "", reconcile.Result{}, TerminalError(ConditionFailed(v1alpha1.ReasonUnknownError, "failed to create account JWT claims: %w", err))
+	}
+
+	defer func() { observeJWTSigned("Operator", result, err) }()
+
+	got, err := r.getSecret(ctx, operator.Namespace, operator.Spec.JWTSecretName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			logger.V(1).Info("JWT secret not found, creating new secret")
+
+			return nextJWT, reconcile.Result{Requeue: true}, r.createJWTSecret(ctx, operator, nextJWT)
+		}
+
+		return "", reconcile.Result{}, TemporaryError(ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to get JWT secret: %w", err))
+	}
+
+	return r.ensureJWTSecretUpToDate(ctx, operator, wantClaims, got, nextJWT, force)
+}
+
+// loadOfflineJWT loads and decodes the pre-signed Operator JWT referenced by spec.offlineIdentity.jwtSecretRef.
+func (r *OperatorReconciler) loadOfflineJWT(ctx context.Context, operator *v1alpha1.Operator) (*jwt.OperatorClaims, string, error) {
+	ref := operator.Spec.OfflineIdentity.JWTSecretRef
+
+	secret, err := r.getSecret(ctx, operator.Namespace, ref.Name)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return nil, "", TemporaryError(ConditionFailed(v1alpha1.ReasonNotFound, "offline JWT secret %q not found", ref.Name))
+		}
+
+		return nil, "", TemporaryError(ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to get offline JWT secret: %w", err))
+	}
+
+	// the manager only caches and watches labelled Secrets, see CacheByObject, so re-signing the JWT offline would not
+	// be noticed until the Operator is next reconciled. Labelling the Secret triggers a reconcile, so this need not
+	// be retried.
+	if _, ok := secret.Labels[resources.LabelSecretType]; !ok {
+		return nil, "", TerminalError(ConditionFailed(v1alpha1.ReasonUnlabelledSecret,
+			"offline JWT secret %q must be labelled %s=%s so that changes to it are watched",
+			ref.Name, resources.LabelSecretType, resources.LabelSecretTypeJWT))
+	}
+
+	ojwt, ok := secret.Data[ref.Key]
+	if !ok {
+		return nil, "", TerminalError(ConditionFailed(v1alpha1.ReasonInvalidJWTSecret, "offline JWT secret %q does not contain key %q", ref.Name, ref.Key))
+	}
+
+	claims, err := jwt.DecodeOperatorClaims(string(ojwt))
+	if err != nil {
+		return nil, "", TerminalError(ConditionFailed(v1alpha1.ReasonInvalidJWTSecret, "failed to decode offline operator JWT: %w", err))
+	}
+
+	if claims.Issuer != claims.Subject {
+		return nil, "", TerminalError(ConditionFailed(v1alpha1.ReasonInvalidJWTSecret, "offline operator JWT must be self-signed by the operator identity key"))
 	}
 
-	got, err := r.CoreV1.Secrets(operator.Namespace).Get(ctx, operator.Spec.JWTSecretName, metav1.GetOptions{})
+	return claims, string(ojwt), nil
+}
+
+// reconcileOfflineJWTSecret verifies the pre-signed Operator JWT lists the current signing keys and system account,
+// and copies it into the Operator's JWT secret. The controller cannot re-sign the JWT, so any drift is reported as a
+// condition describing what must be changed offline.
+func (r *OperatorReconciler) reconcileOfflineJWTSecret(ctx context.Context, operator *v1alpha1.Operator, claims *jwt.OperatorClaims, ojwt string, force bool) (string, reconcile.Result, error) {
+	logger := log.FromContext(ctx)
+
+	if err := verifyOfflineOperatorClaims(operator, claims); err != nil {
+		return "", reconcile.Result{}, TerminalError(err)
+	}
+
+	got, err := r.getSecret(ctx, operator.Namespace, operator.Spec.JWTSecretName)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.V(1).Info("JWT secret not found, creating new secret")
 
-			return reconcile.Result{Requeue: true}, r.createJWTSecret(ctx, operator, nextJWT)
+			return ojwt, reconcile.Result{Requeue: true}, r.createJWTSecret(ctx, operator, ojwt)
 		}
 
-		return reconcile.Result{}, TemporaryError(ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to get JWT secret: %w", err))
+		return "", reconcile.Result{}, TemporaryError(ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to get JWT secret: %w", err))
 	}
 
-	_, result, err := r.ensureJWTSecretUpToDate(ctx, operator, wantClaims, got, nextJWT)
+	return r.ensureJWTSecretUpToDate(ctx, operator, claims, got, ojwt, force)
+}
 
-	return result, err
+// verifyOfflineOperatorClaims checks that a pre-signed Operator JWT lists every ready SigningKey owned by the Operator
+// and the resolved system account.
+func verifyOfflineOperatorClaims(operator *v1alpha1.Operator, claims *jwt.OperatorClaims) error {
+	var problems []string
+
+	var missing []string
+
+	for _, sk := range operator.Status.SigningKeys {
+		if !claims.SigningKeys.Contains(sk.KeyPair.PublicKey) {
+			missing = append(missing, sk.KeyPair.PublicKey)
+		}
+	}
+
+	if len(missing) > 0 {
+		problems = append(problems, fmt.Sprintf("add signing keys %s", strings.Join(missing, ", ")))
+	}
+
+	if sysAcc := operator.Status.ResolvedSystemAccount; sysAcc != nil && claims.SystemAccount != sysAcc.PublicKey {
+		problems = append(problems, fmt.Sprintf("set system account to %s (currently %q)", sysAcc.PublicKey, claims.SystemAccount))
+	}
+
+	if len(problems) == 0 {
+		return nil
+	}
+
+	return ConditionFailed(v1alpha1.ReasonOfflineJWTOutdated, "operator JWT must be re-signed offline with the identity key: %s", strings.Join(problems, "; "))
 }
 
 func (r *OperatorReconciler) ensureSigningKeysUpdated(ctx context.Context, operator *v1alpha1.Operator) error {
 	logger := log.FromContext(ctx)
 
-	skList, err := r.AccountsV1Alpha1.SigningKeys(operator.Namespace).List(ctx, metav1.ListOptions{})
-	if err != nil || skList == nil {
+	skList := new(v1alpha1.SigningKeyList)
+
+	err := r.List(ctx, skList, client.InNamespace(operator.Namespace))
+	if err != nil {
 		logger.V(1).Info("failed to list signing keys", "error:", err)
 
 		return err
@@ -257,8 +410,10 @@ func (r *OperatorReconciler) updateOperatorJWTSigningKeys(ctx context.Context, o
 		return err
 	}
 
+	jwtSignedTotal.WithLabelValues("Operator").Inc()
+
 	jwtSecret.Data[v1alpha1.NatsSecretJWTKey] = []byte(ojwt)
-	_, err = r.CoreV1.Secrets(jwtSecret.Namespace).Update(ctx, jwtSecret, metav1.UpdateOptions{})
+	err = r.Update(ctx, jwtSecret)
 	if err != nil {
 		logger.Error(err, "failed to update jwt secret")
 		return err
@@ -274,8 +429,12 @@ func (r *OperatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	logger := mgr.GetLogger().WithName("OperatorReconciler")
 
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.Operator{}).
+		For(&v1alpha1.Operator{}, builder.WithPredicates(specChangedPredicate())).
+		WithOptions(r.controllerOptions()).
 		Owns(&v1.Secret{}).
+		Owns(&v1alpha1.Account{}).
+		Owns(&v1alpha1.SigningKey{}).
+		Owns(&v1alpha1.User{}).
 		Watches(
 			&v1alpha1.Account{},
 			handler.EnqueueRequestsFromMapFunc(func(_ context.Context, obj client.Object) []reconcile.Request {
@@ -335,5 +494,22 @@ func (r *OperatorReconciler) SetupWithManager(mgr ctrl.Manager) error {
 				}}
 			}),
 		).
+		Watches(&v1.Secret{}, operatorOfflineJWTSecretWatcher(logger, mgr.GetClient())).
 		Complete(r)
 }
+
+// operatorOfflineJWTSecretWatcher enqueues the Operators referencing a Secret through spec.offlineIdentity.jwtSecretRef.
+// The pre-signed JWT of an Operator with an offline identity is not owned by the Operator, so this is the only way a
+// re-signed JWT is picked up.
+func operatorOfflineJWTSecretWatcher(logger logr.Logger, c client.Client) handler.EventHandler {
+	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
+		requests, err := listReferencing(ctx, c, &v1alpha1.OperatorList{}, obj, indexQuery{field: IndexSpecOfflineJWTSecretRef})
+		if err != nil {
+			logger.Error(err, "failed to list operators for offline JWT secret", "secret", client.ObjectKeyFromObject(obj))
+
+			return nil
+		}
+
+		return requests
+	})
+}
@@ -0,0 +1,354 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-faster/errors"
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/internal/controller/accounts/resources"
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+)
+
+func Test_verifyOfflineOperatorClaims(t *testing.T) {
+	publicKey := func(create func() (nkeys.KeyPair, error)) string {
+		kp, err := create()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		pub, err := kp.PublicKey()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return pub
+	}
+
+	skA := publicKey(nkeys.CreateOperator)
+	skB := publicKey(nkeys.CreateOperator)
+	sysAcc := publicKey(nkeys.CreateAccount)
+	otherAcc := publicKey(nkeys.CreateAccount)
+
+	tests := []struct {
+		name          string
+		signingKeys   []string
+		systemAccount string
+		wantContains  []string
+	}{
+		{
+			name:          "up to date",
+			signingKeys:   []string{skA, skB},
+			systemAccount: sysAcc,
+		},
+		{
+			name:          "missing signing key",
+			signingKeys:   []string{skA},
+			systemAccount: sysAcc,
+			wantContains:  []string{"add signing keys " + skB},
+		},
+		{
+			name:          "wrong system account",
+			signingKeys:   []string{skA, skB},
+			systemAccount: otherAcc,
+			wantContains:  []string{"set system account to " + sysAcc},
+		},
+		{
+			name:         "missing signing keys and system account",
+			wantContains: []string{"add signing keys " + skA + ", " + skB, "set system account to " + sysAcc},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := &v1alpha1.Operator{
+				Status: v1alpha1.OperatorStatus{
+					SigningKeys: []v1alpha1.SigningKeyEmbeddedStatus{
+						{Name: "a", KeyPair: v1alpha1.KeyPair{PublicKey: skA}},
+						{Name: "b", KeyPair: v1alpha1.KeyPair{PublicKey: skB}},
+					},
+					ResolvedSystemAccount: &v1alpha1.KeyPairReference{PublicKey: sysAcc},
+				},
+			}
+
+			claims := jwt.NewOperatorClaims(publicKey(nkeys.CreateOperator))
+			claims.SigningKeys.Add(tt.signingKeys...)
+			claims.SystemAccount = tt.systemAccount
+
+			err := verifyOfflineOperatorClaims(operator, claims)
+			if len(tt.wantContains) == 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+
+				return
+			}
+
+			cerr, ok := errors.Into[*conditionError](err)
+			if !ok {
+				t.Fatalf("expected condition error, got %v", err)
+			}
+
+			if cerr.reason != v1alpha1.ReasonOfflineJWTOutdated || !cerr.failure {
+				t.Errorf("condition reason = %q (failure: %v), want %q", cerr.reason, cerr.failure, v1alpha1.ReasonOfflineJWTOutdated)
+			}
+
+			for _, want := range tt.wantContains {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error %q does not contain %q", err.Error(), want)
+				}
+			}
+		})
+	}
+}
+
+func Test_OperatorReconciler_loadOfflineJWT(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	operatorKP, err := nkeys.CreateOperator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	operatorPub, err := operatorKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ojwt, err := jwt.NewOperatorClaims(operatorPub).Encode(operatorKP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		wantReason string
+	}{
+		{
+			name:   "labelled",
+			labels: map[string]string{resources.LabelSecretType: resources.LabelSecretTypeJWT},
+		},
+		{
+			name:       "unlabelled",
+			wantReason: v1alpha1.ReasonUnlabelledSecret,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "offline-jwt", Namespace: "nats", Labels: tt.labels},
+				Data:       map[string][]byte{v1alpha1.NatsSecretJWTKey: []byte(ojwt)},
+			}
+
+			operator := &v1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: "nats"},
+				Spec: v1alpha1.OperatorSpec{OfflineIdentity: &v1alpha1.OperatorOfflineIdentity{
+					JWTSecretRef: corev1.SecretKeySelector{
+						LocalObjectReference: corev1.LocalObjectReference{Name: "offline-jwt"},
+						Key:                  v1alpha1.NatsSecretJWTKey,
+					},
+				}},
+			}
+
+			r := &OperatorReconciler{
+				BaseReconciler: &BaseReconciler{
+					Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build(),
+					Scheme: scheme,
+				},
+			}
+
+			claims, got, err := r.loadOfflineJWT(context.Background(), operator)
+
+			if tt.wantReason != "" {
+				if cerr, ok := errors.Into[*conditionError](err); !ok || cerr.reason != tt.wantReason {
+					t.Fatalf("loadOfflineJWT() error = %v, want reason %s", err, tt.wantReason)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != ojwt || claims.Subject != operatorPub {
+				t.Errorf("loadOfflineJWT() = %s, %q, want the offline JWT", claims.Subject, got)
+			}
+		})
+	}
+}
+
+func Test_OperatorReconciler_reconcileBootstrap(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	newOperator := func() *v1alpha1.Operator {
+		return &v1alpha1.Operator{
+			ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: "nats", UID: "op-uid"},
+			Spec: v1alpha1.OperatorSpec{
+				SystemAccountRef: corev1.LocalObjectReference{Name: "sys"},
+				Bootstrap: &v1alpha1.OperatorBootstrap{
+					Labels: map[string]string{"nats.io/operator": "op"},
+				},
+			},
+		}
+	}
+
+	t.Run("creates resources and waits for them to become ready", func(t *testing.T) {
+		operator := newOperator()
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(operator).Build()
+
+		r := &OperatorReconciler{
+			BaseReconciler: &BaseReconciler{Client: c, Scheme: scheme, EventRecorder: record.NewFakeRecorder(10)},
+		}
+
+		if err := r.reconcileBootstrap(context.Background(), operator); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		user := &v1alpha1.User{}
+		if err := c.Get(context.Background(), client.ObjectKey{Namespace: "nats", Name: "op-system"}, user); err != nil {
+			t.Fatalf("failed to get system user: %v", err)
+		}
+
+		if !metav1.IsControlledBy(user, operator) {
+			t.Errorf("expected system user to be controlled by the operator")
+		}
+
+		if user.Spec.Issuer.Ref.Name != "sys-sk" || user.Labels["nats.io/operator"] != "op" {
+			t.Errorf("unexpected system user: issuer %q, labels %v", user.Spec.Issuer.Ref.Name, user.Labels)
+		}
+
+		account := &v1alpha1.Account{}
+		if err := c.Get(context.Background(), client.ObjectKey{Namespace: "nats", Name: "sys"}, account); err != nil {
+			t.Fatalf("failed to get system account: %v", err)
+		}
+
+		if account.Spec.Issuer.Ref.Name != "op-sk" {
+			t.Errorf("expected system account to be issued by op-sk, got %q", account.Spec.Issuer.Ref.Name)
+		}
+
+		if len(operator.Status.Bootstrap) != 4 {
+			t.Fatalf("expected 4 bootstrap resources, got %d", len(operator.Status.Bootstrap))
+		}
+
+		cond := operator.Status.GetCondition(v1alpha1.OperatorConditionBootstrapReady)
+		if cond == nil || cond.Status != corev1.ConditionUnknown || cond.Reason != v1alpha1.ReasonNotReady {
+			t.Errorf("expected BootstrapReady to be Unknown/NotReady, got %+v", cond)
+		}
+
+		// a second pass must not modify the resources which have already been created
+		if err := r.reconcileBootstrap(context.Background(), operator); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		again := &v1alpha1.User{}
+		if err := c.Get(context.Background(), client.ObjectKeyFromObject(user), again); err != nil {
+			t.Fatal(err)
+		}
+
+		if again.ResourceVersion != user.ResourceVersion {
+			t.Errorf("expected system user to be unchanged, resourceVersion %s -> %s", user.ResourceVersion, again.ResourceVersion)
+		}
+	})
+
+	t.Run("reports ready and leaves resources it does not control", func(t *testing.T) {
+		operator := newOperator()
+		ready := v1alpha1.Status{Conditions: apis.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}}}
+
+		existing := &v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "sys", Namespace: "nats"},
+			Spec:       v1alpha1.AccountSpec{JWTSecretName: "custom-jwt"},
+			Status:     v1alpha1.AccountStatus{Status: ready},
+		}
+
+		c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(operator, existing).
+			WithStatusSubresource(&v1alpha1.SigningKey{}, &v1alpha1.User{}).Build()
+
+		r := &OperatorReconciler{
+			BaseReconciler: &BaseReconciler{Client: c, Scheme: scheme, EventRecorder: record.NewFakeRecorder(10)},
+		}
+
+		if err := r.reconcileBootstrap(context.Background(), operator); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		account := &v1alpha1.Account{}
+		if err := c.Get(context.Background(), client.ObjectKeyFromObject(existing), account); err != nil {
+			t.Fatal(err)
+		}
+
+		if account.Spec.JWTSecretName != "custom-jwt" || len(account.OwnerReferences) != 0 {
+			t.Errorf("expected existing account to be left unchanged, got %+v", account)
+		}
+
+		for _, name := range []string{"op-sk", "sys-sk"} {
+			sk := &v1alpha1.SigningKey{}
+			if err := c.Get(context.Background(), client.ObjectKey{Namespace: "nats", Name: name}, sk); err != nil {
+				t.Fatal(err)
+			}
+
+			sk.Status.Status = ready
+			if err := c.Status().Update(context.Background(), sk); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		user := &v1alpha1.User{}
+		if err := c.Get(context.Background(), client.ObjectKey{Namespace: "nats", Name: "op-system"}, user); err != nil {
+			t.Fatal(err)
+		}
+
+		user.Status.Status = ready
+		if err := c.Status().Update(context.Background(), user); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := r.reconcileBootstrap(context.Background(), operator); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if cond := operator.Status.GetCondition(v1alpha1.OperatorConditionBootstrapReady); cond == nil || !cond.IsTrue() {
+			t.Errorf("expected BootstrapReady to be True, got %+v", cond)
+		}
+	})
+
+	t.Run("clears status when bootstrap is removed", func(t *testing.T) {
+		operator := newOperator()
+		operator.Spec.Bootstrap = nil
+		operator.Status.MarkBootstrapReady([]v1alpha1.BootstrapResourceStatus{{Kind: "User", Name: "op-system", Ready: true}})
+
+		r := &OperatorReconciler{
+			BaseReconciler: &BaseReconciler{Client: fake.NewClientBuilder().WithScheme(scheme).Build(), Scheme: scheme},
+		}
+
+		if err := r.reconcileBootstrap(context.Background(), operator); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if operator.Status.Bootstrap != nil || operator.Status.GetCondition(v1alpha1.OperatorConditionBootstrapReady) != nil {
+			t.Errorf("expected bootstrap status to be cleared, got %+v", operator.Status)
+		}
+	})
+}
@@ -0,0 +1,105 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// OperatorRateLimiter limits the rate of requests made to the account server of each Operator, so raising controller
+// concurrency does not flood the NATS server with $SYS.REQ.CLAIMS requests. A nil *OperatorRateLimiter does not limit.
+type OperatorRateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu       sync.Mutex
+	limiters map[client.ObjectKey]*rate.Limiter
+}
+
+// NewOperatorRateLimiter returns an OperatorRateLimiter allowing limit requests per second with bursts of up to burst
+// requests to the account server of each Operator. It returns nil, which does not limit, if limit is not positive.
+func NewOperatorRateLimiter(limit float64, burst int) *OperatorRateLimiter {
+	if limit <= 0 {
+		return nil
+	}
+
+	if burst < 1 {
+		burst = 1
+	}
+
+	return &OperatorRateLimiter{
+		limit:    rate.Limit(limit),
+		burst:    burst,
+		limiters: make(map[client.ObjectKey]*rate.Limiter),
+	}
+}
+
+// Wait blocks until a request of the named operation may be made to the account server of operator, or ctx is done.
+func (l *OperatorRateLimiter) Wait(ctx context.Context, operator *v1alpha1.Operator, operation string) (err error) {
+	if l == nil {
+		return nil
+	}
+
+	key := client.ObjectKeyFromObject(operator)
+
+	reservation := l.limiter(key).Reserve()
+
+	delay := reservation.Delay()
+	if delay == 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+		reservation.Cancel()
+
+		return fmt.Errorf("account server rate limit of operator %s would exceed context deadline", key)
+	}
+
+	labels := []string{key.String(), operation}
+
+	accountServerThrottledTotal.WithLabelValues(labels...).Inc()
+	accountServerThrottleSeconds.WithLabelValues(labels...).Observe(delay.Seconds())
+
+	// only throttled requests are traced, so the span shows when a reconcile was delayed by the limiter
+	_, span := startSpan(ctx, "OperatorRateLimiter.Wait", append(keyAttributes(key.Namespace, key.Name),
+		attribute.String("operation", operation),
+		attribute.Float64("delay_seconds", delay.Seconds()),
+	)...)
+	defer func() { endSpan(span, err) }()
+
+	waiting := accountServerWaiting.WithLabelValues(key.String())
+	waiting.Inc()
+	defer waiting.Dec()
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		reservation.Cancel()
+
+		return ctx.Err()
+	}
+}
+
+func (l *OperatorRateLimiter) limiter(key client.ObjectKey) *rate.Limiter {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limiter, ok := l.limiters[key]
+	if !ok {
+		limiter = rate.NewLimiter(l.limit, l.burst)
+		l.limiters[key] = limiter
+	}
+
+	return limiter
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+func TestOperatorRateLimiter(t *testing.T) {
+	ctx := context.Background()
+
+	operator := &v1alpha1.Operator{ObjectMeta: metav1.ObjectMeta{Name: "limited", Namespace: "nats"}}
+	other := &v1alpha1.Operator{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: "nats"}}
+
+	if l := NewOperatorRateLimiter(0, 10); l != nil {
+		t.Fatalf("NewOperatorRateLimiter(0) = %v, want nil", l)
+	}
+
+	var disabled *OperatorRateLimiter
+	if err := disabled.Wait(ctx, operator, "push"); err != nil {
+		t.Fatalf("nil limiter Wait() error = %v", err)
+	}
+
+	l := NewOperatorRateLimiter(20, 1)
+
+	if err := l.Wait(ctx, operator, "push"); err != nil {
+		t.Fatalf("Wait() within burst error = %v", err)
+	}
+
+	// every Operator has its own bucket
+	if err := l.Wait(ctx, other, "push"); err != nil {
+		t.Fatalf("Wait() for other operator error = %v", err)
+	}
+
+	throttled := accountServerThrottledTotal.WithLabelValues("nats/limited", "push")
+	before := testutil.ToFloat64(throttled)
+
+	start := time.Now()
+	if err := l.Wait(ctx, operator, "push"); err != nil {
+		t.Fatalf("Wait() over burst error = %v", err)
+	}
+
+	if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+		t.Errorf("Wait() over burst returned after %s, want it to be throttled", elapsed)
+	}
+
+	if got := testutil.ToFloat64(throttled) - before; got != 1 {
+		t.Errorf("throttled requests = %v, want 1", got)
+	}
+
+	deadline, cancel := context.WithTimeout(ctx, time.Millisecond)
+	defer cancel()
+
+	if err := l.Wait(deadline, operator, "delete"); err == nil {
+		t.Errorf("Wait() exceeding the context deadline error = nil, want error")
+	}
+}
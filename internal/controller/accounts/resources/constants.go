@@ -13,6 +13,7 @@ const (
 	LabelSecretTypeSigningKey = "SigningKey"
 	LabelSecretTypeAccount    = "Account"
 	LabelSecretTypeUser       = "User"
+	LabelSecretTypeXKey       = "XKey"
 
 	LabelSubject = "accounts.nats.io/subject"
 
@@ -0,0 +1,77 @@
+package resources
+
+import (
+	"fmt"
+
+	"github.com/nats-io/nkeys"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// XKeySecretBuilder builds the Secret containing the curve key pair used to encrypt auth callout requests for an
+// Account.
+type XKeySecretBuilder struct {
+	scheme *runtime.Scheme
+	secret *v1.Secret
+}
+
+func NewXKeySecretBuilder(scheme *runtime.Scheme) *XKeySecretBuilder {
+	return &XKeySecretBuilder{
+		scheme: scheme,
+		secret: &v1.Secret{},
+	}
+}
+
+func NewXKeySecretBuilderFromSecret(s *v1.Secret, scheme *runtime.Scheme) *XKeySecretBuilder {
+	return &XKeySecretBuilder{
+		scheme: scheme,
+		secret: s.DeepCopy(),
+	}
+}
+
+func (b *XKeySecretBuilder) Build(acc *v1alpha1.Account, kp nkeys.KeyPair, opts ...SecretOption) (*v1.Secret, error) {
+	if acc.Spec.Authorization == nil || acc.Spec.Authorization.XKey == nil {
+		return nil, fmt.Errorf("account does not define an authorization xkey")
+	}
+
+	for _, opt := range opts {
+		if err := opt(b.secret); err != nil {
+			return nil, fmt.Errorf("failed to apply option: %w", err)
+		}
+	}
+
+	seed, err := kp.Seed()
+	if err != nil {
+		return nil, err
+	}
+
+	pubkey, err := kp.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	if b.secret.Labels == nil {
+		b.secret.Labels = make(map[string]string)
+	}
+
+	b.secret.Labels[LabelSecretType] = LabelSecretTypeSeed
+	b.secret.Labels[LabelSecretSeedType] = LabelSecretTypeXKey
+	b.secret.Labels[LabelSubject] = pubkey
+	b.secret.Labels[LabelAccountName] = acc.Name
+
+	b.secret.Name = acc.Spec.Authorization.XKey.SeedSecretName
+	b.secret.Namespace = acc.GetNamespace()
+	b.secret.Data = map[string][]byte{
+		v1alpha1.NatsSecretSeedKey:      seed,
+		v1alpha1.NatsSecretPublicKeyKey: []byte(pubkey),
+	}
+
+	if err := controllerutil.SetControllerReference(acc, b.secret, b.scheme); err != nil {
+		return nil, fmt.Errorf("failed to set owner reference: %w", err)
+	}
+
+	return b.secret, nil
+}
@@ -0,0 +1,212 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// DefaultServerProbeInterval is how often the account server of each Operator is probed by default.
+const DefaultServerProbeInterval = 30 * time.Second
+
+const serverProbeTimeout = 5 * time.Second
+
+// ServerProber periodically connects to the account server of each ready Operator with its system account credentials
+// and pings it. The leader records the result in the ServerReachable condition of the Operator, and every replica
+// reports it through Checker so a replica which cannot reach an account server is not ready.
+type ServerProber struct {
+	*BaseReconciler
+
+	// Interval is how often each Operator is probed.
+	Interval time.Duration
+
+	// Elected is closed once this replica is the leader, only the leader writes the Operator status. A nil channel
+	// always writes.
+	Elected <-chan struct{}
+
+	logger logr.Logger
+
+	mu          sync.RWMutex
+	unreachable map[types.NamespacedName]error
+}
+
+var _ manager.Runnable = (*ServerProber)(nil)
+var _ manager.LeaderElectionRunnable = (*ServerProber)(nil)
+
+func NewServerProber(base *BaseReconciler, interval time.Duration, elected <-chan struct{}) *ServerProber {
+	if interval <= 0 {
+		interval = DefaultServerProbeInterval
+	}
+
+	return &ServerProber{
+		BaseReconciler: base,
+		Interval:       interval,
+		Elected:        elected,
+		logger:         log.Log.WithName("server-prober"),
+		unreachable:    make(map[types.NamespacedName]error),
+	}
+}
+
+// NeedLeaderElection returns false since readiness is reported by every replica.
+func (p *ServerProber) NeedLeaderElection() bool {
+	return false
+}
+
+// Start probes the account servers every Interval until the context is cancelled.
+func (p *ServerProber) Start(ctx context.Context) error {
+	ctx = log.IntoContext(ctx, p.logger)
+
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.probe(ctx)
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Checker is a healthz.Checker failing while the account server of any ready Operator is unreachable.
+func (p *ServerProber) Checker(_ *http.Request) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.unreachable) == 0 {
+		return nil
+	}
+
+	failures := make([]string, 0, len(p.unreachable))
+	for key, err := range p.unreachable {
+		failures = append(failures, fmt.Sprintf("%s: %s", key, err.Error()))
+	}
+
+	sort.Strings(failures)
+
+	return fmt.Errorf("account server unreachable: %s", strings.Join(failures, "; "))
+}
+
+func (p *ServerProber) probe(ctx context.Context) {
+	var operators v1alpha1.OperatorList
+
+	if err := p.List(ctx, &operators); err != nil {
+		p.logger.Error(err, "failed to list operators")
+
+		return
+	}
+
+	unreachable := make(map[types.NamespacedName]error)
+
+	for i := range operators.Items {
+		operator := &operators.Items[i]
+		key := client.ObjectKeyFromObject(operator)
+
+		// the system account credentials are not available until the Operator is ready
+		if !operator.Status.IsReady() || operator.Spec.AccountServerURL == "" {
+			continue
+		}
+
+		server, err := p.probeOperator(ctx, operator)
+		if err != nil {
+			unreachable[key] = err
+		}
+
+		if !p.isLeader() {
+			continue
+		}
+
+		if err := p.patchStatus(ctx, operator, func(status *v1alpha1.OperatorStatus) {
+			if err != nil {
+				status.MarkServerUnreachable(v1alpha1.ReasonServerUnreachable, "%s", err)
+
+				return
+			}
+
+			// only the time of the probe changed, which is not worth a status write every Period
+			if status.Server != nil && sameServer(*status.Server, *server) {
+				server.ObservedAt = status.Server.ObservedAt
+			}
+
+			status.MarkServerReachable(*server)
+		}); err != nil {
+			p.logger.Error(err, "failed to update operator status", "operator", key)
+		}
+	}
+
+	p.mu.Lock()
+	p.unreachable = unreachable
+	p.mu.Unlock()
+}
+
+func (p *ServerProber) probeOperator(ctx context.Context, operator *v1alpha1.Operator) (*v1alpha1.OperatorServerStatus, error) {
+	publisher, err := p.connectSystemUser(ctx, operator)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	defer publisher.Close()
+
+	ctx, cancel := context.WithTimeout(ctx, serverProbeTimeout)
+	defer cancel()
+
+	info, err := publisher.ServerInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ping server: %w", err)
+	}
+
+	return &v1alpha1.OperatorServerStatus{
+		Name:         info.Name,
+		Version:      info.Version,
+		JetStream:    info.JetStream,
+		ResolverType: info.ResolverType,
+		ObservedAt:   metav1.Now(),
+	}, nil
+}
+
+func (p *ServerProber) isLeader() bool {
+	if p.Elected == nil {
+		return true
+	}
+
+	select {
+	case <-p.Elected:
+		return true
+	default:
+		return false
+	}
+}
+
+// patchStatus applies mutate to the status of operator and patches it when it has changed, see patchStatusWithRetry.
+func (p *ServerProber) patchStatus(ctx context.Context, operator *v1alpha1.Operator, mutate func(*v1alpha1.OperatorStatus)) error {
+	return patchStatusWithRetry(ctx, p.Client, operator, func(operator *v1alpha1.Operator) bool {
+		original := operator.Status.DeepCopy()
+
+		mutate(&operator.Status)
+
+		return !equality.Semantic.DeepEqual(*original, operator.Status)
+	})
+}
+
+// sameServer reports whether a and b differ at most in ObservedAt.
+func sameServer(a, b v1alpha1.OperatorServerStatus) bool {
+	a.ObservedAt = b.ObservedAt
+
+	return equality.Semantic.DeepEqual(a, b)
+}
@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+	nscfake "github.com/versori-oss/nats-account-operator/pkg/nsc/fake"
+)
+
+func TestServerProber(t *testing.T) {
+	operator := &v1alpha1.Operator{
+		ObjectMeta: metav1.ObjectMeta{Name: "nats", Namespace: "a"},
+		Spec:       v1alpha1.OperatorSpec{AccountServerURL: "nats://nats:4222"},
+	}
+
+	// every dependent condition must be set for Ready to stay True when ServerReachable is marked
+	for _, t := range []apis.ConditionType{
+		apis.ConditionReady,
+		v1alpha1.OperatorConditionSystemAccountResolved,
+		v1alpha1.OperatorConditionSigningKeysUpdated,
+		v1alpha1.OperatorConditionJWTSecretReady,
+		v1alpha1.OperatorConditionSeedSecretReady,
+	} {
+		operator.Status.Conditions = append(operator.Status.Conditions, apis.Condition{Type: t, Status: corev1.ConditionTrue})
+	}
+
+	server, err := nscfake.NewAccountServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server.Server = nsc.ServerInfo{Name: "nats-0", Version: "2.10.14", JetStream: true, ResolverType: nsc.ResolverTypeFull}
+
+	c := newIndexedClient(t, operator)
+	ctx := context.Background()
+	elected := make(chan struct{})
+
+	prober := NewServerProber(&BaseReconciler{
+		Client:            c,
+		AccountServer:     server,
+		SystemCredentials: &nscfake.SystemCredentialsLoader{},
+	}, 0, elected)
+
+	get := func() *v1alpha1.Operator {
+		t.Helper()
+
+		got := new(v1alpha1.Operator)
+		if err := c.Get(ctx, client.ObjectKeyFromObject(operator), got); err != nil {
+			t.Fatal(err)
+		}
+
+		return got
+	}
+
+	// followers report readiness without writing status
+	server.ConnectErr = errors.New("connection refused")
+	prober.probe(ctx)
+
+	if err := prober.Checker(nil); err == nil || !strings.Contains(err.Error(), "a/nats") {
+		t.Errorf("Checker() = %v, want error for a/nats", err)
+	}
+
+	if cond := get().Status.GetCondition(v1alpha1.OperatorConditionServerReachable); cond != nil {
+		t.Errorf("%s condition = %+v, want none before election", v1alpha1.OperatorConditionServerReachable, cond)
+	}
+
+	close(elected)
+
+	server.ConnectErr = nil
+	prober.probe(ctx)
+
+	if err := prober.Checker(nil); err != nil {
+		t.Errorf("Checker() = %v, want nil", err)
+	}
+
+	got := get()
+	if cond := got.Status.GetCondition(v1alpha1.OperatorConditionServerReachable); !cond.IsTrue() {
+		t.Errorf("%s condition = %+v, want True", v1alpha1.OperatorConditionServerReachable, cond)
+	}
+
+	if s := got.Status.Server; s == nil || s.Name != "nats-0" || s.Version != "2.10.14" || !s.JetStream || s.ResolverType != nsc.ResolverTypeFull {
+		t.Errorf("status.server = %+v, want nats-0 2.10.14 with JetStream and the full resolver", s)
+	}
+
+	// a probe observing no change does not write the status
+	prober.probe(ctx)
+
+	if rv := get().ResourceVersion; rv != got.ResourceVersion {
+		t.Errorf("resourceVersion = %s, want %s unchanged", rv, got.ResourceVersion)
+	}
+
+	server.Server.Version = "2.10.15"
+	prober.probe(ctx)
+
+	if s := get().Status.Server; s.Version != "2.10.15" {
+		t.Errorf("status.server = %+v, want version 2.10.15", s)
+	}
+
+	server.ConnectErr = errors.New("connection refused: 100%")
+	prober.probe(ctx)
+
+	got = get()
+
+	cond := got.Status.GetCondition(v1alpha1.OperatorConditionServerReachable)
+	if !cond.IsFalse() || cond.Reason != v1alpha1.ReasonServerUnreachable {
+		t.Errorf("%s condition = %+v, want False/%s", v1alpha1.OperatorConditionServerReachable, cond, v1alpha1.ReasonServerUnreachable)
+	}
+
+	if !strings.HasSuffix(cond.Message, "connection refused: 100%") {
+		t.Errorf("%s message = %q, want the error verbatim", v1alpha1.OperatorConditionServerReachable, cond.Message)
+	}
+
+	if !got.Status.IsReady() {
+		t.Errorf("Ready = %+v, want ServerReachable not to affect Ready", got.Status.GetCondition(apis.ConditionReady))
+	}
+
+	if got.Status.Server == nil {
+		t.Error("status.server cleared, want the last observed server kept")
+	}
+}
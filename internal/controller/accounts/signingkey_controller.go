@@ -34,6 +34,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -41,9 +42,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
-	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -52,15 +51,11 @@ import (
 
 	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
 	"github.com/versori-oss/nats-account-operator/internal/controller/accounts/resources"
-	accountsclientsets "github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned/typed/accounts/v1alpha1"
 )
 
 // SigningKeyReconciler reconciles a SigningKey object
 type SigningKeyReconciler struct {
-	client.Client
-	Scheme           *runtime.Scheme
-	CoreV1           corev1.CoreV1Interface
-	AccountsV1Alpha1 accountsclientsets.AccountsV1alpha1Interface
+	*BaseReconciler
 }
 
 //+kubebuilder:rbac:groups=accounts.nats.io,resources=signingkeys,verbs=get;list;watch;create;update;patch;delete
@@ -73,6 +68,9 @@ type SigningKeyReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.13.0/pkg/reconcile
 func (r *SigningKeyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := startSpan(ctx, "SigningKeyReconciler.Reconcile", keyAttributes(req.Namespace, req.Name)...)
+	defer func() { endSpan(span, err) }()
+
 	logger := log.FromContext(ctx)
 
 	logger.V(1).Info("reconciling signing key", "name", req.Name)
@@ -82,8 +80,14 @@ func (r *SigningKeyReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	if isPaused(r.EventRecorder, signingKey) {
+		return ctrl.Result{}, nil
+	}
+
 	originalStatus := signingKey.Status.DeepCopy()
 
+	signingKey.Status.ObservedGeneration = signingKey.Generation
+
 	signingKey.Status.InitializeConditions()
 
 	defer func() {
@@ -123,7 +127,7 @@ func (r *SigningKeyReconciler) ensureKeyPair(ctx context.Context, signingKey *v1
 	logger := log.FromContext(ctx)
 
 	var publicKey string
-	secret, err := r.CoreV1.Secrets(signingKey.Namespace).Get(ctx, signingKey.Spec.SeedSecretName, metav1.GetOptions{})
+	secret, err := r.getSecret(ctx, signingKey.Namespace, signingKey.Spec.SeedSecretName)
 	if errors.IsNotFound(err) {
 		var keyPair nkeys.KeyPair
 		switch signingKey.Spec.OwnerRef.Kind {
@@ -157,8 +161,11 @@ func (r *SigningKeyReconciler) ensureKeyPair(ctx context.Context, signingKey *v1
 		}
 
 		labels := map[string]string{
-			"operator-name": signingKey.Spec.OwnerRef.Name,
-			"secret-type":   string(v1alpha1.NatsSecretTypeSKey),
+			"operator-name":               signingKey.Spec.OwnerRef.Name,
+			"secret-type":                 string(v1alpha1.NatsSecretTypeSKey),
+			resources.LabelSecretType:     resources.LabelSecretTypeSeed,
+			resources.LabelSecretSeedType: resources.LabelSecretTypeSigningKey,
+			resources.LabelSigningKeyName: signingKey.Name,
 		}
 
 		secret := NewSecret(signingKey.Spec.SeedSecretName, signingKey.Namespace, WithImmutable(true), WithLabels(labels), WithData(data))
@@ -168,7 +175,7 @@ func (r *SigningKeyReconciler) ensureKeyPair(ctx context.Context, signingKey *v1
 			return reconcile.Result{}, err
 		}
 
-		_, err = r.CoreV1.Secrets(signingKey.Namespace).Create(ctx, &secret, metav1.CreateOptions{})
+		err = r.Create(ctx, &secret)
 		if err != nil {
 			logger.Error(err, "failed to create seed secret")
 			return reconcile.Result{}, err
@@ -182,6 +189,11 @@ func (r *SigningKeyReconciler) ensureKeyPair(ctx context.Context, signingKey *v1
 		return reconcile.Result{}, err
 	} else {
 		publicKey = string(secret.Data[v1alpha1.NatsSecretPublicKeyKey])
+
+		if err := r.labelLegacySeedSecret(ctx, signingKey, secret); err != nil {
+			logger.Error(err, "failed to label seed secret")
+			return reconcile.Result{}, err
+		}
 	}
 
 	signingKey.Status.MarkSeedSecretReady(publicKey, signingKey.Spec.SeedSecretName)
@@ -189,6 +201,34 @@ func (r *SigningKeyReconciler) ensureKeyPair(ctx context.Context, signingKey *v1
 	return reconcile.Result{}, nil
 }
 
+// labelLegacySeedSecret adds the labels of a managed seed Secret to a Secret created for signingKey by a release which
+// did not set them. Only labelled Secrets are cached, see CacheByObject, so without them the Secret is read from the
+// API server on every reconcile and changes to it are not watched. Labels can be changed although the Secret is
+// immutable, and every SigningKey is reconciled on startup, so this migrates existing Secrets once after an upgrade.
+func (r *SigningKeyReconciler) labelLegacySeedSecret(ctx context.Context, signingKey *v1alpha1.SigningKey, secret *v1.Secret) error {
+	if _, ok := secret.Labels[resources.LabelSecretType]; ok || !metav1.IsControlledBy(secret, signingKey) {
+		return nil
+	}
+
+	original := secret.DeepCopy()
+
+	if secret.Labels == nil {
+		secret.Labels = make(map[string]string)
+	}
+
+	secret.Labels[resources.LabelSecretType] = resources.LabelSecretTypeSeed
+	secret.Labels[resources.LabelSecretSeedType] = resources.LabelSecretTypeSigningKey
+	secret.Labels[resources.LabelSigningKeyName] = signingKey.Name
+
+	if err := r.Patch(ctx, secret, client.MergeFrom(original)); err != nil {
+		return err
+	}
+
+	r.EventRecorder.Eventf(signingKey, v1.EventTypeNormal, "SeedSecretLabelled", "labelled secret: %s/%s", secret.Namespace, secret.Name)
+
+	return nil
+}
+
 func (r *SigningKeyReconciler) ensureOwnerResolved(ctx context.Context, signingKey *v1alpha1.SigningKey) error {
 	logger := log.FromContext(ctx)
 
@@ -321,8 +361,11 @@ func (r *SigningKeyReconciler) reconcileLabels(ctx context.Context, signingKey *
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *SigningKeyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	r.EventRecorder = mgr.GetEventRecorderFor("signingkey-controller")
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.SigningKey{}).
+		For(&v1alpha1.SigningKey{}, builder.WithPredicates(specChangedPredicate())).
+		WithOptions(r.controllerOptions()).
 		Owns(&v1.Secret{}).
 		Watches(&v1alpha1.Operator{}, signingKeyOperatorWatcher(mgr.GetLogger(), mgr.GetClient())).
 		Watches(&v1alpha1.Account{}, signingKeyAccountWatcher(mgr.GetLogger(), mgr.GetClient())).
@@ -342,27 +385,14 @@ func signingKeyOperatorWatcher(logger logr.Logger, c client.Client) handler.Even
 			return nil
 		}
 
-		var signingKeyList v1alpha1.SigningKeyList
-
-		if err := c.List(ctx, &signingKeyList, client.InNamespace(operator.Namespace), client.MatchingLabels{
-			resources.LabelOperatorName: operator.Name,
-		}); err != nil {
-			logger.Error(err, "failed to list accounts for operator during enqueue handler", "operator", operator.Name)
+		requests, err := listReferencing(ctx, c, &v1alpha1.SigningKeyList{}, operator,
+			indexQuery{field: IndexStatusOwnerRef, keep: ownedBy("Operator")})
+		if err != nil {
+			logger.Error(err, "failed to list signing keys for operator during enqueue handler", "operator", client.ObjectKeyFromObject(operator))
 
 			return nil
 		}
 
-		requests := make([]reconcile.Request, len(signingKeyList.Items))
-
-		for i, acc := range signingKeyList.Items {
-			requests[i] = reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Name:      acc.Name,
-					Namespace: acc.Namespace,
-				},
-			}
-		}
-
 		return requests
 	})
 }
@@ -378,27 +408,14 @@ func signingKeyAccountWatcher(logger logr.Logger, c client.Client) handler.Event
 			return nil
 		}
 
-		var signingKeyList v1alpha1.SigningKeyList
-
-		if err := c.List(ctx, &signingKeyList, client.InNamespace(account.Namespace), client.MatchingLabels{
-			resources.LabelAccountName: account.Name,
-		}); err != nil {
-			logger.Error(err, "failed to list signing keys for account during enqueue handler", "account", account.Name)
+		requests, err := listReferencing(ctx, c, &v1alpha1.SigningKeyList{}, account,
+			indexQuery{field: IndexStatusOwnerRef, keep: ownedBy("Account")})
+		if err != nil {
+			logger.Error(err, "failed to list signing keys for account during enqueue handler", "account", client.ObjectKeyFromObject(account))
 
 			return nil
 		}
 
-		requests := make([]reconcile.Request, len(signingKeyList.Items))
-
-		for i, acc := range signingKeyList.Items {
-			requests[i] = reconcile.Request{
-				NamespacedName: types.NamespacedName{
-					Name:      acc.Name,
-					Namespace: acc.Namespace,
-				},
-			}
-		}
-
 		return requests
 	})
 }
@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/internal/controller/accounts/resources"
+)
+
+func Test_SigningKeyReconciler_labelLegacySeedSecret(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	signingKey := &v1alpha1.SigningKey{
+		ObjectMeta: metav1.ObjectMeta{Name: "sk", Namespace: "nats", UID: "sk-uid"},
+	}
+
+	controllerRef := metav1.OwnerReference{
+		APIVersion: v1alpha1.GroupVersion.String(),
+		Kind:       "SigningKey",
+		Name:       signingKey.Name,
+		UID:        signingKey.UID,
+		Controller: ptr.To(true),
+	}
+
+	tests := []struct {
+		name       string
+		labels     map[string]string
+		owners     []metav1.OwnerReference
+		wantLabels map[string]string
+		wantEvent  bool
+	}{
+		{
+			name:   "created by an earlier release",
+			labels: map[string]string{"operator-name": "op", "secret-type": string(v1alpha1.NatsSecretTypeSKey)},
+			owners: []metav1.OwnerReference{controllerRef},
+			wantLabels: map[string]string{
+				"operator-name":               "op",
+				"secret-type":                 string(v1alpha1.NatsSecretTypeSKey),
+				resources.LabelSecretType:     resources.LabelSecretTypeSeed,
+				resources.LabelSecretSeedType: resources.LabelSecretTypeSigningKey,
+				resources.LabelSigningKeyName: signingKey.Name,
+			},
+			wantEvent: true,
+		},
+		{
+			name:       "already labelled",
+			labels:     map[string]string{resources.LabelSecretType: resources.LabelSecretTypeSeed},
+			owners:     []metav1.OwnerReference{controllerRef},
+			wantLabels: map[string]string{resources.LabelSecretType: resources.LabelSecretTypeSeed},
+		},
+		{
+			name: "not owned",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secret := &corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:            "sk-seed",
+					Namespace:       "nats",
+					Labels:          tt.labels,
+					OwnerReferences: tt.owners,
+				},
+				Immutable: ptr.To(true),
+			}
+
+			c := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+			recorder := record.NewFakeRecorder(1)
+
+			r := &SigningKeyReconciler{
+				BaseReconciler: &BaseReconciler{Client: c, Scheme: scheme, EventRecorder: recorder},
+			}
+
+			if err := r.labelLegacySeedSecret(context.Background(), signingKey, secret); err != nil {
+				t.Fatalf("labelLegacySeedSecret() error = %v", err)
+			}
+
+			got := new(corev1.Secret)
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(secret), got); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got.Labels) != len(tt.wantLabels) {
+				t.Fatalf("labels = %v, want %v", got.Labels, tt.wantLabels)
+			}
+
+			for k, v := range tt.wantLabels {
+				if got.Labels[k] != v {
+					t.Errorf("labels = %v, want %v", got.Labels, tt.wantLabels)
+				}
+			}
+
+			if gotEvent := len(recorder.Events) == 1; gotEvent != tt.wantEvent {
+				t.Errorf("recorded event = %v, want %v", gotEvent, tt.wantEvent)
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/equality"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+)
+
+const (
+	// DefaultUsageRefreshInterval is used for Operators which do not set spec.usageRefreshInterval.
+	DefaultUsageRefreshInterval = 5 * time.Minute
+
+	// usageLimitThreshold is the fraction of a limit above which an Account is approaching its limits.
+	usageLimitThreshold = 0.8
+
+	usageRequestTimeout = 10 * time.Second
+)
+
+// UsagePoller periodically queries the account server of each Operator for the usage of its Accounts, writing a
+// summary to the Account status and marking the UsageWithinLimits condition. Usage is collected outside of the Account
+// reconciler since every Account reconcile signs and pushes the Account JWT.
+type UsagePoller struct {
+	*BaseReconciler
+
+	// Period is how often Operators are checked for a due refresh, it bounds the precision of
+	// spec.usageRefreshInterval. Defaults to 15s.
+	Period time.Duration
+
+	logger   logr.Logger
+	now      func() time.Time
+	lastPoll map[types.NamespacedName]time.Time
+}
+
+var _ manager.Runnable = (*UsagePoller)(nil)
+var _ manager.LeaderElectionRunnable = (*UsagePoller)(nil)
+
+func NewUsagePoller(base *BaseReconciler) *UsagePoller {
+	return &UsagePoller{
+		BaseReconciler: base,
+		Period:         15 * time.Second,
+		logger:         log.Log.WithName("usage-poller"),
+		now:            time.Now,
+		lastPoll:       make(map[types.NamespacedName]time.Time),
+	}
+}
+
+// NeedLeaderElection returns true so only the leader writes usage to the Account status.
+func (p *UsagePoller) NeedLeaderElection() bool {
+	return true
+}
+
+// Start refreshes the usage of Accounts every Period until the context is cancelled.
+func (p *UsagePoller) Start(ctx context.Context) error {
+	ctx = log.IntoContext(ctx, p.logger)
+
+	ticker := time.NewTicker(p.Period)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			p.poll(ctx)
+		}
+	}
+}
+
+// poll refreshes the usage of the Accounts of every Operator whose refresh interval has elapsed since the last poll.
+func (p *UsagePoller) poll(ctx context.Context) {
+	var operators v1alpha1.OperatorList
+
+	if err := p.List(ctx, &operators); err != nil {
+		p.logger.Error(err, "failed to list operators")
+
+		return
+	}
+
+	now := p.now()
+	seen := make(map[types.NamespacedName]bool, len(operators.Items))
+
+	for i := range operators.Items {
+		operator := &operators.Items[i]
+		key := client.ObjectKeyFromObject(operator)
+		seen[key] = true
+
+		interval := usageRefreshInterval(operator)
+		if interval <= 0 {
+			delete(p.lastPoll, key)
+
+			if err := p.clearOperatorUsage(ctx, operator); err != nil {
+				p.logger.Error(err, "failed to clear account usage", "operator", key)
+			}
+
+			continue
+		}
+
+		if last, ok := p.lastPoll[key]; ok && now.Sub(last) < interval {
+			continue
+		}
+
+		if !operator.Status.IsReady() {
+			continue
+		}
+
+		p.lastPoll[key] = now
+
+		if err := p.refreshOperatorUsage(ctx, operator); err != nil {
+			p.logger.Error(err, "failed to refresh account usage", "operator", key)
+		}
+	}
+
+	// forget deleted Operators
+	for key := range p.lastPoll {
+		if !seen[key] {
+			delete(p.lastPoll, key)
+		}
+	}
+}
+
+func usageRefreshInterval(operator *v1alpha1.Operator) time.Duration {
+	if operator.Spec.UsageRefreshInterval == nil {
+		return DefaultUsageRefreshInterval
+	}
+
+	return operator.Spec.UsageRefreshInterval.Duration
+}
+
+func (p *UsagePoller) listOperatorAccounts(ctx context.Context, operator *v1alpha1.Operator) ([]v1alpha1.Account, error) {
+	var accounts v1alpha1.AccountList
+
+	if err := p.List(ctx, &accounts, client.MatchingFields{
+		IndexStatusOperatorRef: refIndexKey(operator.Namespace, operator.Name),
+	}); err != nil {
+		return nil, fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	return accounts.Items, nil
+}
+
+func (p *UsagePoller) refreshOperatorUsage(ctx context.Context, operator *v1alpha1.Operator) error {
+	accounts, err := p.listOperatorAccounts(ctx, operator)
+	if err != nil || len(accounts) == 0 {
+		return err
+	}
+
+	// usage requests are not rate limited since each Operator is polled at most once per refresh interval
+	publisher, err := p.connectSystemUser(ctx, operator)
+	if err != nil {
+		for i := range accounts {
+			acc := &accounts[i]
+
+			if patchErr := p.patchStatus(ctx, acc, func(status *v1alpha1.AccountStatus) {
+				status.MarkUsageUnknown(v1alpha1.ReasonUsageUnavailable, "failed to connect to account server: %s", err.Error())
+			}); patchErr != nil {
+				p.logger.Error(patchErr, "failed to update account status", "account", client.ObjectKeyFromObject(acc))
+			}
+		}
+
+		return err
+	}
+
+	defer publisher.Close()
+
+	for i := range accounts {
+		acc := &accounts[i]
+
+		if acc.DeletionTimestamp != nil || acc.Status.KeyPair == nil {
+			continue
+		}
+
+		if err := p.refreshAccountUsage(ctx, publisher, acc); err != nil {
+			p.logger.Error(err, "failed to refresh account usage", "account", client.ObjectKeyFromObject(acc))
+		}
+	}
+
+	return nil
+}
+
+func (p *UsagePoller) refreshAccountUsage(ctx context.Context, publisher nsc.AccountPublisher, acc *v1alpha1.Account) error {
+	reqCtx, cancel := context.WithTimeout(ctx, usageRequestTimeout)
+	defer cancel()
+
+	usage, err := publisher.Usage(reqCtx, acc.Status.KeyPair.PublicKey)
+	if err != nil {
+		return p.patchStatus(ctx, acc, func(status *v1alpha1.AccountStatus) {
+			status.MarkUsageUnknown(v1alpha1.ReasonUsageUnavailable, "failed to query account usage: %s", err.Error())
+		})
+	}
+
+	summary := accountUsageSummary(usage, acc.Spec.Limits, p.now())
+
+	return p.patchStatus(ctx, acc, func(status *v1alpha1.AccountStatus) {
+		// when only the time of the poll changed the status is left as it is, since every write re-enqueues the
+		// Operator, Users and SigningKeys of the Account
+		if status.Usage != nil && sameUsage(*status.Usage, summary) {
+			unchanged := status.DeepCopy()
+
+			observed := summary
+			observed.ObservedAt = status.Usage.ObservedAt
+			markUsage(unchanged, observed, acc.Spec.Limits)
+
+			if equality.Semantic.DeepEqual(*unchanged, *status) {
+				return
+			}
+		}
+
+		markUsage(status, summary, acc.Spec.Limits)
+	})
+}
+
+// markUsage sets the usage summary of status, and whether it is approaching the limits of the Account.
+func markUsage(status *v1alpha1.AccountStatus, summary v1alpha1.AccountUsage, limits *v1alpha1.OperatorLimits) {
+	if exceeded := approachingLimits(summary, limits); len(exceeded) > 0 {
+		status.MarkUsageApproachingLimits(summary, "usage is approaching the Account limits: %s", strings.Join(exceeded, ", "))
+	} else {
+		status.MarkUsageWithinLimits(summary)
+	}
+}
+
+// sameUsage reports whether a and b differ at most in ObservedAt.
+func sameUsage(a, b v1alpha1.AccountUsage) bool {
+	a.ObservedAt = b.ObservedAt
+
+	return equality.Semantic.DeepEqual(a, b)
+}
+
+// clearOperatorUsage removes the usage summary from the Accounts of an Operator with usage collection disabled.
+func (p *UsagePoller) clearOperatorUsage(ctx context.Context, operator *v1alpha1.Operator) error {
+	accounts, err := p.listOperatorAccounts(ctx, operator)
+	if err != nil {
+		return err
+	}
+
+	for i := range accounts {
+		acc := &accounts[i]
+
+		if err := p.patchStatus(ctx, acc, (*v1alpha1.AccountStatus).ClearUsage); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// patchStatus applies mutate to the status of acc and patches it when it has changed, see patchStatusWithRetry.
+func (p *UsagePoller) patchStatus(ctx context.Context, acc *v1alpha1.Account, mutate func(*v1alpha1.AccountStatus)) error {
+	return patchStatusWithRetry(ctx, p.Client, acc, func(acc *v1alpha1.Account) bool {
+		original := acc.Status.DeepCopy()
+
+		mutate(&acc.Status)
+
+		return !equality.Semantic.DeepEqual(*original, acc.Status)
+	})
+}
+
+// accountUsageSummary converts usage reported by the account server into the Account status, including the JetStream
+// storage limits of the Account.
+func accountUsageSummary(usage *nsc.AccountUsage, limits *v1alpha1.OperatorLimits, now time.Time) v1alpha1.AccountUsage {
+	summary := v1alpha1.AccountUsage{
+		Connections:   int64(usage.Connections),
+		LeafNodes:     int64(usage.LeafNodes),
+		Subscriptions: int64(usage.Subscriptions),
+		ObservedAt:    metav1.NewTime(now),
+	}
+
+	if !usage.LastActivity.IsZero() {
+		// truncated to the precision the API server stores, so that an unchanged summary compares equal to the status
+		lastActivity := metav1.NewTime(usage.LastActivity.Truncate(time.Second))
+		summary.LastActivity = &lastActivity
+	}
+
+	if js := usage.JetStream; js != nil {
+		memoryLimit, diskLimit := jetStreamStorageLimits(limits)
+
+		summary.JetStream = &v1alpha1.JetStreamUsage{
+			MemoryBytes:      int64(js.Memory),
+			MemoryLimitBytes: memoryLimit,
+			DiskBytes:        int64(js.Storage),
+			DiskLimitBytes:   diskLimit,
+		}
+	}
+
+	return summary
+}
+
+// jetStreamStorageLimits returns the memory and disk storage limits of an Account, summed across tiers when tiered
+// limits are set. A limit of 0 is unlimited.
+func jetStreamStorageLimits(limits *v1alpha1.OperatorLimits) (memory, disk int64) {
+	if limits == nil {
+		return 0, 0
+	}
+
+	tiers := []v1alpha1.JetStreamLimits{limits.JetStream}
+	if len(limits.TieredJetStream) > 0 {
+		tiers = tiers[:0]
+		for _, tier := range limits.TieredJetStream {
+			tiers = append(tiers, tier)
+		}
+	}
+
+	var memoryUnlimited, diskUnlimited bool
+
+	for _, tier := range tiers {
+		memoryUnlimited = memoryUnlimited || tier.MemoryStorage < 0
+		diskUnlimited = diskUnlimited || tier.DiskStorage < 0
+
+		memory += max(tier.MemoryStorage, 0)
+		disk += max(tier.DiskStorage, 0)
+	}
+
+	if memoryUnlimited {
+		memory = 0
+	}
+
+	if diskUnlimited {
+		disk = 0
+	}
+
+	return memory, disk
+}
+
+// approachingLimits returns a description of each limit which usage is within usageLimitThreshold of.
+func approachingLimits(usage v1alpha1.AccountUsage, limits *v1alpha1.OperatorLimits) []string {
+	if limits == nil {
+		return nil
+	}
+
+	var exceeded []string
+
+	check := func(name string, used int64, limit *int64) {
+		if limit == nil || *limit <= 0 {
+			return
+		}
+
+		if float64(used) >= usageLimitThreshold*float64(*limit) {
+			exceeded = append(exceeded, fmt.Sprintf("%s %d/%d", name, used, *limit))
+		}
+	}
+
+	check("connections", usage.Connections, limits.Account.Conn)
+	check("leaf nodes", usage.LeafNodes, limits.Account.LeafNodeConn)
+	check("subscriptions", usage.Subscriptions, limits.Nats.Subs)
+
+	if js := usage.JetStream; js != nil {
+		check("JetStream memory bytes", js.MemoryBytes, &js.MemoryLimitBytes)
+		check("JetStream disk bytes", js.DiskBytes, &js.DiskLimitBytes)
+	}
+
+	return exceeded
+}
@@ -0,0 +1,315 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+	nscfake "github.com/versori-oss/nats-account-operator/pkg/nsc/fake"
+)
+
+func TestUsagePoller_poll(t *testing.T) {
+	const accountPub = "ACCOUNT"
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	lastActivity := now.Add(-time.Minute)
+
+	limits := &v1alpha1.OperatorLimits{
+		Nats:      v1alpha1.NatsLimits{Subs: ptr.To[int64](100)},
+		Account:   v1alpha1.AccountLimits{Conn: ptr.To[int64](10), LeafNodeConn: ptr.To[int64](-1)},
+		JetStream: v1alpha1.JetStreamLimits{MemoryStorage: 1000, DiskStorage: -1},
+	}
+
+	tests := []struct {
+		name          string
+		interval      *metav1.Duration
+		usage         nsc.AccountUsage
+		usageErr      error
+		existing      *v1alpha1.AccountUsage
+		wantUsage     *v1alpha1.AccountUsage
+		wantCondition corev1.ConditionStatus
+		wantReason    string
+	}{
+		{
+			name:  "within limits",
+			usage: nsc.AccountUsage{Connections: 2, LeafNodes: 50, Subscriptions: 10, LastActivity: lastActivity},
+			wantUsage: &v1alpha1.AccountUsage{
+				Connections:   2,
+				LeafNodes:     50,
+				Subscriptions: 10,
+				LastActivity:  ptr.To(metav1.NewTime(lastActivity)),
+				ObservedAt:    metav1.NewTime(now),
+			},
+			wantCondition: corev1.ConditionTrue,
+		},
+		{
+			name: "approaching limits",
+			usage: nsc.AccountUsage{
+				Connections:   8,
+				Subscriptions: 10,
+				JetStream:     &nsc.JetStreamUsage{Memory: 900, Storage: 1 << 30},
+			},
+			wantUsage: &v1alpha1.AccountUsage{
+				Connections:   8,
+				Subscriptions: 10,
+				JetStream:     &v1alpha1.JetStreamUsage{MemoryBytes: 900, MemoryLimitBytes: 1000, DiskBytes: 1 << 30},
+				ObservedAt:    metav1.NewTime(now),
+			},
+			wantCondition: corev1.ConditionFalse,
+			wantReason:    v1alpha1.ReasonApproachingLimits,
+		},
+		{
+			name:          "usage unavailable",
+			usageErr:      errors.New("nats: timeout"),
+			existing:      &v1alpha1.AccountUsage{Connections: 1},
+			wantUsage:     &v1alpha1.AccountUsage{Connections: 1},
+			wantCondition: corev1.ConditionUnknown,
+			wantReason:    v1alpha1.ReasonUsageUnavailable,
+		},
+		{
+			name:     "disabled",
+			interval: &metav1.Duration{},
+			existing: &v1alpha1.AccountUsage{Connections: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			operator := &v1alpha1.Operator{
+				ObjectMeta: metav1.ObjectMeta{Name: "nats", Namespace: "a"},
+				Spec:       v1alpha1.OperatorSpec{UsageRefreshInterval: tt.interval},
+				Status: v1alpha1.OperatorStatus{Status: v1alpha1.Status{
+					Conditions: apis.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}},
+				}},
+			}
+
+			acc := &v1alpha1.Account{
+				ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "b"},
+				Spec:       v1alpha1.AccountSpec{Limits: limits},
+				Status: v1alpha1.AccountStatus{
+					OperatorRef: &v1alpha1.InferredObjectReference{Namespace: "a", Name: "nats"},
+					KeyPair:     &v1alpha1.KeyPair{PublicKey: accountPub},
+					Usage:       tt.existing,
+				},
+			}
+
+			if tt.existing != nil {
+				acc.Status.MarkUsageWithinLimits(*tt.existing)
+			}
+
+			server, err := nscfake.NewAccountServer()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			server.UsageErr = tt.usageErr
+			server.SetUsage(accountPub, tt.usage)
+
+			c := newIndexedClient(t, operator, acc)
+
+			poller := NewUsagePoller(&BaseReconciler{
+				Client:            c,
+				AccountServer:     server,
+				SystemCredentials: &nscfake.SystemCredentialsLoader{},
+			})
+			poller.now = func() time.Time { return now }
+
+			poller.poll(context.Background())
+
+			got := new(v1alpha1.Account)
+			if err := c.Get(context.Background(), client.ObjectKeyFromObject(acc), got); err != nil {
+				t.Fatal(err)
+			}
+
+			if !equalUsage(got.Status.Usage, tt.wantUsage) {
+				t.Errorf("status.usage = %+v, want %+v", got.Status.Usage, tt.wantUsage)
+			}
+
+			cond := got.Status.GetCondition(v1alpha1.AccountConditionUsageWithinLimits)
+
+			switch {
+			case tt.wantCondition == "" && cond != nil:
+				t.Errorf("%s condition = %+v, want none", v1alpha1.AccountConditionUsageWithinLimits, cond)
+			case tt.wantCondition != "" && cond == nil:
+				t.Errorf("%s condition missing, want %s", v1alpha1.AccountConditionUsageWithinLimits, tt.wantCondition)
+			case cond != nil && (cond.Status != tt.wantCondition || cond.Reason != tt.wantReason):
+				t.Errorf("%s condition = %s/%s, want %s/%s", v1alpha1.AccountConditionUsageWithinLimits,
+					cond.Status, cond.Reason, tt.wantCondition, tt.wantReason)
+			}
+		})
+	}
+}
+
+// equalUsage compares usage ignoring the precision lost by serialising times.
+func equalUsage(got, want *v1alpha1.AccountUsage) bool {
+	if got == nil || want == nil {
+		return got == want
+	}
+
+	return got.Connections == want.Connections &&
+		got.LeafNodes == want.LeafNodes &&
+		got.Subscriptions == want.Subscriptions &&
+		got.LastActivity.Equal(want.LastActivity) &&
+		got.ObservedAt.Equal(&want.ObservedAt) &&
+		(got.JetStream == nil) == (want.JetStream == nil) &&
+		(got.JetStream == nil || *got.JetStream == *want.JetStream)
+}
+
+func Test_jetStreamStorageLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		limits     *v1alpha1.OperatorLimits
+		wantMemory int64
+		wantDisk   int64
+	}{
+		{name: "no limits"},
+		{
+			name:       "global",
+			limits:     &v1alpha1.OperatorLimits{JetStream: v1alpha1.JetStreamLimits{MemoryStorage: 10, DiskStorage: -1}},
+			wantMemory: 10,
+		},
+		{
+			name: "tiered",
+			limits: &v1alpha1.OperatorLimits{TieredJetStream: map[string]v1alpha1.JetStreamLimits{
+				"R1": {MemoryStorage: 10, DiskStorage: 100},
+				"R3": {MemoryStorage: 20, DiskStorage: 300},
+			}},
+			wantMemory: 30,
+			wantDisk:   400,
+		},
+		{
+			name: "tiered with an unlimited tier",
+			limits: &v1alpha1.OperatorLimits{TieredJetStream: map[string]v1alpha1.JetStreamLimits{
+				"R1": {MemoryStorage: 10, DiskStorage: -1},
+				"R3": {MemoryStorage: 20, DiskStorage: 300},
+			}},
+			wantMemory: 30,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			memory, disk := jetStreamStorageLimits(tt.limits)
+			if memory != tt.wantMemory || disk != tt.wantDisk {
+				t.Errorf("jetStreamStorageLimits() = %d, %d, want %d, %d", memory, disk, tt.wantMemory, tt.wantDisk)
+			}
+		})
+	}
+}
+
+func TestUsagePoller_poll_unchanged(t *testing.T) {
+	const accountPub = "ACCOUNT"
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	operator := &v1alpha1.Operator{
+		ObjectMeta: metav1.ObjectMeta{Name: "nats", Namespace: "a"},
+		Spec:       v1alpha1.OperatorSpec{UsageRefreshInterval: &metav1.Duration{Duration: time.Minute}},
+		Status: v1alpha1.OperatorStatus{Status: v1alpha1.Status{
+			Conditions: apis.Conditions{{Type: apis.ConditionReady, Status: corev1.ConditionTrue}},
+		}},
+	}
+
+	acc := &v1alpha1.Account{
+		ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "b"},
+		Status: v1alpha1.AccountStatus{
+			OperatorRef: &v1alpha1.InferredObjectReference{Namespace: "a", Name: "nats"},
+			KeyPair:     &v1alpha1.KeyPair{PublicKey: accountPub},
+		},
+	}
+
+	server, err := nscfake.NewAccountServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the account server reports the last activity with a precision the API server does not store
+	server.SetUsage(accountPub, nsc.AccountUsage{Connections: 2, LastActivity: now.Add(-1500 * time.Millisecond)})
+
+	c := newIndexedClient(t, operator, acc)
+
+	poller := NewUsagePoller(&BaseReconciler{
+		Client:            c,
+		AccountServer:     server,
+		SystemCredentials: &nscfake.SystemCredentialsLoader{},
+	})
+
+	poll := func(at time.Time) *v1alpha1.Account {
+		t.Helper()
+
+		poller.now = func() time.Time { return at }
+		poller.poll(context.Background())
+
+		got := new(v1alpha1.Account)
+		if err := c.Get(context.Background(), client.ObjectKeyFromObject(acc), got); err != nil {
+			t.Fatal(err)
+		}
+
+		return got
+	}
+
+	first := poll(now)
+	second := poll(now.Add(time.Minute))
+
+	if second.ResourceVersion != first.ResourceVersion {
+		t.Errorf("resourceVersion = %s, want %s unchanged by an identical poll", second.ResourceVersion, first.ResourceVersion)
+	}
+
+	server.SetUsage(accountPub, nsc.AccountUsage{Connections: 3, LastActivity: now})
+
+	third := poll(now.Add(2 * time.Minute))
+
+	if third.Status.Usage == nil || third.Status.Usage.Connections != 3 ||
+		!third.Status.Usage.ObservedAt.Equal(ptr.To(metav1.NewTime(now.Add(2*time.Minute)))) {
+		t.Errorf("status.usage = %+v, want 3 connections observed at the third poll", third.Status.Usage)
+	}
+}
+
+func TestUsagePoller_patchStatus_conflict(t *testing.T) {
+	acc := &v1alpha1.Account{ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: "b"}}
+
+	c := newIndexedClient(t, acc)
+
+	stale := new(v1alpha1.Account)
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(acc), stale); err != nil {
+		t.Fatal(err)
+	}
+
+	// the Account reconciler resolves the Operator after the poller listed the Account
+	latest := stale.DeepCopy()
+	latest.Status.MarkOperatorResolved(v1alpha1.InferredObjectReference{Namespace: "a", Name: "nats"})
+
+	if err := c.Status().Update(context.Background(), latest); err != nil {
+		t.Fatal(err)
+	}
+
+	poller := NewUsagePoller(&BaseReconciler{Client: c})
+
+	if err := poller.patchStatus(context.Background(), stale, func(status *v1alpha1.AccountStatus) {
+		status.MarkUsageWithinLimits(v1alpha1.AccountUsage{Connections: 1})
+	}); err != nil {
+		t.Fatalf("patchStatus() error = %v", err)
+	}
+
+	got := new(v1alpha1.Account)
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(acc), got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Status.OperatorRef == nil || !got.Status.GetCondition(v1alpha1.AccountConditionOperatorResolved).IsTrue() {
+		t.Errorf("status = %+v, want the concurrent write kept", got.Status)
+	}
+
+	if got.Status.Usage == nil || got.Status.Usage.Connections != 1 {
+		t.Errorf("status.usage = %+v, want 1 connection", got.Status.Usage)
+	}
+}
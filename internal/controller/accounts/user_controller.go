@@ -40,6 +40,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
@@ -66,6 +67,9 @@ type UserReconciler struct {
 // For more details, check Reconcile and its Result here:
 // - https://pkg.go.dev/sigs.k8s.io/controller-runtime@v0.13.0/pkg/reconcile
 func (r *UserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (result ctrl.Result, err error) {
+	ctx, span := startSpan(ctx, "UserReconciler.Reconcile", keyAttributes(req.Namespace, req.Name)...)
+	defer func() { endSpan(span, err) }()
+
 	logger := log.FromContext(ctx)
 
 	usr := new(v1alpha1.User)
@@ -73,8 +77,14 @@ func (r *UserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
+	if isPaused(r.EventRecorder, usr) {
+		return ctrl.Result{}, nil
+	}
+
 	originalStatus := usr.Status.DeepCopy()
 
+	usr.Status.ObservedGeneration = usr.Generation
+
 	usr.Status.InitializeConditions()
 
 	defer func() {
@@ -93,7 +103,8 @@ func (r *UserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 		}
 	}()
 
-	kp, seed, result, err := r.reconcileSeedSecret(ctx, usr, nkeys.CreateUser, usr.Spec.SeedSecretName)
+	kp, seed, result, err := r.reconcileKeyPair(ctx, usr, nkeys.CreateUser, nkeys.PrefixByteUser,
+		usr.Spec.ExistingSeedSecretRef, usr.Spec.SeedSecretName)
 	if err != nil {
 		logger.Error(err, "failed to reconcile seed secret")
 
@@ -141,16 +152,27 @@ func (r *UserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 
 	logger.V(1).Info("reconciling user JWT secret")
 
-	ujwt, result, err := r.reconcileJWTSecret(ctx, usr, acc, keyPairable)
+	forcePush := forcePushRequest(usr, usr.Status.LastHandledForcePushAt)
+
+	ujwt, result, err := r.reconcileJWTSecret(ctx, usr, acc, keyPairable, forcePush != "")
+	if err != nil {
+		MarkCondition(err, usr.Status.MarkJWTSecretFailed, usr.Status.MarkJWTSecretUnknown)
+
+		return AsResult(err)
+	}
+
+	claims, err := newClaimsSummary(ujwt)
 	if err != nil {
 		MarkCondition(err, usr.Status.MarkJWTSecretFailed, usr.Status.MarkJWTSecretUnknown)
 
 		return AsResult(err)
 	}
 
-	usr.Status.MarkJWTSecretReady()
+	usr.Status.MarkJWTSecretReady(claims)
 
-	if !result.IsZero() {
+	// a forced push carries on to update the credentials with the JWT it has just re-signed, otherwise the next
+	// reconcile would see the request as pending and re-sign it again
+	if !result.IsZero() && forcePush == "" {
 		return result, nil
 	}
 
@@ -161,11 +183,19 @@ func (r *UserReconciler) Reconcile(ctx context.Context, req ctrl.Request) (resul
 		return result, fmt.Errorf("failed to reconcile user credential secret: %w", err)
 	}
 
+	if forcePush != "" {
+		usr.Status.LastHandledForcePushAt = forcePush
+
+		r.EventRecorder.Eventf(usr, v1.EventTypeNormal, "ForcePushed", "re-signed JWT and credentials for %s=%s", v1alpha1.AnnotationForcePushAt, forcePush)
+	}
+
 	return result, nil
 }
 
 func (r *UserReconciler) validateAccountSelector(ctx context.Context, account *v1alpha1.Account, user *v1alpha1.User) error {
-	ns, err := r.CoreV1.Namespaces().Get(ctx, user.Namespace, metav1.GetOptions{})
+	ns := new(v1.Namespace)
+
+	err := r.Get(ctx, client.ObjectKey{Name: user.Namespace}, ns)
 	if err != nil {
 		return TemporaryError(fmt.Errorf("failed to get user namespace: %w", err))
 	}
@@ -249,7 +279,7 @@ func (r *UserReconciler) resolveAccount(ctx context.Context, user *v1alpha1.User
 	return account, nil
 }
 
-func (r *UserReconciler) reconcileJWTSecret(ctx context.Context, usr *v1alpha1.User, account *v1alpha1.Account, keyPairable v1alpha1.KeyPairable) (string, reconcile.Result, error) {
+func (r *UserReconciler) reconcileJWTSecret(ctx context.Context, usr *v1alpha1.User, account *v1alpha1.Account, keyPairable v1alpha1.KeyPairable, force bool) (_ string, result reconcile.Result, err error) {
 	logger := log.FromContext(ctx)
 
 	issuerKP, err := r.loadIssuerSeed(ctx, keyPairable, nkeys.PrefixByteAccount)
@@ -269,7 +299,9 @@ func (r *UserReconciler) reconcileJWTSecret(ctx context.Context, usr *v1alpha1.U
 		return "", reconcile.Result{}, TerminalError(ConditionFailed(v1alpha1.ReasonUnknownError, "failed to create user JWT claims: %w", err))
 	}
 
-	got, err := r.CoreV1.Secrets(usr.Namespace).Get(ctx, usr.Spec.JWTSecretName, metav1.GetOptions{})
+	defer func() { observeJWTSigned("User", result, err) }()
+
+	got, err := r.getSecret(ctx, usr.Namespace, usr.Spec.JWTSecretName)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.V(1).Info("JWT secret not found, creating new secret")
@@ -280,7 +312,7 @@ func (r *UserReconciler) reconcileJWTSecret(ctx context.Context, usr *v1alpha1.U
 		return "", reconcile.Result{}, TemporaryError(ConditionUnknown(v1alpha1.ReasonUnknownError, "failed to get JWT secret: %w", err))
 	}
 
-	return r.ensureJWTSecretUpToDate(ctx, usr, wantClaims, got, nextJWT)
+	return r.ensureJWTSecretUpToDate(ctx, usr, wantClaims, got, nextJWT, force)
 }
 
 func (r *UserReconciler) getCAIfExists(ctx context.Context, acc *v1alpha1.Account) ([]byte, error) {
@@ -293,8 +325,9 @@ func (r *UserReconciler) getCAIfExists(ctx context.Context, acc *v1alpha1.Accoun
 
 	operatorRef := acc.Status.OperatorRef
 
-	opClient := r.AccountsV1Alpha1.Operators(operatorRef.Namespace)
-	op, err := opClient.Get(ctx, operatorRef.Name, metav1.GetOptions{})
+	op := new(v1alpha1.Operator)
+
+	err := r.Get(ctx, client.ObjectKey{Namespace: operatorRef.Namespace, Name: operatorRef.Name}, op)
 	if err != nil {
 		logger.Error(err, "failed to retrieve the operator for account")
 		return nil, err
@@ -304,7 +337,7 @@ func (r *UserReconciler) getCAIfExists(ctx context.Context, acc *v1alpha1.Accoun
 		return nil, errInternalNotFound
 	}
 
-	s, err := r.CoreV1.Secrets(op.Namespace).Get(ctx, op.Spec.TLSConfig.CAFile.Name, metav1.GetOptions{})
+	s, err := r.getSecret(ctx, op.Namespace, op.Spec.TLSConfig.CAFile.Name)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.Error(err, "could not find secret")
@@ -334,7 +367,7 @@ func (r *UserReconciler) reconcileUserCredentialSecret(ctx context.Context, usr
 		logger.V(1).Info("no TLS config found for account operator")
 	}
 
-	got, err := r.CoreV1.Secrets(usr.Namespace).Get(ctx, usr.Spec.CredentialsSecretName, metav1.GetOptions{})
+	got, err := r.getSecret(ctx, usr.Namespace, usr.Spec.CredentialsSecretName)
 	if err != nil {
 		if errors.IsNotFound(err) {
 			logger.Info("credentials secret not found, creating new secret")
@@ -449,12 +482,15 @@ func (r *UserReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	r.EventRecorder = mgr.GetEventRecorderFor("user-controller")
 
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&v1alpha1.User{}).
+		For(&v1alpha1.User{}, builder.WithPredicates(specChangedPredicate())).
+		WithOptions(r.controllerOptions()).
 		Owns(&v1.Secret{}).
 		Watches(&v1alpha1.Account{}, userAccountWatcher(mgr.GetLogger(), mgr.GetClient())).
 		Complete(r)
 }
 
+// userAccountWatcher will enqueue any Users which belong to the Account being watched, or which are
+// issued by it but have not resolved it yet.
 func userAccountWatcher(logger logr.Logger, c client.Client) handler.EventHandler {
 	return handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, obj client.Object) []reconcile.Request {
 		account, ok := obj.(*v1alpha1.Account)
@@ -464,24 +500,15 @@ func userAccountWatcher(logger logr.Logger, c client.Client) handler.EventHandle
 			return nil
 		}
 
-		var users v1alpha1.UserList
-
-		if err := c.List(ctx, &users, client.MatchingLabels{
-			resources.LabelAccountName: account.Name,
-		}); err != nil {
-			logger.Error(err, "failed to list users for account", "account", account.Name)
+		requests, err := listReferencing(ctx, c, &v1alpha1.UserList{}, account,
+			indexQuery{field: IndexStatusAccountRef},
+			indexQuery{field: IndexSpecIssuerRef, keep: issuedBy("Account")})
+		if err != nil {
+			logger.Error(err, "failed to list users for account", "account", client.ObjectKeyFromObject(account))
 
 			return nil
 		}
 
-		requests := make([]reconcile.Request, len(users.Items))
-
-		for i, user := range users.Items {
-			requests[i] = reconcile.Request{
-				NamespacedName: client.ObjectKeyFromObject(&user),
-			}
-		}
-
 		return requests
 	})
 }
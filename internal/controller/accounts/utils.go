@@ -1,9 +1,12 @@
 package controllers
 
 import (
+	"context"
 	"errors"
 
+	"k8s.io/client-go/util/retry"
 	"k8s.io/utils/strings/slices"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var errInternalNotFound = errors.New("resource not found")
@@ -23,3 +26,28 @@ func isEqualUnordered(s1 []string, s2 []string) bool {
 
 	return true
 }
+
+// patchStatusWithRetry applies mutate to obj and patches its status when mutate reports a change. The patch carries the
+// resourceVersion of obj so it cannot overwrite a concurrent write from a reconciler, on conflict obj is read again and
+// mutate re-applied to the latest version. It is used by the runnables which write status outside of a reconcile.
+func patchStatusWithRetry[T client.Object](ctx context.Context, c client.Client, obj T, mutate func(T) bool) error {
+	stale := false
+
+	return retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if stale {
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return err
+			}
+		}
+
+		stale = true
+
+		original := obj.DeepCopyObject().(client.Object)
+
+		if !mutate(obj) {
+			return nil
+		}
+
+		return c.Status().Patch(ctx, obj, client.MergeFromWithOptions(original, client.MergeFromWithOptimisticLock{}))
+	})
+}
@@ -0,0 +1,114 @@
+// Package tracing configures the OpenTelemetry tracer provider used by the manager.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	// ExporterNone disables tracing.
+	ExporterNone = ""
+	// ExporterOTLP exports spans to an OTLP gRPC collector.
+	ExporterOTLP = "otlp"
+	// ExporterStdout writes spans to stdout, intended for debugging.
+	ExporterStdout = "stdout"
+)
+
+// DefaultServiceName is the service.name resource attribute of exported spans.
+const DefaultServiceName = "nats-account-operator"
+
+// Options configures Setup.
+type Options struct {
+	// Exporter is one of ExporterNone, ExporterOTLP or ExporterStdout.
+	Exporter string
+
+	// Endpoint is the host:port of the OTLP collector. When empty the OTEL_EXPORTER_OTLP_ENDPOINT and
+	// OTEL_EXPORTER_OTLP_TRACES_ENDPOINT environment variables are used, defaulting to localhost:4317.
+	Endpoint string
+
+	// Insecure disables TLS when connecting to the OTLP collector.
+	Insecure bool
+
+	// SampleRatio is the fraction of traces started by the manager which are sampled, traces continued from a remote
+	// parent follow the sampling decision of the parent.
+	SampleRatio float64
+
+	// ServiceName defaults to DefaultServiceName.
+	ServiceName string
+}
+
+// Setup installs the global tracer provider and W3C trace context propagator described by opts. The returned function
+// flushes buffered spans and must be called before the process exits. With ExporterNone the global no-op provider is
+// left in place.
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	exporter, err := newExporter(ctx, opts)
+	if err != nil || exporter == nil {
+		return func(context.Context) error { return nil }, err
+	}
+
+	serviceName := opts.ServiceName
+	if serviceName == "" {
+		serviceName = DefaultServiceName
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(serviceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, opts Options) (sdktrace.SpanExporter, error) {
+	switch opts.Exporter {
+	case ExporterNone:
+		return nil, nil
+	case ExporterOTLP:
+		var clientOpts []otlptracegrpc.Option
+
+		if opts.Endpoint != "" {
+			clientOpts = append(clientOpts, otlptracegrpc.WithEndpoint(opts.Endpoint))
+		}
+
+		if opts.Insecure {
+			clientOpts = append(clientOpts, otlptracegrpc.WithInsecure())
+		}
+
+		exporter, err := otlptracegrpc.New(ctx, clientOpts...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create OTLP trace exporter: %w", err)
+		}
+
+		return exporter, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create stdout trace exporter: %w", err)
+		}
+
+		return exporter, nil
+	default:
+		return nil, fmt.Errorf("unsupported trace exporter %q, must be one of %q or %q", opts.Exporter, ExporterOTLP, ExporterStdout)
+	}
+}
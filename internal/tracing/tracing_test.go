@@ -0,0 +1,51 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestSetup(t *testing.T) {
+	tests := []struct {
+		name         string
+		opts         Options
+		wantErr      bool
+		wantProvider bool
+	}{
+		{name: "disabled"},
+		{name: "stdout", opts: Options{Exporter: ExporterStdout, SampleRatio: 1}, wantProvider: true},
+		{name: "otlp", opts: Options{Exporter: ExporterOTLP, Endpoint: "127.0.0.1:4317", Insecure: true, SampleRatio: 1}, wantProvider: true},
+		{name: "unknown exporter", opts: Options{Exporter: "zipkin"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prev := otel.GetTracerProvider()
+			t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			shutdown, err := Setup(ctx, tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Setup() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if _, ok := otel.GetTracerProvider().(*sdktrace.TracerProvider); ok != tt.wantProvider {
+				t.Errorf("global tracer provider = %T, want SDK provider %v", otel.GetTracerProvider(), tt.wantProvider)
+			}
+
+			if err := shutdown(ctx); err != nil {
+				t.Errorf("shutdown() error = %v", err)
+			}
+		})
+	}
+}
@@ -34,6 +34,13 @@ type ConditionsAccessor interface {
 	SetConditions(Conditions)
 }
 
+// ObservedGenerationAccessor is optionally implemented by a ConditionsAccessor, in which case every Condition set
+// through a ConditionManager records the observed generation.
+// +k8s:deepcopy-gen=false
+type ObservedGenerationAccessor interface {
+	GetObservedGeneration() int64
+}
+
 // ConditionAccessor is used to access a condition through it's type
 // +k8s:deepcopy-gen=false
 type ConditionAccessor interface {
@@ -189,7 +196,11 @@ func (r conditionsImpl) SetCondition(cond Condition) {
 	if r.accessor == nil {
 		return
 	}
+	if g, ok := r.accessor.(ObservedGenerationAccessor); ok {
+		cond.ObservedGeneration = g.GetObservedGeneration()
+	}
 	t := cond.Type
+	transitioned := true
 	var conditions Conditions
 	for _, c := range r.accessor.GetConditions() {
 		if c.Type != t {
@@ -200,9 +211,14 @@ func (r conditionsImpl) SetCondition(cond Condition) {
 			if reflect.DeepEqual(cond, c) {
 				return
 			}
+			// If only the ObservedGeneration changed, keep the LastTransitionTime.
+			c.ObservedGeneration = cond.ObservedGeneration
+			transitioned = !reflect.DeepEqual(cond, c)
 		}
 	}
-	cond.LastTransitionTime = VolatileTime{Inner: metav1.NewTime(time.Now())}
+	if transitioned {
+		cond.LastTransitionTime = VolatileTime{Inner: metav1.NewTime(time.Now())}
+	}
 	conditions = append(conditions, cond)
 	// Sorted for convenience of the consumer, i.e. kubectl.
 	sort.Slice(conditions, func(i, j int) bool { return conditions[i].Type < conditions[j].Type })
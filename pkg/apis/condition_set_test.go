@@ -0,0 +1,50 @@
+package apis
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type testStatus struct {
+	conditions         Conditions
+	observedGeneration int64
+}
+
+func (s *testStatus) GetConditions() Conditions           { return s.conditions }
+func (s *testStatus) SetConditions(conditions Conditions) { s.conditions = conditions }
+func (s *testStatus) GetObservedGeneration() int64        { return s.observedGeneration }
+
+func TestConditionManager_ObservedGeneration(t *testing.T) {
+	const (
+		foo ConditionType = "Foo"
+		bar ConditionType = "Bar"
+	)
+
+	status := &testStatus{observedGeneration: 1}
+	mgr := NewLivingConditionSet(foo, bar).Manage(status)
+
+	mgr.InitializeConditions()
+	mgr.MarkTrue(foo)
+
+	if got := mgr.GetCondition(foo).ObservedGeneration; got != 1 {
+		t.Fatalf("Foo observedGeneration = %d, want 1", got)
+	}
+
+	transitioned := metav1.Unix(0, 0)
+	status.conditions[0].LastTransitionTime = VolatileTime{Inner: transitioned}
+	status.conditions[1].LastTransitionTime = VolatileTime{Inner: transitioned}
+	status.observedGeneration = 2
+
+	// re-evaluating Foo without a change records the new generation but is not a transition, whereas Bar was not
+	// evaluated at the new generation.
+	mgr.MarkTrue(foo)
+
+	if got := mgr.GetCondition(foo); got.ObservedGeneration != 2 || !got.LastTransitionTime.Inner.Equal(&transitioned) {
+		t.Errorf("Foo = %+v, want observedGeneration 2 and an unchanged lastTransitionTime", got)
+	}
+
+	if got := mgr.GetCondition(bar).ObservedGeneration; got != 1 {
+		t.Errorf("Bar observedGeneration = %d, want 1", got)
+	}
+}
@@ -82,6 +82,10 @@ type Condition struct {
 	// A human readable message indicating details about the transition.
 	// +optional
 	Message string `json:"message,omitempty" description:"human-readable message indicating details about last transition"`
+
+	// ObservedGeneration is the metadata.generation of the resource when the condition was last evaluated.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty" description:"generation of the resource when the condition was last evaluated"`
 }
 
 // IsTrue is true if the condition is True
@@ -39,6 +39,7 @@ type AccountsV1alpha1Interface interface {
 	RESTClient() rest.Interface
 	AccountsGetter
 	OperatorsGetter
+	ServiceAccountPoliciesGetter
 	SigningKeysGetter
 	UsersGetter
 }
@@ -56,6 +57,10 @@ func (c *AccountsV1alpha1Client) Operators(namespace string) OperatorInterface {
 	return newOperators(c, namespace)
 }
 
+func (c *AccountsV1alpha1Client) ServiceAccountPolicies(namespace string) ServiceAccountPolicyInterface {
+	return newServiceAccountPolicies(c, namespace)
+}
+
 func (c *AccountsV1alpha1Client) SigningKeys(namespace string) SigningKeyInterface {
 	return newSigningKeys(c, namespace)
 }
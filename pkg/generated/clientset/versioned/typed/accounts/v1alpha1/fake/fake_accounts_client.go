@@ -45,6 +45,10 @@ func (c *FakeAccountsV1alpha1) Operators(namespace string) v1alpha1.OperatorInte
 	return &FakeOperators{c, namespace}
 }
 
+func (c *FakeAccountsV1alpha1) ServiceAccountPolicies(namespace string) v1alpha1.ServiceAccountPolicyInterface {
+	return &FakeServiceAccountPolicies{c, namespace}
+}
+
 func (c *FakeAccountsV1alpha1) SigningKeys(namespace string) v1alpha1.SigningKeyInterface {
 	return &FakeSigningKeys{c, namespace}
 }
@@ -0,0 +1,138 @@
+/*
+MIT License
+
+Copyright (c) 2024 Versori Ltd
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+*/
+
+// Code generated by client-gen-v0.29.3. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	v1alpha1 "github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeServiceAccountPolicies implements ServiceAccountPolicyInterface
+type FakeServiceAccountPolicies struct {
+	Fake *FakeAccountsV1alpha1
+	ns   string
+}
+
+var serviceaccountpoliciesResource = v1alpha1.SchemeGroupVersion.WithResource("serviceaccountpolicies")
+
+var serviceaccountpoliciesKind = v1alpha1.SchemeGroupVersion.WithKind("ServiceAccountPolicy")
+
+// Get takes name of the serviceAccountPolicy, and returns the corresponding serviceAccountPolicy object, and an error if there is any.
+func (c *FakeServiceAccountPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ServiceAccountPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(serviceaccountpoliciesResource, c.ns, name), &v1alpha1.ServiceAccountPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceAccountPolicy), err
+}
+
+// List takes label and field selectors, and returns the list of ServiceAccountPolicies that match those selectors.
+func (c *FakeServiceAccountPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ServiceAccountPolicyList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(serviceaccountpoliciesResource, serviceaccountpoliciesKind, c.ns, opts), &v1alpha1.ServiceAccountPolicyList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &v1alpha1.ServiceAccountPolicyList{ListMeta: obj.(*v1alpha1.ServiceAccountPolicyList).ListMeta}
+	for _, item := range obj.(*v1alpha1.ServiceAccountPolicyList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested serviceAccountPolicies.
+func (c *FakeServiceAccountPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(serviceaccountpoliciesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a serviceAccountPolicy and creates it.  Returns the server's representation of the serviceAccountPolicy, and an error, if there is any.
+func (c *FakeServiceAccountPolicies) Create(ctx context.Context, serviceAccountPolicy *v1alpha1.ServiceAccountPolicy, opts v1.CreateOptions) (result *v1alpha1.ServiceAccountPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(serviceaccountpoliciesResource, c.ns, serviceAccountPolicy), &v1alpha1.ServiceAccountPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceAccountPolicy), err
+}
+
+// Update takes the representation of a serviceAccountPolicy and updates it. Returns the server's representation of the serviceAccountPolicy, and an error, if there is any.
+func (c *FakeServiceAccountPolicies) Update(ctx context.Context, serviceAccountPolicy *v1alpha1.ServiceAccountPolicy, opts v1.UpdateOptions) (result *v1alpha1.ServiceAccountPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(serviceaccountpoliciesResource, c.ns, serviceAccountPolicy), &v1alpha1.ServiceAccountPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceAccountPolicy), err
+}
+
+// Delete takes name of the serviceAccountPolicy and deletes it. Returns an error if one occurs.
+func (c *FakeServiceAccountPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteActionWithOptions(serviceaccountpoliciesResource, c.ns, name, opts), &v1alpha1.ServiceAccountPolicy{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeServiceAccountPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(serviceaccountpoliciesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &v1alpha1.ServiceAccountPolicyList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched serviceAccountPolicy.
+func (c *FakeServiceAccountPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ServiceAccountPolicy, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(serviceaccountpoliciesResource, c.ns, name, pt, data, subresources...), &v1alpha1.ServiceAccountPolicy{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*v1alpha1.ServiceAccountPolicy), err
+}
@@ -31,6 +31,8 @@ type AccountExpansion interface{}
 
 type OperatorExpansion interface{}
 
+type ServiceAccountPolicyExpansion interface{}
+
 type SigningKeyExpansion interface{}
 
 type UserExpansion interface{}
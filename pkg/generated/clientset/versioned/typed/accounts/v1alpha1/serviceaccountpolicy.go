@@ -0,0 +1,187 @@
+/*
+MIT License
+
+Copyright (c) 2024 Versori Ltd
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+*/
+
+// Code generated by client-gen-v0.29.3. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	"context"
+	"time"
+
+	v1alpha1 "github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	scheme "github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned/scheme"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// ServiceAccountPoliciesGetter has a method to return a ServiceAccountPolicyInterface.
+// A group's client should implement this interface.
+type ServiceAccountPoliciesGetter interface {
+	ServiceAccountPolicies(namespace string) ServiceAccountPolicyInterface
+}
+
+// ServiceAccountPolicyInterface has methods to work with ServiceAccountPolicy resources.
+type ServiceAccountPolicyInterface interface {
+	Create(ctx context.Context, serviceAccountPolicy *v1alpha1.ServiceAccountPolicy, opts v1.CreateOptions) (*v1alpha1.ServiceAccountPolicy, error)
+	Update(ctx context.Context, serviceAccountPolicy *v1alpha1.ServiceAccountPolicy, opts v1.UpdateOptions) (*v1alpha1.ServiceAccountPolicy, error)
+	Delete(ctx context.Context, name string, opts v1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error
+	Get(ctx context.Context, name string, opts v1.GetOptions) (*v1alpha1.ServiceAccountPolicy, error)
+	List(ctx context.Context, opts v1.ListOptions) (*v1alpha1.ServiceAccountPolicyList, error)
+	Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ServiceAccountPolicy, err error)
+	ServiceAccountPolicyExpansion
+}
+
+// serviceAccountPolicies implements ServiceAccountPolicyInterface
+type serviceAccountPolicies struct {
+	client rest.Interface
+	ns     string
+}
+
+// newServiceAccountPolicies returns a ServiceAccountPolicies
+func newServiceAccountPolicies(c *AccountsV1alpha1Client, namespace string) *serviceAccountPolicies {
+	return &serviceAccountPolicies{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the serviceAccountPolicy, and returns the corresponding serviceAccountPolicy object, and an error if there is any.
+func (c *serviceAccountPolicies) Get(ctx context.Context, name string, options v1.GetOptions) (result *v1alpha1.ServiceAccountPolicy, err error) {
+	result = &v1alpha1.ServiceAccountPolicy{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("serviceaccountpolicies").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of ServiceAccountPolicies that match those selectors.
+func (c *serviceAccountPolicies) List(ctx context.Context, opts v1.ListOptions) (result *v1alpha1.ServiceAccountPolicyList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1alpha1.ServiceAccountPolicyList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("serviceaccountpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested serviceAccountPolicies.
+func (c *serviceAccountPolicies) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("serviceaccountpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a serviceAccountPolicy and creates it.  Returns the server's representation of the serviceAccountPolicy, and an error, if there is any.
+func (c *serviceAccountPolicies) Create(ctx context.Context, serviceAccountPolicy *v1alpha1.ServiceAccountPolicy, opts v1.CreateOptions) (result *v1alpha1.ServiceAccountPolicy, err error) {
+	result = &v1alpha1.ServiceAccountPolicy{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("serviceaccountpolicies").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(serviceAccountPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a serviceAccountPolicy and updates it. Returns the server's representation of the serviceAccountPolicy, and an error, if there is any.
+func (c *serviceAccountPolicies) Update(ctx context.Context, serviceAccountPolicy *v1alpha1.ServiceAccountPolicy, opts v1.UpdateOptions) (result *v1alpha1.ServiceAccountPolicy, err error) {
+	result = &v1alpha1.ServiceAccountPolicy{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("serviceaccountpolicies").
+		Name(serviceAccountPolicy.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(serviceAccountPolicy).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the serviceAccountPolicy and deletes it. Returns an error if one occurs.
+func (c *serviceAccountPolicies) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("serviceaccountpolicies").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *serviceAccountPolicies) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("serviceaccountpolicies").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched serviceAccountPolicy.
+func (c *serviceAccountPolicies) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *v1alpha1.ServiceAccountPolicy, err error) {
+	result = &v1alpha1.ServiceAccountPolicy{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("serviceaccountpolicies").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -37,6 +37,8 @@ type Interface interface {
 	Accounts() AccountInformer
 	// Operators returns a OperatorInformer.
 	Operators() OperatorInformer
+	// ServiceAccountPolicies returns a ServiceAccountPolicyInformer.
+	ServiceAccountPolicies() ServiceAccountPolicyInformer
 	// SigningKeys returns a SigningKeyInformer.
 	SigningKeys() SigningKeyInformer
 	// Users returns a UserInformer.
@@ -64,6 +66,11 @@ func (v *version) Operators() OperatorInformer {
 	return &operatorInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// ServiceAccountPolicies returns a ServiceAccountPolicyInformer.
+func (v *version) ServiceAccountPolicies() ServiceAccountPolicyInformer {
+	return &serviceAccountPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // SigningKeys returns a SigningKeyInformer.
 func (v *version) SigningKeys() SigningKeyInformer {
 	return &signingKeyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
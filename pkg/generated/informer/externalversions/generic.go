@@ -66,6 +66,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Accounts().V1alpha1().Accounts().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("operators"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Accounts().V1alpha1().Operators().Informer()}, nil
+	case v1alpha1.SchemeGroupVersion.WithResource("serviceaccountpolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Accounts().V1alpha1().ServiceAccountPolicies().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("signingkeys"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Accounts().V1alpha1().SigningKeys().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("users"):
@@ -43,6 +43,14 @@ type OperatorListerExpansion interface{}
 // OperatorNamespaceLister.
 type OperatorNamespaceListerExpansion interface{}
 
+// ServiceAccountPolicyListerExpansion allows custom methods to be added to
+// ServiceAccountPolicyLister.
+type ServiceAccountPolicyListerExpansion interface{}
+
+// ServiceAccountPolicyNamespaceListerExpansion allows custom methods to be added to
+// ServiceAccountPolicyNamespaceLister.
+type ServiceAccountPolicyNamespaceListerExpansion interface{}
+
 // SigningKeyListerExpansion allows custom methods to be added to
 // SigningKeyLister.
 type SigningKeyListerExpansion interface{}
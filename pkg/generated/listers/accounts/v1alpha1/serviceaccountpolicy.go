@@ -0,0 +1,108 @@
+/*
+MIT License
+
+Copyright (c) 2024 Versori Ltd
+
+Permission is hereby granted, free of charge, to any person obtaining a copy
+of this software and associated documentation files (the "Software"), to deal
+in the Software without restriction, including without limitation the rights
+to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+copies of the Software, and to permit persons to whom the Software is
+furnished to do so, subject to the following conditions:
+
+The above copyright notice and this permission notice shall be included in all
+copies or substantial portions of the Software.
+
+THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+SOFTWARE.
+
+*/
+
+// Code generated by lister-gen-v0.29.3. DO NOT EDIT.
+
+package v1alpha1
+
+import (
+	v1alpha1 "github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// ServiceAccountPolicyLister helps list ServiceAccountPolicies.
+// All objects returned here must be treated as read-only.
+type ServiceAccountPolicyLister interface {
+	// List lists all ServiceAccountPolicies in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.ServiceAccountPolicy, err error)
+	// ServiceAccountPolicies returns an object that can list and get ServiceAccountPolicies.
+	ServiceAccountPolicies(namespace string) ServiceAccountPolicyNamespaceLister
+	ServiceAccountPolicyListerExpansion
+}
+
+// serviceAccountPolicyLister implements the ServiceAccountPolicyLister interface.
+type serviceAccountPolicyLister struct {
+	indexer cache.Indexer
+}
+
+// NewServiceAccountPolicyLister returns a new ServiceAccountPolicyLister.
+func NewServiceAccountPolicyLister(indexer cache.Indexer) ServiceAccountPolicyLister {
+	return &serviceAccountPolicyLister{indexer: indexer}
+}
+
+// List lists all ServiceAccountPolicies in the indexer.
+func (s *serviceAccountPolicyLister) List(selector labels.Selector) (ret []*v1alpha1.ServiceAccountPolicy, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ServiceAccountPolicy))
+	})
+	return ret, err
+}
+
+// ServiceAccountPolicies returns an object that can list and get ServiceAccountPolicies.
+func (s *serviceAccountPolicyLister) ServiceAccountPolicies(namespace string) ServiceAccountPolicyNamespaceLister {
+	return serviceAccountPolicyNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// ServiceAccountPolicyNamespaceLister helps list and get ServiceAccountPolicies.
+// All objects returned here must be treated as read-only.
+type ServiceAccountPolicyNamespaceLister interface {
+	// List lists all ServiceAccountPolicies in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1alpha1.ServiceAccountPolicy, err error)
+	// Get retrieves the ServiceAccountPolicy from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1alpha1.ServiceAccountPolicy, error)
+	ServiceAccountPolicyNamespaceListerExpansion
+}
+
+// serviceAccountPolicyNamespaceLister implements the ServiceAccountPolicyNamespaceLister
+// interface.
+type serviceAccountPolicyNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all ServiceAccountPolicies in the indexer for a given namespace.
+func (s serviceAccountPolicyNamespaceLister) List(selector labels.Selector) (ret []*v1alpha1.ServiceAccountPolicy, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1alpha1.ServiceAccountPolicy))
+	})
+	return ret, err
+}
+
+// Get retrieves the ServiceAccountPolicy from the indexer for a given namespace and name.
+func (s serviceAccountPolicyNamespaceLister) Get(name string) (*v1alpha1.ServiceAccountPolicy, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1alpha1.Resource("serviceaccountpolicy"), name)
+	}
+	return obj.(*v1alpha1.ServiceAccountPolicy), nil
+}
@@ -0,0 +1,163 @@
+package kubectlnats
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/nats-io/jwt/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+)
+
+// Claims writes the decoded claims of the JWT for an Operator, Account or User as indented JSON.
+func (p *Plugin) Claims(ctx context.Context, w io.Writer, kind Kind, namespace, name string) error {
+	obj, err := p.get(ctx, kind, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	var secretName string
+
+	switch o := obj.(type) {
+	case *v1alpha1.Operator:
+		secretName = o.Spec.JWTSecretName
+	case *v1alpha1.Account:
+		secretName = o.Spec.JWTSecretName
+	case *v1alpha1.User:
+		secretName = o.Spec.JWTSecretName
+	default:
+		return fmt.Errorf("%s does not have a JWT", kind)
+	}
+
+	token, err := p.secretValue(ctx, namespace, secretName, v1alpha1.NatsSecretJWTKey)
+	if err != nil {
+		return err
+	}
+
+	var claims jwt.Claims
+
+	switch kind {
+	case KindOperator:
+		claims, err = jwt.DecodeOperatorClaims(string(token))
+	case KindAccount:
+		claims, err = jwt.DecodeAccountClaims(string(token))
+	default:
+		claims, err = jwt.DecodeUserClaims(string(token))
+	}
+
+	if err != nil {
+		return fmt.Errorf("failed to decode JWT in secret %s: %w", secretName, err)
+	}
+
+	data, err := json.MarshalIndent(claims, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintln(w, string(data))
+
+	return err
+}
+
+// Creds returns the contents of the creds file for a User.
+func (p *Plugin) Creds(ctx context.Context, namespace, name string) ([]byte, error) {
+	obj, err := p.get(ctx, KindUser, namespace, name)
+	if err != nil {
+		return nil, err
+	}
+
+	user := obj.(*v1alpha1.User)
+
+	if !readyCondition(user).IsTrue() {
+		return nil, fmt.Errorf("user %s/%s is not Ready, run 'kubectl nats status user %s' for details", namespace, name, name)
+	}
+
+	return p.secretValue(ctx, namespace, user.Spec.CredentialsSecretName, v1alpha1.NatsSecretCredsKey)
+}
+
+// Status writes a summary of why a resource is or is not Ready. The unhappy conditions are listed first, since they
+// are the cause of the top level condition being False or Unknown.
+func (p *Plugin) Status(ctx context.Context, w io.Writer, kind Kind, namespace, name string) error {
+	obj, err := p.get(ctx, kind, namespace, name)
+	if err != nil {
+		return err
+	}
+
+	ready := readyCondition(obj)
+
+	switch {
+	case ready == nil:
+		fmt.Fprintf(w, "%s %s/%s has not been reconciled yet\n", kind, namespace, name)
+
+		return nil
+	case ready.IsTrue():
+		fmt.Fprintf(w, "%s %s/%s is Ready\n", kind, namespace, name)
+	default:
+		fmt.Fprintf(w, "%s %s/%s is not Ready: %s\n", kind, namespace, name, describeCondition(ready))
+	}
+
+	conditions := make(apis.Conditions, 0, len(obj.GetStatus().Conditions))
+
+	for _, cond := range obj.GetStatus().Conditions {
+		if cond.Type != ready.Type {
+			conditions = append(conditions, cond)
+		}
+	}
+
+	sort.SliceStable(conditions, func(i, j int) bool {
+		return !conditions[i].IsTrue() && conditions[j].IsTrue()
+	})
+
+	fmt.Fprintln(w)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 3, ' ', 0)
+
+	fmt.Fprintln(tw, "CONDITION\tSTATUS\tREASON\tSINCE\tMESSAGE")
+
+	for _, cond := range conditions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", cond.Type, cond.Status, cond.Reason, since(cond), cond.Message)
+	}
+
+	return tw.Flush()
+}
+
+func describeCondition(cond *apis.Condition) string {
+	switch {
+	case cond.Reason != "" && cond.Message != "":
+		return cond.Reason + ": " + cond.Message
+	case cond.Reason != "":
+		return cond.Reason
+	case cond.Message != "":
+		return cond.Message
+	default:
+		return string(cond.Status)
+	}
+}
+
+func since(cond apis.Condition) string {
+	if cond.LastTransitionTime.Inner.IsZero() {
+		return ""
+	}
+
+	return cond.LastTransitionTime.Inner.UTC().Format("2006-01-02T15:04:05Z")
+}
+
+func (p *Plugin) secretValue(ctx context.Context, namespace, name, key string) ([]byte, error) {
+	secret, err := p.core.Secrets(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get secret %s/%s: %w", namespace, name, err)
+	}
+
+	value, ok := secret.Data[key]
+	if !ok {
+		return nil, fmt.Errorf("secret %s/%s is missing key %s", namespace, name, key)
+	}
+
+	return value, nil
+}
@@ -0,0 +1,104 @@
+// Package kubectlnats implements the commands of the kubectl-nats plugin, which inspects the Operators, Accounts,
+// Users and SigningKeys managed by the operator and fetches their credentials without going through the raw Secrets.
+package kubectlnats
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+	accountsv1alpha1 "github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned/typed/accounts/v1alpha1"
+)
+
+// Kind identifies the resources the plugin can inspect.
+type Kind string
+
+const (
+	KindOperator   Kind = "Operator"
+	KindAccount    Kind = "Account"
+	KindUser       Kind = "User"
+	KindSigningKey Kind = "SigningKey"
+)
+
+// ParseKind accepts the kind, plural or any short name of a resource, case-insensitively.
+func ParseKind(s string) (Kind, error) {
+	switch strings.ToLower(s) {
+	case "operator", "operators", "nop", "natsoperator":
+		return KindOperator, nil
+	case "account", "accounts", "nacc", "natsaccount":
+		return KindAccount, nil
+	case "user", "users", "nuser", "natsuser":
+		return KindUser, nil
+	case "signingkey", "signingkeys", "nsk", "natssigningkey":
+		return KindSigningKey, nil
+	default:
+		return "", fmt.Errorf("unknown kind %q, must be one of operator, account, user or signingkey", s)
+	}
+}
+
+// resource is implemented by every kind the plugin can inspect.
+type resource interface {
+	metav1.Object
+	v1alpha1.StatusAccessor
+	v1alpha1.ConditionSetAccessor
+}
+
+// Plugin reads resources through the generated accounts clientset, and their Secrets through the core clientset.
+type Plugin struct {
+	accounts accountsv1alpha1.AccountsV1alpha1Interface
+	core     corev1.CoreV1Interface
+}
+
+// New returns a Plugin using the provided clients.
+func New(accounts accountsv1alpha1.AccountsV1alpha1Interface, core corev1.CoreV1Interface) *Plugin {
+	return &Plugin{
+		accounts: accounts,
+		core:     core,
+	}
+}
+
+func (p *Plugin) get(ctx context.Context, kind Kind, namespace, name string) (resource, error) {
+	var (
+		obj resource
+		err error
+	)
+
+	switch kind {
+	case KindOperator:
+		obj, err = p.accounts.Operators(namespace).Get(ctx, name, metav1.GetOptions{})
+	case KindAccount:
+		obj, err = p.accounts.Accounts(namespace).Get(ctx, name, metav1.GetOptions{})
+	case KindUser:
+		obj, err = p.accounts.Users(namespace).Get(ctx, name, metav1.GetOptions{})
+	case KindSigningKey:
+		obj, err = p.accounts.SigningKeys(namespace).Get(ctx, name, metav1.GetOptions{})
+	default:
+		return nil, fmt.Errorf("unknown kind %q", kind)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s/%s: %w", kind, namespace, name, err)
+	}
+
+	return obj, nil
+}
+
+// readyCondition returns the top level condition of obj, or nil if it has not been reconciled yet.
+func readyCondition(obj resource) *apis.Condition {
+	return obj.GetConditionSet().Manage(obj.GetStatus()).GetTopLevelCondition()
+}
+
+// readyStatus formats the status of the Ready condition, as shown by the printer columns of each CRD.
+func readyStatus(obj resource) string {
+	cond := readyCondition(obj)
+	if cond == nil {
+		return "Unknown"
+	}
+
+	return string(cond.Status)
+}
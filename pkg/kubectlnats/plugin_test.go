@@ -0,0 +1,233 @@
+package kubectlnats
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+	accountsfake "github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned/fake"
+)
+
+func meta(name string) metav1.ObjectMeta {
+	return metav1.ObjectMeta{Name: name, Namespace: "nats"}
+}
+
+func status(ready v1.ConditionStatus, conditions ...apis.Condition) v1alpha1.Status {
+	return v1alpha1.Status{
+		Conditions: append(apis.Conditions{{Type: apis.ConditionReady, Status: ready}}, conditions...),
+	}
+}
+
+func newTestPlugin(t *testing.T) *Plugin {
+	accountKP, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountPub, err := accountKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ac := jwt.NewAccountClaims(accountPub)
+	ac.Name = "orders"
+
+	token, err := ac.Encode(accountKP)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	notReady := status(v1.ConditionFalse,
+		apis.Condition{Type: v1alpha1.AccountConditionJWTPushed, Status: v1.ConditionFalse, Reason: "PushFailed", Message: "nats: timeout"},
+		apis.Condition{Type: v1alpha1.KeyPairableConditionSeedSecretReady, Status: v1.ConditionTrue},
+	)
+	notReady.Conditions[0].Reason = "PushFailed"
+
+	accounts := accountsfake.NewSimpleClientset(
+		&v1alpha1.Operator{
+			ObjectMeta: meta("op"),
+			Status:     v1alpha1.OperatorStatus{Status: status(v1.ConditionTrue)},
+		},
+		&v1alpha1.Account{
+			ObjectMeta: meta("orders"),
+			Spec:       v1alpha1.AccountSpec{JWTSecretName: "orders-jwt"},
+			Status: v1alpha1.AccountStatus{
+				Status:      notReady,
+				OperatorRef: &v1alpha1.InferredObjectReference{Namespace: "nats", Name: "op"},
+			},
+		},
+		&v1alpha1.Account{
+			ObjectMeta: meta("billing"),
+			Status: v1alpha1.AccountStatus{
+				Status:      status(v1.ConditionTrue),
+				OperatorRef: &v1alpha1.InferredObjectReference{Namespace: "nats", Name: "op"},
+			},
+		},
+		&v1alpha1.User{
+			ObjectMeta: meta("app"),
+			Spec:       v1alpha1.UserSpec{CredentialsSecretName: "app-creds"},
+			Status: v1alpha1.UserStatus{
+				Status:     status(v1.ConditionTrue),
+				AccountRef: &v1alpha1.InferredObjectReference{Namespace: "nats", Name: "orders"},
+			},
+		},
+		&v1alpha1.User{
+			ObjectMeta: meta("pending"),
+		},
+	).AccountsV1alpha1()
+
+	core := k8sfake.NewSimpleClientset(
+		&v1.Secret{ObjectMeta: meta("orders-jwt"), Data: map[string][]byte{v1alpha1.NatsSecretJWTKey: []byte(token)}},
+		&v1.Secret{ObjectMeta: meta("app-creds"), Data: map[string][]byte{v1alpha1.NatsSecretCredsKey: []byte("creds")}},
+	).CoreV1()
+
+	return New(accounts, core)
+}
+
+func TestPlugin_Tree(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := newTestPlugin(t).Tree(context.Background(), &buf, "nats"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var names []string
+
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n")[1:] {
+		names = append(names, strings.Fields(line)[1:]...)
+	}
+
+	got := strings.Join(names, " ")
+	want := "Operator/op True " +
+		"├── Account/billing True " +
+		"└── Account/orders False PushFailed " +
+		"└── User/app True " +
+		"User/pending Unknown"
+
+	if got != want {
+		t.Errorf("Tree() =\n%s\nwant fields %q, got %q", buf.String(), want, got)
+	}
+}
+
+func TestPlugin_Status(t *testing.T) {
+	tests := []struct {
+		name string
+		kind Kind
+		obj  string
+		want []string
+	}{
+		{
+			name: "not ready",
+			kind: KindAccount,
+			obj:  "orders",
+			want: []string{"Account nats/orders is not Ready: PushFailed", "JWTPushed", "nats: timeout"},
+		},
+		{
+			name: "ready",
+			kind: KindUser,
+			obj:  "app",
+			want: []string{"User nats/app is Ready"},
+		},
+		{
+			name: "not reconciled",
+			kind: KindUser,
+			obj:  "pending",
+			want: []string{"User nats/pending has not been reconciled yet"},
+		},
+	}
+
+	p := newTestPlugin(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			if err := p.Status(context.Background(), &buf, tt.kind, "nats", tt.obj); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("Status() = %q, want it to contain %q", buf.String(), want)
+				}
+			}
+		})
+	}
+
+	// unhappy conditions are listed before happy ones
+	var buf bytes.Buffer
+
+	if err := p.Status(context.Background(), &buf, KindAccount, "nats", "orders"); err != nil {
+		t.Fatal(err)
+	}
+
+	if strings.Index(buf.String(), "JWTPushed") > strings.Index(buf.String(), "SeedSecretReady") {
+		t.Errorf("expected JWTPushed before SeedSecretReady, got %s", buf.String())
+	}
+}
+
+func TestPlugin_Claims(t *testing.T) {
+	var buf bytes.Buffer
+
+	if err := newTestPlugin(t).Claims(context.Background(), &buf, KindAccount, "nats", "orders"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var claims jwt.AccountClaims
+
+	if err := json.Unmarshal(buf.Bytes(), &claims); err != nil {
+		t.Fatalf("Claims() did not write JSON: %v", err)
+	}
+
+	if claims.Name != "orders" {
+		t.Errorf("Claims() name = %q, want orders", claims.Name)
+	}
+}
+
+func TestPlugin_Creds(t *testing.T) {
+	p := newTestPlugin(t)
+
+	creds, err := p.Creds(context.Background(), "nats", "app")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if string(creds) != "creds" {
+		t.Errorf("Creds() = %q, want creds", creds)
+	}
+
+	if _, err := p.Creds(context.Background(), "nats", "pending"); err == nil {
+		t.Errorf("expected error fetching creds of a User which is not Ready")
+	}
+}
+
+func TestParseKind(t *testing.T) {
+	tests := map[string]Kind{
+		"operator":    KindOperator,
+		"nop":         KindOperator,
+		"Accounts":    KindAccount,
+		"nuser":       KindUser,
+		"signingkeys": KindSigningKey,
+	}
+
+	for in, want := range tests {
+		got, err := ParseKind(in)
+		if err != nil || got != want {
+			t.Errorf("ParseKind(%q) = %q, %v, want %q", in, got, err, want)
+		}
+	}
+
+	if _, err := ParseKind("secret"); err == nil {
+		t.Errorf("expected error parsing unknown kind")
+	}
+}
@@ -0,0 +1,138 @@
+package kubectlnats
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// treeNode is a resource and the resources it issued.
+type treeNode struct {
+	kind     Kind
+	obj      resource
+	children []*treeNode
+}
+
+// Tree writes the Operator → Account → User hierarchy in namespace, or all namespaces when empty, along with the Ready
+// status of each resource. Accounts are placed under the Operator in their status.operatorRef and Users under the
+// Account in their status.accountRef, so resources which have not been resolved yet are listed at the top level.
+func (p *Plugin) Tree(ctx context.Context, w io.Writer, namespace string) error {
+	operators, err := p.accounts.Operators(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list operators: %w", err)
+	}
+
+	accounts, err := p.accounts.Accounts(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	users, err := p.accounts.Users(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	var roots []*treeNode
+
+	operatorNodes := make(map[string]*treeNode, len(operators.Items))
+
+	for i := range operators.Items {
+		operator := &operators.Items[i]
+
+		node := &treeNode{kind: KindOperator, obj: operator}
+		operatorNodes[key(operator.Namespace, operator.Name)] = node
+		roots = append(roots, node)
+	}
+
+	accountNodes := make(map[string]*treeNode, len(accounts.Items))
+
+	for i := range accounts.Items {
+		account := &accounts.Items[i]
+
+		node := &treeNode{kind: KindAccount, obj: account}
+		accountNodes[key(account.Namespace, account.Name)] = node
+
+		if parent, ok := operatorNodes[refKey(account.Status.OperatorRef)]; ok {
+			parent.children = append(parent.children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	for i := range users.Items {
+		user := &users.Items[i]
+
+		node := &treeNode{kind: KindUser, obj: user}
+
+		if parent, ok := accountNodes[refKey(user.Status.AccountRef)]; ok {
+			parent.children = append(parent.children, node)
+		} else {
+			roots = append(roots, node)
+		}
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 3, ' ', 0)
+
+	fmt.Fprintln(tw, "NAMESPACE\tNAME\tREADY\tREASON")
+
+	sortNodes(roots)
+
+	for _, node := range roots {
+		writeNode(tw, node, "", "")
+	}
+
+	return tw.Flush()
+}
+
+func writeNode(w io.Writer, node *treeNode, prefix, childPrefix string) {
+	var reason string
+
+	if cond := readyCondition(node.obj); cond != nil && !cond.IsTrue() {
+		reason = cond.Reason
+	}
+
+	fmt.Fprintf(w, "%s\t%s%s/%s\t%s\t%s\n", node.obj.GetNamespace(), prefix, node.kind, node.obj.GetName(), readyStatus(node.obj), reason)
+
+	sortNodes(node.children)
+
+	for i, child := range node.children {
+		if i == len(node.children)-1 {
+			writeNode(w, child, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			writeNode(w, child, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+// sortNodes orders nodes by kind, so unresolved Accounts and Users follow the Operators, then by namespace and name.
+func sortNodes(nodes []*treeNode) {
+	order := map[Kind]int{KindOperator: 0, KindAccount: 1, KindUser: 2}
+
+	sort.SliceStable(nodes, func(i, j int) bool {
+		a, b := nodes[i], nodes[j]
+
+		if a.kind != b.kind {
+			return order[a.kind] < order[b.kind]
+		}
+
+		return key(a.obj.GetNamespace(), a.obj.GetName()) < key(b.obj.GetNamespace(), b.obj.GetName())
+	})
+}
+
+func key(namespace, name string) string {
+	return namespace + "/" + name
+}
+
+func refKey(ref *v1alpha1.InferredObjectReference) string {
+	if ref == nil {
+		return ""
+	}
+
+	return key(ref.Namespace, ref.Name)
+}
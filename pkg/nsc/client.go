@@ -3,6 +3,7 @@ package nsc
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 
 	"github.com/nats-io/jwt/v2"
@@ -16,24 +17,86 @@ import (
 const (
 	RequestSubjectClaimsUpdate = "$SYS.REQ.CLAIMS.UPDATE"
 	RequestSubjectClaimsDelete = "$SYS.REQ.CLAIMS.DELETE"
+	RequestSubjectClaimsList   = "$SYS.REQ.CLAIMS.LIST"
+
+	// RequestSubjectClaimsLookupFormat is formatted with the public key of the account to look up.
+	RequestSubjectClaimsLookupFormat = "$SYS.REQ.ACCOUNT.%s.CLAIMS.LOOKUP"
 )
 
+// ErrAccountNotFound is returned by AccountPublisher.Lookup when the account server does not have a JWT for the
+// account.
+var ErrAccountNotFound = errors.New("account not found")
+
+// AccountPublisher publishes Account JWTs to the account server of an Operator.
+type AccountPublisher interface {
+	// Push stores the account JWT, replacing any existing JWT for the same account.
+	Push(ctx context.Context, jwt string) error
+	// Delete removes the JWT of the account with the public key subject.
+	Delete(ctx context.Context, subject string) error
+	// Lookup returns the JWT of the account with the public key subject, or ErrAccountNotFound.
+	Lookup(ctx context.Context, subject string) (string, error)
+	// List returns the public keys of every account stored by the account server.
+	List(ctx context.Context) ([]string, error)
+	// Usage returns the usage of the account with the public key subject, aggregated across every server which
+	// responds.
+	Usage(ctx context.Context, subject string) (*AccountUsage, error)
+	// ConnectionsByUser returns the number of open client connections of each user of the account with the public key
+	// subject, summed across every server which responds.
+	ConnectionsByUser(ctx context.Context, subject string) (map[string]int, error)
+	// SubscribeConnectionEvents calls handler for every client connect and disconnect event until the publisher is
+	// closed.
+	SubscribeConnectionEvents(handler func(ConnectionEvent)) error
+	// ServerInfo returns the name, version and resolver type of the server.
+	ServerInfo(ctx context.Context) (*ServerInfo, error)
+	// Close releases the connection to the account server.
+	Close()
+}
+
+// AccountServer connects to the account server of an Operator. Pushes are authenticated by a temporary user of the
+// system account, and deletes are signed by the operator key pair, which may be nil if the publisher is not used to
+// delete accounts.
+type AccountServer interface {
+	Connect(url string, operator nkeys.KeyPair, systemAccountSeed []byte, opts ...nats.Option) (AccountPublisher, error)
+}
+
+// NATSAccountServer is an AccountServer for a nats-server using the full or cache resolver, which accepts account
+// JWTs over the $SYS.REQ.CLAIMS subjects.
+type NATSAccountServer struct{}
+
+var _ AccountServer = NATSAccountServer{}
+
+func (NATSAccountServer) Connect(url string, operator nkeys.KeyPair, systemAccountSeed []byte, opts ...nats.Option) (AccountPublisher, error) {
+	client, err := Connect(url, operator, systemAccountSeed, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return client, nil
+}
+
+var _ AccountPublisher = (*Client)(nil)
+
 type Client struct {
 	conn *nats.Conn
 
 	operator        nkeys.KeyPair
 	operatorSubject string
+	systemAccount   string
 }
 
 func Connect(url string, operator nkeys.KeyPair, systemAccountSeed []byte, opts ...nats.Option) (*Client, error) {
-	ujwt, useed, err := makeTemporaryUser(systemAccountSeed)
+	ujwt, useed, sysPubkey, err := makeTemporaryUser(systemAccountSeed)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temporary system account user: %w", err)
 	}
 
-	operatorPubkey, err := operator.PublicKey()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get operator public key: %w", err)
+	var operatorPubkey string
+
+	if operator != nil {
+		operatorPubkey, err = operator.PublicKey()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get operator public key: %w", err)
+		}
 	}
 
 	options := append(make([]nats.Option, 0, len(opts)+2), opts...)
@@ -51,6 +114,7 @@ func Connect(url string, operator nkeys.KeyPair, systemAccountSeed []byte, opts
 		conn:            conn,
 		operator:        operator,
 		operatorSubject: operatorPubkey,
+		systemAccount:   sysPubkey,
 	}, nil
 }
 
@@ -70,6 +134,10 @@ func (c *Client) Push(ctx context.Context, jwt string) error {
 }
 
 func (c *Client) Delete(ctx context.Context, subject string) error {
+	if c.operator == nil {
+		return errors.New("nats delete requires the operator key pair")
+	}
+
 	claims := jwt.NewGenericClaims(c.operatorSubject)
 	claims.Data["accounts"] = []string{subject}
 
@@ -92,8 +160,41 @@ func (c *Client) Delete(ctx context.Context, subject string) error {
 	return nil
 }
 
+func (c *Client) Lookup(ctx context.Context, subject string) (string, error) {
+	resp, err := c.request(ctx, subjectTemplate(RequestSubjectClaimsLookupFormat),
+		fmt.Sprintf(RequestSubjectClaimsLookupFormat, subject), nil)
+	if err != nil {
+		return "", err
+	}
+
+	// the full resolver replies with an empty message when it does not have the account
+	if len(resp.Data) == 0 {
+		return "", ErrAccountNotFound
+	}
+
+	return string(resp.Data), nil
+}
+
+func (c *Client) List(ctx context.Context) ([]string, error) {
+	resp, err := c.request(ctx, RequestSubjectClaimsList, RequestSubjectClaimsList, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply internal.ListResponse
+	if err := json.Unmarshal(resp.Data, &reply); err != nil {
+		return nil, fmt.Errorf("failed to json unmarshal response: %w", err)
+	}
+
+	if reply.Error != nil {
+		return nil, fmt.Errorf("nats list failed: %s", reply.Error.Description)
+	}
+
+	return reply.Data, nil
+}
+
 func (c *Client) do(ctx context.Context, subj string, data []byte) (*internal.UpdateResponse, error) {
-	resp, err := c.conn.RequestWithContext(ctx, subj, data)
+	resp, err := c.request(ctx, subj, subj, data)
 	if err != nil {
 		return nil, err
 	}
@@ -110,20 +211,25 @@ func (c *Client) Close() {
 	c.conn.Close()
 }
 
-func makeTemporaryUser(accountSeed []byte) (ujwt string, seed string, err error) {
+func makeTemporaryUser(accountSeed []byte) (ujwt string, seed string, accountPubkey string, err error) {
 	sysKP, err := nkeys.FromSeed(accountSeed)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
+	}
+
+	accountPubkey, err = sysKP.PublicKey()
+	if err != nil {
+		return "", "", "", err
 	}
 
 	userKP, err := nkeys.CreateUser()
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 
 	userPubkey, err := userKP.PublicKey()
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 
 	userClaims := jwt.NewUserClaims(userPubkey)
@@ -131,13 +237,13 @@ func makeTemporaryUser(accountSeed []byte) (ujwt string, seed string, err error)
 
 	ujwt, err = userClaims.Encode(sysKP)
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 
 	userSeed, err := userKP.Seed()
 	if err != nil {
-		return "", "", err
+		return "", "", "", err
 	}
 
-	return ujwt, string(userSeed), nil
+	return ujwt, string(userSeed), accountPubkey, nil
 }
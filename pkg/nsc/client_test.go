@@ -0,0 +1,214 @@
+package nsc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+)
+
+func TestClient(t *testing.T) {
+	operatorKP, url, sysSeed := startFullResolver(t)
+	accountKP, accountPub, _ := newKeyPair(t, nkeys.CreateAccount)
+	_, userPub, userSeed := newKeyPair(t, nkeys.CreateUser)
+
+	encode := func(claims jwt.Claims, kp nkeys.KeyPair) string {
+		return encodeClaims(t, claims, kp)
+	}
+
+	publisher, err := NATSAccountServer{}.Connect(url, operatorKP, sysSeed)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	defer publisher.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	accountJWT := encode(jwt.NewAccountClaims(accountPub), operatorKP)
+
+	if err := publisher.Push(ctx, accountJWT); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	info, err := publisher.ServerInfo(ctx)
+	if err != nil {
+		t.Fatalf("ServerInfo() error = %v", err)
+	}
+
+	if info.Name == "" || info.Version == "" || info.ResolverType != ResolverTypeFull {
+		t.Errorf("ServerInfo() = %+v, want name, version and the full resolver", info)
+	}
+
+	if got, err := publisher.Lookup(ctx, accountPub); err != nil || got != accountJWT {
+		t.Errorf("Lookup() = %q, %v, want pushed JWT", got, err)
+	}
+
+	subjects, err := publisher.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+
+	if list := jwt.StringList(subjects); !list.Contains(accountPub) {
+		t.Errorf("List() = %v, want it to contain %s", subjects, accountPub)
+	}
+
+	events := make(chan ConnectionEvent, 10)
+
+	if err := publisher.SubscribeConnectionEvents(func(event ConnectionEvent) {
+		if event.Account == accountPub {
+			events <- event
+		}
+	}); err != nil {
+		t.Fatalf("SubscribeConnectionEvents() error = %v", err)
+	}
+
+	nextEvent := func(want ConnectionEventType) {
+		t.Helper()
+
+		select {
+		case event := <-events:
+			if event.Type != want || event.User != userPub || event.Host == "" || event.Time.IsZero() {
+				t.Errorf("connection event = %+v, want %s of user %s", event, want, userPub)
+			}
+		case <-ctx.Done():
+			t.Fatalf("timed out waiting for %s event", want)
+		}
+	}
+
+	nc, err := nats.Connect(url, nats.UserJWTAndSeed(encode(jwt.NewUserClaims(userPub), accountKP), string(userSeed)))
+	if err != nil {
+		t.Fatalf("failed to connect account user: %v", err)
+	}
+
+	nextEvent(ConnectionEventConnect)
+
+	if _, err := nc.SubscribeSync("usage"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := nc.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	usage, err := publisher.Usage(ctx, accountPub)
+	if err != nil {
+		t.Fatalf("Usage() error = %v", err)
+	}
+
+	if usage.Connections != 1 || usage.Subscriptions < 1 || usage.LastActivity.IsZero() || usage.JetStream != nil {
+		t.Errorf("Usage() = %+v, want 1 connection, a subscription, last activity and no JetStream", usage)
+	}
+
+	users, err := publisher.ConnectionsByUser(ctx, accountPub)
+	if err != nil {
+		t.Fatalf("ConnectionsByUser() error = %v", err)
+	}
+
+	if want := map[string]int{userPub: 1}; !reflect.DeepEqual(users, want) {
+		t.Errorf("ConnectionsByUser() = %v, want %v", users, want)
+	}
+
+	nc.Close()
+
+	nextEvent(ConnectionEventDisconnect)
+
+	if err := publisher.Delete(ctx, accountPub); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := publisher.Lookup(ctx, accountPub); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Lookup() after Delete() error = %v, want ErrAccountNotFound", err)
+	}
+}
+
+func newKeyPair(t *testing.T, create func() (nkeys.KeyPair, error)) (nkeys.KeyPair, string, []byte) {
+	t.Helper()
+
+	kp, err := create()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pub, err := kp.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seed, err := kp.Seed()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return kp, pub, seed
+}
+
+func encodeClaims(t *testing.T, claims jwt.Claims, kp nkeys.KeyPair) string {
+	t.Helper()
+
+	token, err := claims.Encode(kp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return token
+}
+
+// startFullResolver starts a nats-server in operator mode using the full resolver with deletes allowed, returning the
+// operator key pair, the client URL and the seed of the system account.
+func startFullResolver(t *testing.T) (nkeys.KeyPair, string, []byte) {
+	t.Helper()
+
+	operatorKP, operatorPub, _ := newKeyPair(t, nkeys.CreateOperator)
+	_, sysPub, sysSeed := newKeyPair(t, nkeys.CreateAccount)
+
+	operatorClaims := jwt.NewOperatorClaims(operatorPub)
+	operatorClaims.SystemAccount = sysPub
+
+	dir := t.TempDir()
+	config := fmt.Sprintf(`
+listen: 127.0.0.1:-1
+operator: %s
+system_account: %s
+resolver: { type: full, dir: %q, allow_delete: true }
+resolver_preload: { %s: %s }
+`, encodeClaims(t, operatorClaims, operatorKP), sysPub, filepath.Join(dir, "jwt"), sysPub,
+		encodeClaims(t, jwt.NewAccountClaims(sysPub), operatorKP))
+
+	configFile := filepath.Join(dir, "nats-server.conf")
+	if err := os.WriteFile(configFile, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	opts, err := server.ProcessConfigFile(configFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opts.NoLog = true
+	opts.NoSigs = true
+
+	srv, err := server.NewServer(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Start()
+	t.Cleanup(srv.Shutdown)
+
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats-server not ready for connections")
+	}
+
+	return operatorKP, srv.ClientURL(), sysSeed
+}
@@ -90,6 +90,43 @@ func ConvertToNatsLimits(in v1alpha1.NatsLimits, defaults jwt.NatsLimits) jwt.Na
 	}
 }
 
+func ConvertToNatsUserLimits(in v1alpha1.UserLimits, defaults jwt.Limits) jwt.Limits {
+	return jwt.Limits{
+		UserLimits: jwt.UserLimits{
+			Src:    in.Src,
+			Times:  ConvertToNatsTimeRanges(in.Times),
+			Locale: in.Locale,
+		},
+		NatsLimits: ConvertToNatsLimits(in.NatsLimits, defaults.NatsLimits),
+	}
+}
+
+func ConvertToNATSPermissions(in *v1alpha1.UserPermissions) jwt.Permissions {
+	var out jwt.Permissions
+
+	if in == nil {
+		return out
+	}
+
+	out.Pub = jwt.Permission{
+		Allow: in.Pub.Allow,
+		Deny:  in.Pub.Deny,
+	}
+	out.Sub = jwt.Permission{
+		Allow: in.Sub.Allow,
+		Deny:  in.Sub.Deny,
+	}
+
+	if in.Resp != nil {
+		out.Resp = &jwt.ResponsePermission{
+			MaxMsgs: in.Resp.MaxMsgs,
+			Expires: in.Resp.TTL.Duration,
+		}
+	}
+
+	return out
+}
+
 func ConvertToAccountLimits(in v1alpha1.AccountLimits, defaults jwt.AccountLimits) jwt.AccountLimits {
 	return jwt.AccountLimits{
 		Imports:         getDefaultFromPtr(in.Imports, defaults.Imports),
@@ -101,6 +138,32 @@ func ConvertToAccountLimits(in v1alpha1.AccountLimits, defaults jwt.AccountLimit
 	}
 }
 
+func ConvertToJetStreamLimits(in v1alpha1.JetStreamLimits) jwt.JetStreamLimits {
+	return jwt.JetStreamLimits{
+		MemoryStorage:        in.MemoryStorage,
+		DiskStorage:          in.DiskStorage,
+		Streams:              in.Streams,
+		Consumer:             in.Consumer,
+		MaxAckPending:        in.MaxAckPending,
+		MemoryMaxStreamBytes: in.MemoryMaxStreamBytes,
+		DiskMaxStreamBytes:   in.DiskMaxStreamBytes,
+		MaxBytesRequired:     in.MaxBytesRequired,
+	}
+}
+
+func ConvertToJetStreamTieredLimits(in map[string]v1alpha1.JetStreamLimits) jwt.JetStreamTieredLimits {
+	if len(in) == 0 {
+		return nil
+	}
+
+	out := make(jwt.JetStreamTieredLimits, len(in))
+	for tier, limits := range in {
+		out[tier] = ConvertToJetStreamLimits(limits)
+	}
+
+	return out
+}
+
 func ConvertToNatsTimeRanges(in []v1alpha1.StartEndTime) []jwt.TimeRange {
 	if in == nil {
 		return nil
@@ -116,3 +179,60 @@ func ConvertToNatsTimeRanges(in []v1alpha1.StartEndTime) []jwt.TimeRange {
 
 	return out
 }
+
+func ConvertToNATSExternalAuthorization(in *v1alpha1.AccountAuthorizationStatus) jwt.ExternalAuthorization {
+	var out jwt.ExternalAuthorization
+
+	if in == nil {
+		return out
+	}
+
+	for _, u := range in.AuthUsers {
+		out.AuthUsers.Add(u.PublicKey)
+	}
+
+	for _, a := range in.AllowedAccounts {
+		out.AllowedAccounts.Add(a.PublicKey)
+	}
+
+	if in.XKey != nil {
+		out.XKey = in.XKey.PublicKey
+	}
+
+	return out
+}
+
+func ConvertToNATSConnectionType(ct v1alpha1.ConnectionType) string {
+	switch ct {
+	case v1alpha1.ConnectionTypeStandard:
+		return jwt.ConnectionTypeStandard
+	case v1alpha1.ConnectionTypeWebsocket:
+		return jwt.ConnectionTypeWebsocket
+	case v1alpha1.ConnectionTypeLeafnode:
+		return jwt.ConnectionTypeLeafnode
+	case v1alpha1.ConnectionTypeLeafnodeWS:
+		return jwt.ConnectionTypeLeafnodeWS
+	case v1alpha1.ConnectionTypeMqtt:
+		return jwt.ConnectionTypeMqtt
+	case v1alpha1.ConnectionTypeMqttWS:
+		return jwt.ConnectionTypeMqttWS
+	default:
+		// the CRD validation should prevent this, pass it through so the server rejects it rather than silently
+		// allowing every connection type.
+		return string(ct)
+	}
+}
+
+func ConvertToNATSConnectionTypes(in []v1alpha1.ConnectionType) jwt.StringList {
+	if len(in) == 0 {
+		return nil
+	}
+
+	var out jwt.StringList
+
+	for _, ct := range in {
+		out.Add(ConvertToNATSConnectionType(ct))
+	}
+
+	return out
+}
@@ -1,6 +1,7 @@
 package nsc
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/nats-io/jwt/v2"
@@ -9,6 +10,9 @@ import (
 	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
 )
 
+// ErrInvalidLimits is returned when the limits defined on an Account cannot be represented in a valid JWT.
+var ErrInvalidLimits = errors.New("invalid limits")
+
 func CreateAccountClaims(
 	resource *v1alpha1.Account,
 	signingKey nkeys.KeyPair,
@@ -22,22 +26,20 @@ func CreateAccountClaims(
 	claims.Imports = ConvertToNATSImports(spec.Imports)
 
 	if spec.Limits != nil {
+		if err = validateJetStreamLimits(spec.Limits); err != nil {
+			return nil, "", err
+		}
+
 		claims.Limits = jwt.OperatorLimits{
-			NatsLimits:    ConvertToNatsLimits(spec.Limits.Nats, claims.Limits.NatsLimits),
-			AccountLimits: ConvertToAccountLimits(spec.Limits.Account, claims.Limits.AccountLimits),
-			JetStreamLimits: jwt.JetStreamLimits{
-				MemoryStorage:        spec.Limits.JetStream.MemoryStorage,
-				DiskStorage:          spec.Limits.JetStream.DiskStorage,
-				Streams:              spec.Limits.JetStream.Streams,
-				Consumer:             spec.Limits.JetStream.Consumer,
-				MaxAckPending:        spec.Limits.JetStream.MaxAckPending,
-				MemoryMaxStreamBytes: spec.Limits.JetStream.MemoryMaxStreamBytes,
-				DiskMaxStreamBytes:   spec.Limits.JetStream.DiskMaxStreamBytes,
-				MaxBytesRequired:     spec.Limits.JetStream.MaxBytesRequired,
-			},
+			NatsLimits:            ConvertToNatsLimits(spec.Limits.Nats, claims.Limits.NatsLimits),
+			AccountLimits:         ConvertToAccountLimits(spec.Limits.Account, claims.Limits.AccountLimits),
+			JetStreamLimits:       ConvertToJetStreamLimits(spec.Limits.JetStream),
+			JetStreamTieredLimits: ConvertToJetStreamTieredLimits(spec.Limits.TieredJetStream),
 		}
 	}
 
+	claims.Authorization = ConvertToNATSExternalAuthorization(resource.Status.Authorization)
+
 	for _, sk := range resource.Status.SigningKeys {
 		claims.SigningKeys.Add(sk.KeyPair.PublicKey)
 	}
@@ -49,3 +51,21 @@ func CreateAccountClaims(
 
 	return claims, ajwt, nil
 }
+
+// validateJetStreamLimits checks that flat and tiered JetStream limits are not mixed, NATS will reject an Account JWT
+// which defines both.
+func validateJetStreamLimits(limits *v1alpha1.OperatorLimits) error {
+	if len(limits.TieredJetStream) == 0 {
+		return nil
+	}
+
+	if limits.JetStream != (v1alpha1.JetStreamLimits{}) {
+		return fmt.Errorf("%w: jetStream and tieredJetStream limits are mutually exclusive", ErrInvalidLimits)
+	}
+
+	if _, ok := limits.TieredJetStream[""]; ok {
+		return fmt.Errorf("%w: tieredJetStream limits cannot contain a blank tier name", ErrInvalidLimits)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package nsc
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+func TestCreateAccountClaims_JetStreamLimits(t *testing.T) {
+	operatorKP, err := nkeys.CreateOperator()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountKP, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountPub, err := accountKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		limits     *v1alpha1.OperatorLimits
+		wantErr    error
+		wantFlat   jwt.JetStreamLimits
+		wantTiered jwt.JetStreamTieredLimits
+	}{
+		{
+			name: "flat limits",
+			limits: &v1alpha1.OperatorLimits{
+				JetStream: v1alpha1.JetStreamLimits{DiskStorage: 1024, Streams: 10},
+			},
+			wantFlat: jwt.JetStreamLimits{DiskStorage: 1024, Streams: 10},
+		},
+		{
+			name: "tiered limits",
+			limits: &v1alpha1.OperatorLimits{
+				TieredJetStream: map[string]v1alpha1.JetStreamLimits{
+					"R1": {DiskStorage: 1024, Streams: 10},
+					"R3": {DiskStorage: 512, Streams: 2},
+				},
+			},
+			wantTiered: jwt.JetStreamTieredLimits{
+				"R1": {DiskStorage: 1024, Streams: 10},
+				"R3": {DiskStorage: 512, Streams: 2},
+			},
+		},
+		{
+			name: "mixed flat and tiered limits",
+			limits: &v1alpha1.OperatorLimits{
+				JetStream: v1alpha1.JetStreamLimits{DiskStorage: 1024},
+				TieredJetStream: map[string]v1alpha1.JetStreamLimits{
+					"R1": {DiskStorage: 1024},
+				},
+			},
+			wantErr: ErrInvalidLimits,
+		},
+		{
+			name: "blank tier name",
+			limits: &v1alpha1.OperatorLimits{
+				TieredJetStream: map[string]v1alpha1.JetStreamLimits{
+					"": {DiskStorage: 1024},
+				},
+			},
+			wantErr: ErrInvalidLimits,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			account := &v1alpha1.Account{
+				Spec: v1alpha1.AccountSpec{Limits: tt.limits},
+				Status: v1alpha1.AccountStatus{
+					KeyPair: &v1alpha1.KeyPair{PublicKey: accountPub},
+				},
+			}
+
+			_, ajwt, err := CreateAccountClaims(account, operatorKP)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			claims, err := jwt.DecodeAccountClaims(ajwt)
+			if err != nil {
+				t.Fatalf("failed to decode account JWT: %v", err)
+			}
+
+			if claims.Limits.JetStreamLimits != tt.wantFlat {
+				t.Errorf("JetStreamLimits = %+v, want %+v", claims.Limits.JetStreamLimits, tt.wantFlat)
+			}
+
+			if len(claims.Limits.JetStreamTieredLimits) != len(tt.wantTiered) {
+				t.Fatalf("JetStreamTieredLimits = %+v, want %+v", claims.Limits.JetStreamTieredLimits, tt.wantTiered)
+			}
+
+			for tier, want := range tt.wantTiered {
+				if got := claims.Limits.JetStreamTieredLimits[tier]; got != want {
+					t.Errorf("JetStreamTieredLimits[%q] = %+v, want %+v", tier, got, want)
+				}
+			}
+		})
+	}
+}
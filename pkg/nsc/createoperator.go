@@ -31,14 +31,17 @@ func CreateOperatorClaims(resource *v1alpha1.Operator, signingKey nkeys.KeyPair)
 	claims.Name = resource.Name
 	claims.IssuedAt = time.Now().Unix()
 	claims.Operator = jwt.Operator{
-		SigningKeys:         signingKeys,
-		AccountServerURL:    spec.AccountServerURL,
-		OperatorServiceURLs: spec.OperatorServiceURLs,
-		SystemAccount:       resource.Status.ResolvedSystemAccount.PublicKey,
+		SigningKeys:           signingKeys,
+		AccountServerURL:      spec.AccountServerURL,
+		OperatorServiceURLs:   spec.OperatorServiceURLs,
+		SystemAccount:         resource.Status.ResolvedSystemAccount.PublicKey,
+		AssertServerVersion:   spec.AssertServerVersion,
+		StrictSigningKeyUsage: spec.StrictSigningKeyUsage,
 		GenericFields: jwt.GenericFields{
 			Type: jwt.OperatorClaim,
 		},
 	}
+	claims.Tags.Add(spec.Tags...)
 
 	ojwt, err := claims.Encode(signingKey)
 	if err != nil {
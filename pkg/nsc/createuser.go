@@ -2,6 +2,7 @@ package nsc
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/nats-io/jwt/v2"
 	"github.com/nats-io/nkeys"
@@ -9,54 +10,34 @@ import (
 	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
 )
 
+// UserClaimsOption modifies the claims built by CreateUserClaims before they are encoded.
+type UserClaimsOption func(claims *jwt.UserClaims)
+
+// WithExpiry sets the expiry of the User JWT.
+func WithExpiry(t time.Time) UserClaimsOption {
+	return func(claims *jwt.UserClaims) {
+		claims.Expires = t.Unix()
+	}
+}
+
 func CreateUserClaims(
 	resource *v1alpha1.User,
 	account *v1alpha1.Account,
 	signingKey nkeys.KeyPair,
+	opts ...UserClaimsOption,
 ) (claims *jwt.UserClaims, ujwt string, err error) {
 	claims = jwt.NewUserClaims(resource.Status.KeyPair.PublicKey)
 	claims.Name = resource.Name
 
 	spec := resource.Spec
-	specLimits := spec.Limits
-
-	claims.Limits = jwt.Limits{
-		UserLimits: jwt.UserLimits{
-			Src:    specLimits.Src,
-			Times:  ConvertToNatsTimeRanges(specLimits.Times),
-			Locale: specLimits.Locale,
-		},
-		NatsLimits: ConvertToNatsLimits(specLimits.NatsLimits, claims.Limits.NatsLimits),
-	}
 
-	if spec.BearerToken != nil {
-		claims.BearerToken = *spec.BearerToken
-	}
-
-	if spec.Permissions != nil {
-		claims.UserPermissionLimits = jwt.UserPermissionLimits{
-			Permissions: jwt.Permissions{
-				Pub: jwt.Permission{
-					Allow: spec.Permissions.Pub.Allow,
-					Deny:  spec.Permissions.Pub.Deny,
-				},
-				Sub: jwt.Permission{
-					Allow: spec.Permissions.Sub.Allow,
-					Deny:  spec.Permissions.Sub.Deny,
-				},
-			},
-			Limits:                 jwt.Limits{},
-			BearerToken:            false,
-			AllowedConnectionTypes: nil,
-		}
-
-		if spec.Permissions.Resp != nil {
-			claims.Resp = &jwt.ResponsePermission{
-				MaxMsgs: spec.Permissions.Resp.MaxMsgs,
-				Expires: spec.Permissions.Resp.TTL.Duration,
-			}
-		}
-	}
+	// each field of the UserSpec is applied independently to the embedded jwt.UserPermissionLimits, so that setting
+	// one never resets another.
+	claims.Permissions = ConvertToNATSPermissions(spec.Permissions)
+	claims.Limits = ConvertToNatsUserLimits(spec.Limits, claims.Limits)
+	claims.BearerToken = getDefaultFromPtr(spec.BearerToken, false)
+	claims.AllowedConnectionTypes = ConvertToNATSConnectionTypes(spec.AllowedConnectionTypes)
+	claims.Tags.Add(spec.Tags...)
 
 	skPub, err := signingKey.PublicKey()
 	if err != nil {
@@ -70,6 +51,10 @@ func CreateUserClaims(
 		claims.IssuerAccount = accountKP.PublicKey
 	}
 
+	for _, opt := range opts {
+		opt(claims)
+	}
+
 	ujwt, err = claims.Encode(signingKey)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to encode user claims: %w", err)
@@ -0,0 +1,262 @@
+package nsc
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+func TestCreateUserClaims_ConnectionTypesAndTags(t *testing.T) {
+	accountKP, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountPub, err := accountKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userKP, err := nkeys.CreateUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userPub, err := userKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                   string
+		allowedConnectionTypes []v1alpha1.ConnectionType
+		tags                   []string
+		wantConnectionTypes    jwt.StringList
+		wantTags               jwt.TagList
+	}{
+		{
+			name: "unset",
+		},
+		{
+			name: "all connection types",
+			allowedConnectionTypes: []v1alpha1.ConnectionType{
+				v1alpha1.ConnectionTypeStandard,
+				v1alpha1.ConnectionTypeWebsocket,
+				v1alpha1.ConnectionTypeLeafnode,
+				v1alpha1.ConnectionTypeLeafnodeWS,
+				v1alpha1.ConnectionTypeMqtt,
+				v1alpha1.ConnectionTypeMqttWS,
+			},
+			wantConnectionTypes: jwt.StringList{
+				jwt.ConnectionTypeStandard,
+				jwt.ConnectionTypeWebsocket,
+				jwt.ConnectionTypeLeafnode,
+				jwt.ConnectionTypeLeafnodeWS,
+				jwt.ConnectionTypeMqtt,
+				jwt.ConnectionTypeMqttWS,
+			},
+		},
+		{
+			name:     "tags",
+			tags:     []string{"team:orders", "Env:Prod"},
+			wantTags: jwt.TagList{"team:orders", "env:prod"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := &v1alpha1.User{
+				Spec: v1alpha1.UserSpec{
+					AllowedConnectionTypes: tt.allowedConnectionTypes,
+					Tags:                   tt.tags,
+				},
+				Status: v1alpha1.UserStatus{
+					KeyPair: &v1alpha1.KeyPair{PublicKey: userPub},
+				},
+			}
+
+			account := &v1alpha1.Account{
+				Status: v1alpha1.AccountStatus{
+					KeyPair: &v1alpha1.KeyPair{PublicKey: accountPub},
+				},
+			}
+
+			_, ujwt, err := CreateUserClaims(user, account, accountKP)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			claims, err := jwt.DecodeUserClaims(ujwt)
+			if err != nil {
+				t.Fatalf("failed to decode user JWT: %v", err)
+			}
+
+			vr := jwt.CreateValidationResults()
+			claims.Validate(vr)
+
+			if len(vr.Issues) > 0 {
+				t.Errorf("user JWT has validation issues: %v", vr.Issues)
+			}
+
+			if !reflect.DeepEqual(claims.AllowedConnectionTypes, tt.wantConnectionTypes) {
+				t.Errorf("AllowedConnectionTypes = %v, want %v", claims.AllowedConnectionTypes, tt.wantConnectionTypes)
+			}
+
+			if !reflect.DeepEqual(claims.Tags, tt.wantTags) {
+				t.Errorf("Tags = %v, want %v", claims.Tags, tt.wantTags)
+			}
+		})
+	}
+}
+
+// TestCreateUserClaims_Composition checks that permissions, limits and the bearer token flag are applied independently,
+// and that setting any one of them does not reset the others.
+func TestCreateUserClaims_Composition(t *testing.T) {
+	accountKP, err := nkeys.CreateAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accountPub, err := accountKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userKP, err := nkeys.CreateUser()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	userPub, err := userKP.PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subs, data, payload := int64(10), int64(1024), int64(512)
+
+	permissions := []struct {
+		name string
+		in   *v1alpha1.UserPermissions
+		want jwt.Permissions
+	}{
+		{
+			name: "no permissions",
+		},
+		{
+			name: "pub sub permissions",
+			in: &v1alpha1.UserPermissions{
+				Pub: v1alpha1.Permission{Allow: []string{"orders.>"}, Deny: []string{"orders.secret"}},
+				Sub: v1alpha1.Permission{Allow: []string{"_INBOX.>"}},
+			},
+			want: jwt.Permissions{
+				Pub: jwt.Permission{Allow: jwt.StringList{"orders.>"}, Deny: jwt.StringList{"orders.secret"}},
+				Sub: jwt.Permission{Allow: jwt.StringList{"_INBOX.>"}},
+			},
+		},
+		{
+			name: "response permissions",
+			in: &v1alpha1.UserPermissions{
+				Sub:  v1alpha1.Permission{Allow: []string{"orders.>"}},
+				Resp: &v1alpha1.RespPermission{MaxMsgs: 1, TTL: metav1.Duration{Duration: time.Minute}},
+			},
+			want: jwt.Permissions{
+				Sub:  jwt.Permission{Allow: jwt.StringList{"orders.>"}},
+				Resp: &jwt.ResponsePermission{MaxMsgs: 1, Expires: time.Minute},
+			},
+		},
+	}
+
+	limits := []struct {
+		name string
+		in   v1alpha1.UserLimits
+		want jwt.Limits
+	}{
+		{
+			name: "no limits",
+			want: jwt.Limits{
+				NatsLimits: jwt.NatsLimits{Subs: jwt.NoLimit, Data: jwt.NoLimit, Payload: jwt.NoLimit},
+			},
+		},
+		{
+			name: "all limits",
+			in: v1alpha1.UserLimits{
+				NatsLimits: v1alpha1.NatsLimits{Subs: &subs, Data: &data, Payload: &payload},
+				Src:        []string{"10.0.0.0/8"},
+				Times:      []v1alpha1.StartEndTime{{Start: "09:00:00", End: "17:00:00"}},
+				Locale:     "Europe/London",
+			},
+			want: jwt.Limits{
+				UserLimits: jwt.UserLimits{
+					Src:    jwt.CIDRList{"10.0.0.0/8"},
+					Times:  []jwt.TimeRange{{Start: "09:00:00", End: "17:00:00"}},
+					Locale: "Europe/London",
+				},
+				NatsLimits: jwt.NatsLimits{Subs: subs, Data: data, Payload: payload},
+			},
+		},
+	}
+
+	bearerTokens := []struct {
+		name string
+		in   *bool
+		want bool
+	}{
+		{name: "bearer unset"},
+		{name: "bearer false", in: new(bool)},
+		{name: "bearer true", in: func() *bool { b := true; return &b }(), want: true},
+	}
+
+	for _, perms := range permissions {
+		for _, lim := range limits {
+			for _, bearer := range bearerTokens {
+				t.Run(fmt.Sprintf("%s/%s/%s", perms.name, lim.name, bearer.name), func(t *testing.T) {
+					user := &v1alpha1.User{
+						Spec: v1alpha1.UserSpec{
+							Permissions: perms.in,
+							Limits:      lim.in,
+							BearerToken: bearer.in,
+						},
+						Status: v1alpha1.UserStatus{
+							KeyPair: &v1alpha1.KeyPair{PublicKey: userPub},
+						},
+					}
+
+					account := &v1alpha1.Account{
+						Status: v1alpha1.AccountStatus{
+							KeyPair: &v1alpha1.KeyPair{PublicKey: accountPub},
+						},
+					}
+
+					_, ujwt, err := CreateUserClaims(user, account, accountKP)
+					if err != nil {
+						t.Fatalf("unexpected error: %v", err)
+					}
+
+					claims, err := jwt.DecodeUserClaims(ujwt)
+					if err != nil {
+						t.Fatalf("failed to decode user JWT: %v", err)
+					}
+
+					if !reflect.DeepEqual(claims.Permissions, perms.want) {
+						t.Errorf("Permissions = %+v, want %+v", claims.Permissions, perms.want)
+					}
+
+					if !reflect.DeepEqual(claims.Limits, lim.want) {
+						t.Errorf("Limits = %+v, want %+v", claims.Limits, lim.want)
+					}
+
+					if claims.BearerToken != bearer.want {
+						t.Errorf("BearerToken = %v, want %v", claims.BearerToken, bearer.want)
+					}
+				})
+			}
+		}
+	}
+}
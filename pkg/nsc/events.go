@@ -0,0 +1,65 @@
+package nsc
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/versori-oss/nats-account-operator/pkg/nsc/internal"
+)
+
+// SubjectAccountEvents matches the $SYS.ACCOUNT.<account>.CONNECT and DISCONNECT events, along with other account
+// events which are filtered out by their type.
+const SubjectAccountEvents = "$SYS.ACCOUNT.*.*"
+
+type ConnectionEventType string
+
+const (
+	ConnectionEventConnect    ConnectionEventType = "io.nats.server.advisory.v1.client_connect"
+	ConnectionEventDisconnect ConnectionEventType = "io.nats.server.advisory.v1.client_disconnect"
+)
+
+// ConnectionEvent is a client connecting to or disconnecting from an account.
+type ConnectionEvent struct {
+	Type ConnectionEventType
+	Time time.Time
+	// Account is the public key of the account the client connected to.
+	Account string
+	// User is the public key of the user the client authenticated as.
+	User string
+	// Host is the address the client connected from.
+	Host string
+}
+
+// SubscribeConnectionEvents calls handler with every client connect and disconnect event published by the servers
+// until the Client is closed. Events are delivered one at a time in the order they are received, and events which
+// cannot be decoded are dropped.
+func (c *Client) SubscribeConnectionEvents(handler func(ConnectionEvent)) error {
+	// a single subscription covers both subjects so that a disconnect is never handled before its connect
+	_, err := c.conn.Subscribe(SubjectAccountEvents, func(msg *nats.Msg) {
+		var event internal.ConnectionEventMsg
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			return
+		}
+
+		eventType := ConnectionEventType(event.Type)
+		if eventType != ConnectionEventConnect && eventType != ConnectionEventDisconnect {
+			return
+		}
+
+		handler(ConnectionEvent{
+			Type:    eventType,
+			Time:    event.Time,
+			Account: event.Client.Account,
+			User:    event.Client.User,
+			Host:    event.Client.Host,
+		})
+	})
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", SubjectAccountEvents, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,306 @@
+// Package fake provides in-memory implementations of the nsc account server interfaces for testing controllers without
+// a nats-server.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+)
+
+// Push records a JWT pushed to the AccountServer.
+type Push struct {
+	// URL is the account server URL the publisher was connected to.
+	URL     string
+	Subject string
+	JWT     string
+}
+
+// AccountServer is an in-memory nsc.AccountServer. Every publisher it connects shares the same accounts, and every
+// push and delete is recorded so tests can assert on them.
+type AccountServer struct {
+	// ConnectErr, PushErr, DeleteErr and UsageErr are returned by the corresponding calls when set, UsageErr is also
+	// returned by ConnectionsByUser.
+	ConnectErr error
+	PushErr    error
+	DeleteErr  error
+	UsageErr   error
+
+	// Server is returned by the ServerInfo call of every publisher.
+	Server nsc.ServerInfo
+
+	mu       sync.Mutex
+	accounts map[string]string
+	usage    map[string]nsc.AccountUsage
+	conns    map[string]map[string]int
+	pushes   []Push
+	deletes  []string
+	handlers map[*publisher]func(nsc.ConnectionEvent)
+	open     map[*publisher]bool
+}
+
+var _ nsc.AccountServer = (*AccountServer)(nil)
+
+// NewAccountServer returns an AccountServer preloaded with the account JWTs in jwts.
+func NewAccountServer(jwts ...string) (*AccountServer, error) {
+	s := &AccountServer{accounts: make(map[string]string, len(jwts))}
+
+	for _, token := range jwts {
+		claims, err := jwt.DecodeAccountClaims(token)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode account JWT: %w", err)
+		}
+
+		s.accounts[claims.Subject] = token
+	}
+
+	return s, nil
+}
+
+func (s *AccountServer) Connect(url string, _ nkeys.KeyPair, _ []byte, opts ...nats.Option) (nsc.AccountPublisher, error) {
+	if s.ConnectErr != nil {
+		return nil, s.ConnectErr
+	}
+
+	var options nats.Options
+
+	for _, opt := range opts {
+		if err := opt(&options); err != nil {
+			return nil, err
+		}
+	}
+
+	p := &publisher{server: s, url: url, reconnected: options.ReconnectedCB}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.open == nil {
+		s.open = make(map[*publisher]bool)
+	}
+
+	s.open[p] = true
+
+	return p, nil
+}
+
+// Reconnect calls the nats.ReconnectHandler of every open publisher, as if each had lost its connection and
+// reconnected. The handlers are called with a nil connection.
+func (s *AccountServer) Reconnect() {
+	s.mu.Lock()
+
+	handlers := make([]nats.ConnHandler, 0, len(s.open))
+	for p := range s.open {
+		if p.reconnected != nil {
+			handlers = append(handlers, p.reconnected)
+		}
+	}
+
+	s.mu.Unlock()
+
+	for _, h := range handlers {
+		h(nil)
+	}
+}
+
+// Pushes returns every JWT pushed so far, in order.
+func (s *AccountServer) Pushes() []Push {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Push(nil), s.pushes...)
+}
+
+// SetUsage sets the usage returned for the account with the public key subject, accounts without usage set report
+// zero usage.
+func (s *AccountServer) SetUsage(subject string, usage nsc.AccountUsage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.usage == nil {
+		s.usage = make(map[string]nsc.AccountUsage)
+	}
+
+	s.usage[subject] = usage
+}
+
+// SetConnections sets the number of open connections of each user returned for the account with the public key subject.
+func (s *AccountServer) SetConnections(subject string, users map[string]int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.conns == nil {
+		s.conns = make(map[string]map[string]int)
+	}
+
+	s.conns[subject] = users
+}
+
+// EmitConnectionEvent delivers event to the connection event handlers of every open publisher.
+func (s *AccountServer) EmitConnectionEvent(event nsc.ConnectionEvent) {
+	s.mu.Lock()
+
+	handlers := make([]func(nsc.ConnectionEvent), 0, len(s.handlers))
+	for _, h := range s.handlers {
+		handlers = append(handlers, h)
+	}
+
+	s.mu.Unlock()
+
+	for _, h := range handlers {
+		h(event)
+	}
+}
+
+// Deletes returns the public key of every account deleted so far, in order.
+func (s *AccountServer) Deletes() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]string(nil), s.deletes...)
+}
+
+type publisher struct {
+	server      *AccountServer
+	url         string
+	reconnected nats.ConnHandler
+}
+
+func (p *publisher) Push(_ context.Context, token string) error {
+	if p.server.PushErr != nil {
+		return p.server.PushErr
+	}
+
+	claims, err := jwt.DecodeAccountClaims(token)
+	if err != nil {
+		return fmt.Errorf("nats push failed: %w", err)
+	}
+
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	if p.server.accounts == nil {
+		p.server.accounts = make(map[string]string)
+	}
+
+	p.server.accounts[claims.Subject] = token
+	p.server.pushes = append(p.server.pushes, Push{URL: p.url, Subject: claims.Subject, JWT: token})
+
+	return nil
+}
+
+func (p *publisher) Delete(_ context.Context, subject string) error {
+	if p.server.DeleteErr != nil {
+		return p.server.DeleteErr
+	}
+
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	delete(p.server.accounts, subject)
+	p.server.deletes = append(p.server.deletes, subject)
+
+	return nil
+}
+
+func (p *publisher) Lookup(_ context.Context, subject string) (string, error) {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	token, ok := p.server.accounts[subject]
+	if !ok {
+		return "", nsc.ErrAccountNotFound
+	}
+
+	return token, nil
+}
+
+func (p *publisher) List(_ context.Context) ([]string, error) {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	subjects := make([]string, 0, len(p.server.accounts))
+	for subject := range p.server.accounts {
+		subjects = append(subjects, subject)
+	}
+
+	sort.Strings(subjects)
+
+	return subjects, nil
+}
+
+func (p *publisher) Usage(_ context.Context, subject string) (*nsc.AccountUsage, error) {
+	if p.server.UsageErr != nil {
+		return nil, p.server.UsageErr
+	}
+
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	usage := p.server.usage[subject]
+
+	return &usage, nil
+}
+
+func (p *publisher) ConnectionsByUser(_ context.Context, subject string) (map[string]int, error) {
+	if p.server.UsageErr != nil {
+		return nil, p.server.UsageErr
+	}
+
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	users := make(map[string]int, len(p.server.conns[subject]))
+	for user, n := range p.server.conns[subject] {
+		users[user] = n
+	}
+
+	return users, nil
+}
+
+func (p *publisher) SubscribeConnectionEvents(handler func(nsc.ConnectionEvent)) error {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	if p.server.handlers == nil {
+		p.server.handlers = make(map[*publisher]func(nsc.ConnectionEvent))
+	}
+
+	p.server.handlers[p] = handler
+
+	return nil
+}
+
+func (p *publisher) ServerInfo(_ context.Context) (*nsc.ServerInfo, error) {
+	info := p.server.Server
+
+	return &info, nil
+}
+
+func (p *publisher) Close() {
+	p.server.mu.Lock()
+	defer p.server.mu.Unlock()
+
+	delete(p.server.handlers, p)
+	delete(p.server.open, p)
+}
+
+// SystemCredentialsLoader is an nsc.SystemCredentialsLoader returning a fixed seed, or Err when set.
+type SystemCredentialsLoader struct {
+	Seed []byte
+	Err  error
+}
+
+var _ nsc.SystemCredentialsLoader = (*SystemCredentialsLoader)(nil)
+
+func (l *SystemCredentialsLoader) Load(_ context.Context, _ *v1alpha1.Operator) ([]byte, error) {
+	return l.Seed, l.Err
+}
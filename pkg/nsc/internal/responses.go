@@ -37,3 +37,72 @@ type UpdateResponse struct {
 	Error  *ErrorInfo         `json:"error,omitempty"`
 	Data   UpdateResponseData `json:"data,omitempty"`
 }
+
+// ListResponse is the response payload from the $SYS.REQ.CLAIMS.LIST request, Data contains the public keys of every
+// account stored by the resolver.
+type ListResponse struct {
+	Server ServerInfo `json:"server"`
+	Error  *ErrorInfo `json:"error,omitempty"`
+	Data   []string   `json:"data,omitempty"`
+}
+
+// ConnzResponse is the response payload from a server to the $SYS.REQ.ACCOUNT.<account>.CONNZ request, containing the
+// subset of nats-server's Connz struct used by the operator.
+type ConnzResponse struct {
+	Server ServerInfo `json:"server"`
+	Error  *ErrorInfo `json:"error,omitempty"`
+	Data   *struct {
+		NumConns    int `json:"num_connections"`
+		Total       int `json:"total"`
+		Connections []struct {
+			LastActivity time.Time `json:"last_activity"`
+			// AuthorizedUser is the public key of the user, only set when the request has "auth": true.
+			AuthorizedUser string `json:"authorized_user,omitempty"`
+		} `json:"connections"`
+	} `json:"data,omitempty"`
+}
+
+// StatzResponse is the response payload from a server to the $SYS.REQ.ACCOUNT.<account>.STATZ request.
+type StatzResponse struct {
+	Server ServerInfo `json:"server"`
+	Error  *ErrorInfo `json:"error,omitempty"`
+	Data   *struct {
+		Accounts []struct {
+			Account       string `json:"acc"`
+			Conns         int    `json:"conns"`
+			LeafNodes     int    `json:"leafnodes"`
+			TotalConns    int    `json:"total_conns"`
+			NumSubs       uint32 `json:"num_subscriptions"`
+			SlowConsumers int64  `json:"slow_consumers"`
+		} `json:"account_statz"`
+	} `json:"data,omitempty"`
+}
+
+// JSZResponse is the response payload from a server to the $SYS.REQ.ACCOUNT.<account>.JSZ request.
+type JSZResponse struct {
+	Server ServerInfo `json:"server"`
+	Error  *ErrorInfo `json:"error,omitempty"`
+	Data   *struct {
+		Memory uint64 `json:"memory"`
+		Store  uint64 `json:"storage"`
+	} `json:"data,omitempty"`
+}
+
+// ConnectionEventMsg is the subset of nats-server's ConnectEventMsg and DisconnectEventMsg published to
+// $SYS.ACCOUNT.<account>.CONNECT and DISCONNECT, which share the same layout.
+type ConnectionEventMsg struct {
+	Type   string     `json:"type"`
+	ID     string     `json:"id"`
+	Time   time.Time  `json:"timestamp"`
+	Server ServerInfo `json:"server"`
+	Client struct {
+		Host    string `json:"host,omitempty"`
+		Account string `json:"acc,omitempty"`
+		User    string `json:"user,omitempty"`
+	} `json:"client"`
+}
+
+// ServerStatsMsg is the response payload from the $SYS.REQ.SERVER.PING request, the server statistics are omitted.
+type ServerStatsMsg struct {
+	Server ServerInfo `json:"server"`
+}
@@ -0,0 +1,69 @@
+package nsc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/versori-oss/nats-account-operator/pkg/nsc/internal"
+)
+
+const RequestSubjectServerPing = "$SYS.REQ.SERVER.PING"
+
+const (
+	// ResolverTypeFull is a resolver storing every account JWT, which responds to lookups and deletes.
+	ResolverTypeFull = "full"
+	// ResolverTypeCache is a resolver caching account JWTs fetched from a full resolver.
+	ResolverTypeCache = "cache"
+)
+
+// ServerInfo describes the server a Client is connected to.
+type ServerInfo struct {
+	Name      string
+	Version   string
+	JetStream bool
+	// ResolverType is ResolverTypeFull or ResolverTypeCache, or empty when the server does not respond to the
+	// $SYS.REQ.CLAIMS subjects, such as when it is configured with a memory or URL resolver.
+	ResolverType string
+}
+
+// ServerInfo pings the servers and returns the first reply. The resolver type is inferred from the subjects it
+// responds to: only the full resolver answers lookups, while both the full and cache resolvers answer list requests.
+func (c *Client) ServerInfo(ctx context.Context) (*ServerInfo, error) {
+	resp, err := c.request(ctx, RequestSubjectServerPing, RequestSubjectServerPing, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var reply internal.ServerStatsMsg
+	if err := json.Unmarshal(resp.Data, &reply); err != nil {
+		return nil, fmt.Errorf("failed to json unmarshal response: %w", err)
+	}
+
+	info := &ServerInfo{
+		Name:      reply.Server.Name,
+		Version:   reply.Server.Version,
+		JetStream: reply.Server.JetStream,
+	}
+
+	// the system account always exists, so any full resolver replies with its JWT
+	_, err = c.Lookup(ctx, c.systemAccount)
+
+	switch {
+	case err == nil || errors.Is(err, ErrAccountNotFound):
+		info.ResolverType = ResolverTypeFull
+	case errors.Is(err, nats.ErrNoResponders):
+		if _, err := c.List(ctx); err == nil {
+			info.ResolverType = ResolverTypeCache
+		} else if !errors.Is(err, nats.ErrNoResponders) {
+			return nil, fmt.Errorf("failed to detect resolver type: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("failed to detect resolver type: %w", err)
+	}
+
+	return info, nil
+}
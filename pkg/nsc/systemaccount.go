@@ -4,25 +4,29 @@ import (
 	"context"
 	"fmt"
 
-	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	clientsetv1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
-	clientsetv1alpha1 "github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned/typed/accounts/v1alpha1"
 )
 
+// SystemCredentialsLoader loads the seed of the system account for an Operator, which is used to authenticate with its
+// account server.
+type SystemCredentialsLoader interface {
+	Load(ctx context.Context, operator *v1alpha1.Operator) (seed []byte, err error)
+}
+
+var _ SystemCredentialsLoader = (*SystemAccountLoader)(nil)
+
+// SystemAccountLoader is a SystemCredentialsLoader which reads the seed Secret of the Account resolved as the system
+// account of the Operator.
 type SystemAccountLoader struct {
-	accounts clientsetv1alpha1.AccountsV1alpha1Interface
-	core     clientsetv1.CoreV1Interface
+	reader client.Reader
 }
 
-func NewSystemAccountLoader(
-	accounts clientsetv1alpha1.AccountsV1alpha1Interface,
-	core clientsetv1.CoreV1Interface,
-) *SystemAccountLoader {
+func NewSystemAccountLoader(reader client.Reader) *SystemAccountLoader {
 	return &SystemAccountLoader{
-		accounts: accounts,
-		core:     core,
+		reader: reader,
 	}
 }
 
@@ -33,8 +37,9 @@ func (s *SystemAccountLoader) Load(ctx context.Context, operator *v1alpha1.Opera
 		return nil, fmt.Errorf("operator %s/%s does not have a resolved system account", operator.Namespace, operator.Name)
 	}
 
-	account, err := s.accounts.Accounts(operator.Namespace).
-		Get(ctx, operator.Status.ResolvedSystemAccount.Name, v1.GetOptions{})
+	account := new(v1alpha1.Account)
+
+	err = s.reader.Get(ctx, client.ObjectKey{Namespace: operator.Namespace, Name: operator.Status.ResolvedSystemAccount.Name}, account)
 	if err != nil {
 		return nil, err
 	}
@@ -43,18 +48,20 @@ func (s *SystemAccountLoader) Load(ctx context.Context, operator *v1alpha1.Opera
 		return nil, fmt.Errorf("system account %s/%s does not have a keypair", account.Namespace, account.Name)
 	}
 
-	seedSecret, err := s.core.Secrets(account.Namespace).Get(ctx, account.Status.KeyPair.SeedSecretName, v1.GetOptions{})
+	seedSecret := new(v1.Secret)
+
+	err = s.reader.Get(ctx, client.ObjectKey{Namespace: account.Namespace, Name: account.Status.KeyPair.SeedSecretName}, seedSecret)
 	if err != nil {
 		return nil, err
 	}
 
-	seedBytes, ok := seedSecret.Data[v1alpha1.NatsSecretSeedKey]
+	seedBytes, ok := seedSecret.Data[account.Status.KeyPair.GetSeedSecretKey()]
 	if !ok {
 		return nil, fmt.Errorf(
 			"secret %s/%s is invalid, missing field: %s",
 			seedSecret.Namespace,
 			seedSecret.Name,
-			v1alpha1.NatsSecretSeedKey)
+			account.Status.KeyPair.GetSeedSecretKey())
 	}
 
 	return seedBytes, nil
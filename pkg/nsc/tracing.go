@@ -0,0 +1,86 @@
+package nsc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/nats-io/nats.go"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/versori-oss/nats-account-operator/pkg/nsc"
+
+// subjectTemplate returns the span name for requests to subjects built from format, replacing each verb with a wildcard
+// so that span names do not contain public keys.
+func subjectTemplate(format string) string {
+	return strings.ReplaceAll(format, "%s", "*")
+}
+
+// startRequestSpan starts a client span called name for a request published to subj, and returns a message for subj
+// carrying the trace context in its headers so the request can be correlated with the account server. Requests to
+// RequestSubjectClaimsDelete carry no headers, since nats-server decodes the whole message, headers included, as the
+// signed delete request.
+func startRequestSpan(ctx context.Context, name, subj string, data []byte) (context.Context, trace.Span, *nats.Msg) {
+	ctx, span := otel.Tracer(tracerName).Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "nats"),
+			attribute.String("messaging.destination.name", subj),
+			attribute.Int("messaging.message.body.size", len(data)),
+		),
+	)
+
+	msg := nats.NewMsg(subj)
+	msg.Data = data
+
+	if subj != RequestSubjectClaimsDelete {
+		otel.GetTextMapPropagator().Inject(ctx, headerCarrier(msg.Header))
+	}
+
+	return ctx, span, msg
+}
+
+// headerCarrier adapts nats.Header to a propagation.TextMapCarrier. Unlike propagation.HeaderCarrier keys are not
+// canonicalised, so the W3C traceparent header keeps its lowercase name.
+type headerCarrier nats.Header
+
+var _ propagation.TextMapCarrier = headerCarrier(nil)
+
+func (h headerCarrier) Get(key string) string {
+	return nats.Header(h).Get(key)
+}
+
+func (h headerCarrier) Set(key, value string) {
+	nats.Header(h).Set(key, value)
+}
+
+func (h headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(h))
+	for key := range h {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
+
+// endSpan records err on span, if any, and ends it.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
+// request sends a request to subj within a span called name and waits for the first reply.
+func (c *Client) request(ctx context.Context, name, subj string, data []byte) (resp *nats.Msg, err error) {
+	ctx, span, msg := startRequestSpan(ctx, name, subj, data)
+	defer func() { endSpan(span, err) }()
+
+	return c.conn.RequestMsgWithContext(ctx, msg)
+}
@@ -0,0 +1,142 @@
+package nsc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nkeys"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestClient_TracePropagation(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	srv, err := server.NewServer(&server.Options{Host: "127.0.0.1", Port: -1, NoLog: true, NoSigs: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv.Start()
+	t.Cleanup(srv.Shutdown)
+
+	if !srv.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats-server not ready for connections")
+	}
+
+	conn, err := nats.Connect(srv.ClientURL())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	traceparents := make(chan string, 1)
+
+	if _, err := conn.Subscribe(RequestSubjectClaimsUpdate, func(msg *nats.Msg) {
+		traceparents <- msg.Header.Get("traceparent")
+
+		_ = msg.Respond([]byte(`{}`))
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ctx, parent := provider.Tracer("test").Start(ctx, "reconcile")
+
+	c := &Client{conn: conn}
+	if err := c.Push(ctx, "jwt"); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	parent.End()
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 || spans[0].Name != RequestSubjectClaimsUpdate {
+		t.Fatalf("spans = %v, want the %s request span and its parent", spans.Snapshots(), RequestSubjectClaimsUpdate)
+	}
+
+	request := spans[0]
+	if request.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("request span parent = %s, want %s", request.Parent.SpanID(), parent.SpanContext().SpanID())
+	}
+
+	want := "00-" + request.SpanContext.TraceID().String() + "-" + request.SpanContext.SpanID().String() + "-01"
+	if got := <-traceparents; got != want {
+		t.Errorf("traceparent header = %q, want %q", got, want)
+	}
+}
+
+// TestClient_TracePropagation_FullResolver pushes and deletes an account with trace propagation enabled, the delete
+// handler of nats-server decodes the whole message as a JWT so it fails if trace headers are added.
+func TestClient_TracePropagation_FullResolver(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+
+	prevProvider, prevPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	t.Cleanup(func() {
+		otel.SetTracerProvider(prevProvider)
+		otel.SetTextMapPropagator(prevPropagator)
+	})
+
+	operatorKP, url, sysSeed := startFullResolver(t)
+	_, accountPub, _ := newKeyPair(t, nkeys.CreateAccount)
+
+	publisher, err := NATSAccountServer{}.Connect(url, operatorKP, sysSeed)
+	if err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+
+	defer publisher.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ctx, parent := otel.Tracer("test").Start(ctx, "reconcile")
+	defer parent.End()
+
+	if err := publisher.Push(ctx, encodeClaims(t, jwt.NewAccountClaims(accountPub), operatorKP)); err != nil {
+		t.Fatalf("Push() error = %v", err)
+	}
+
+	if err := publisher.Delete(ctx, accountPub); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+
+	if _, err := publisher.Lookup(ctx, accountPub); !errors.Is(err, ErrAccountNotFound) {
+		t.Errorf("Lookup() after Delete() error = %v, want ErrAccountNotFound", err)
+	}
+
+	var deletes int
+
+	for _, span := range exporter.GetSpans() {
+		if span.Name == RequestSubjectClaimsDelete {
+			deletes++
+		}
+	}
+
+	if deletes != 1 {
+		t.Errorf("%d %s spans, want 1", deletes, RequestSubjectClaimsDelete)
+	}
+}
@@ -0,0 +1,219 @@
+package nsc
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/versori-oss/nats-account-operator/pkg/nsc/internal"
+)
+
+const (
+	// RequestSubjectAccountConnzFormat is formatted with the public key of the account to query.
+	RequestSubjectAccountConnzFormat = "$SYS.REQ.ACCOUNT.%s.CONNZ"
+	// RequestSubjectAccountStatzFormat is formatted with the public key of the account to query.
+	RequestSubjectAccountStatzFormat = "$SYS.REQ.ACCOUNT.%s.STATZ"
+	// RequestSubjectAccountJSZFormat is formatted with the public key of the account to query.
+	RequestSubjectAccountJSZFormat = "$SYS.REQ.ACCOUNT.%s.JSZ"
+)
+
+// connzPageSize is the number of connections requested from each server per CONNZ request.
+const connzPageSize = 1024
+
+// usageQuietPeriod is how long to wait for further replies after the first, every server in the cluster replies to the
+// account usage requests.
+const usageQuietPeriod = 250 * time.Millisecond
+
+// AccountUsage is the usage of an account aggregated across every server which replied.
+type AccountUsage struct {
+	Connections   int
+	LeafNodes     int
+	Subscriptions int
+	// LastActivity is the most recent activity of any client connected to the account, it is zero when there are
+	// no clients.
+	LastActivity time.Time
+	// JetStream is nil when JetStream is not enabled for the account.
+	JetStream *JetStreamUsage
+}
+
+// JetStreamUsage is the JetStream storage used by an account, in bytes.
+type JetStreamUsage struct {
+	Memory  uint64
+	Storage uint64
+}
+
+// Usage queries the STATZ, CONNZ and JSZ endpoints of the account with the public key subject. Connection and
+// subscription counts are summed across servers, JetStream storage is replicated so the largest value reported is
+// used. ctx must have a deadline.
+func (c *Client) Usage(ctx context.Context, subject string) (*AccountUsage, error) {
+	var usage AccountUsage
+
+	statz, err := requestAll[internal.StatzResponse](ctx, c, RequestSubjectAccountStatzFormat, subject,
+		map[string]any{"accounts": []string{subject}, "include_unused": true})
+	if err != nil {
+		return nil, fmt.Errorf("nats statz failed: %w", err)
+	}
+
+	for _, reply := range statz {
+		if reply.Error != nil {
+			return nil, fmt.Errorf("nats statz failed: %s", reply.Error.Description)
+		}
+
+		if reply.Data == nil {
+			continue
+		}
+
+		for _, acc := range reply.Data.Accounts {
+			if acc.Account != subject {
+				continue
+			}
+
+			usage.Connections += acc.Conns
+			usage.LeafNodes += acc.LeafNodes
+			usage.Subscriptions += int(acc.NumSubs)
+		}
+	}
+
+	connz, err := requestAll[internal.ConnzResponse](ctx, c, RequestSubjectAccountConnzFormat, subject,
+		map[string]any{"sort": "last", "limit": 1})
+	if err != nil {
+		return nil, fmt.Errorf("nats connz failed: %w", err)
+	}
+
+	for _, reply := range connz {
+		if reply.Error != nil {
+			return nil, fmt.Errorf("nats connz failed: %s", reply.Error.Description)
+		}
+
+		if reply.Data == nil {
+			continue
+		}
+
+		for _, conn := range reply.Data.Connections {
+			if conn.LastActivity.After(usage.LastActivity) {
+				usage.LastActivity = conn.LastActivity
+			}
+		}
+	}
+
+	jsz, err := requestAll[internal.JSZResponse](ctx, c, RequestSubjectAccountJSZFormat, subject, nil)
+	if err != nil {
+		return nil, fmt.Errorf("nats jsz failed: %w", err)
+	}
+
+	for _, reply := range jsz {
+		// servers without JetStream, or where it is not enabled for the account, reply with an error
+		if reply.Error != nil || reply.Data == nil {
+			continue
+		}
+
+		if usage.JetStream == nil {
+			usage.JetStream = &JetStreamUsage{}
+		}
+
+		usage.JetStream.Memory = max(usage.JetStream.Memory, reply.Data.Memory)
+		usage.JetStream.Storage = max(usage.JetStream.Storage, reply.Data.Store)
+	}
+
+	return &usage, nil
+}
+
+// ConnectionsByUser pages through the CONNZ endpoint of the account with the public key subject, counting the open
+// connections of each user. Every server pages its own connections, so paging continues until no server returns a full
+// page. ctx must have a deadline.
+func (c *Client) ConnectionsByUser(ctx context.Context, subject string) (map[string]int, error) {
+	users := make(map[string]int)
+
+	for offset := 0; ; offset += connzPageSize {
+		connz, err := requestAll[internal.ConnzResponse](ctx, c, RequestSubjectAccountConnzFormat, subject,
+			map[string]any{"auth": true, "offset": offset, "limit": connzPageSize})
+		if err != nil {
+			return nil, fmt.Errorf("nats connz failed: %w", err)
+		}
+
+		more := false
+
+		for _, reply := range connz {
+			if reply.Error != nil {
+				return nil, fmt.Errorf("nats connz failed: %s", reply.Error.Description)
+			}
+
+			if reply.Data == nil {
+				continue
+			}
+
+			for _, conn := range reply.Data.Connections {
+				if conn.AuthorizedUser != "" {
+					users[conn.AuthorizedUser]++
+				}
+			}
+
+			more = more || len(reply.Data.Connections) == connzPageSize
+		}
+
+		if !more {
+			return users, nil
+		}
+	}
+}
+
+// requestAll publishes a request to the subject formatted from subjFormat and subject, and decodes every reply received
+// until ctx is done, or until no further replies arrive within usageQuietPeriod of the last one. An error is returned if
+// no server replies.
+func requestAll[T any](ctx context.Context, c *Client, subjFormat, subject string, body any) (_ []T, err error) {
+	var data []byte
+
+	if body != nil {
+		data, err = json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to json marshal request: %w", err)
+		}
+	}
+
+	subj := fmt.Sprintf(subjFormat, subject)
+
+	ctx, span, msg := startRequestSpan(ctx, subjectTemplate(subjFormat), subj, data)
+	defer func() { endSpan(span, err) }()
+
+	msg.Reply = c.conn.NewRespInbox()
+
+	sub, err := c.conn.SubscribeSync(msg.Reply)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() { _ = sub.Unsubscribe() }()
+
+	if err := c.conn.PublishMsg(msg); err != nil {
+		return nil, err
+	}
+
+	var replies []T
+
+	for {
+		waitCtx, cancel := ctx, context.CancelFunc(func() {})
+		if len(replies) > 0 {
+			waitCtx, cancel = context.WithTimeout(ctx, usageQuietPeriod)
+		}
+
+		msg, err := sub.NextMsgWithContext(waitCtx)
+
+		cancel()
+
+		if err != nil {
+			if len(replies) > 0 {
+				return replies, nil
+			}
+
+			return nil, err
+		}
+
+		var reply T
+		if err := json.Unmarshal(msg.Data, &reply); err != nil {
+			return nil, fmt.Errorf("failed to json unmarshal response: %w", err)
+		}
+
+		replies = append(replies, reply)
+	}
+}
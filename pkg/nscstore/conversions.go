@@ -0,0 +1,256 @@
+package nscstore
+
+import (
+	"github.com/nats-io/jwt/v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+)
+
+// The conversions in this file are the inverse of those in pkg/nsc. Any claim which has no equivalent field in the
+// v1alpha1 API is reported to warn, prefixed with the name of the field, so the caller can attribute it to a resource.
+
+var (
+	// defaultNatsLimits and defaultAccountLimits match the limits set by jwt.NewAccountClaims and jwt.NewUserClaims.
+	defaultNatsLimits = jwt.NatsLimits{Subs: jwt.NoLimit, Data: jwt.NoLimit, Payload: jwt.NoLimit}
+
+	defaultAccountLimits = jwt.AccountLimits{
+		Imports:         jwt.NoLimit,
+		Exports:         jwt.NoLimit,
+		WildcardExports: true,
+		Conn:            jwt.NoLimit,
+		LeafNodeConn:    jwt.NoLimit,
+	}
+)
+
+func convertFromNATSExportType(t jwt.ExportType) v1alpha1.ImportExportType {
+	switch t {
+	case jwt.Stream:
+		return v1alpha1.ImportExportTypeStream
+	case jwt.Service:
+		return v1alpha1.ImportExportTypeService
+	default:
+		return ""
+	}
+}
+
+func convertFromNATSResponseType(t jwt.ResponseType) v1alpha1.ResponseType {
+	switch t {
+	case jwt.ResponseTypeSingleton:
+		return v1alpha1.ResponseTypeSingleton
+	case jwt.ResponseTypeStream:
+		return v1alpha1.ResponseTypeStream
+	case jwt.ResponseTypeChunked:
+		return v1alpha1.ResponseTypeChunked
+	default:
+		return ""
+	}
+}
+
+func convertFromNATSImports(in jwt.Imports, warn func(format string, args ...any)) []v1alpha1.AccountImport {
+	if len(in) == 0 {
+		return nil
+	}
+
+	out := make([]v1alpha1.AccountImport, 0, len(in))
+
+	for _, i := range in {
+		if i.LocalSubject != "" {
+			warn("imports[%s].localSubject %q dropped", i.Subject, i.LocalSubject)
+		}
+
+		if i.Share {
+			warn("imports[%s].share dropped", i.Subject)
+		}
+
+		if i.AllowTrace {
+			warn("imports[%s].allowTrace dropped", i.Subject)
+		}
+
+		out = append(out, v1alpha1.AccountImport{
+			Name:    i.Name,
+			Subject: string(i.Subject),
+			Account: i.Account,
+			Token:   i.Token,
+			To:      string(i.To),
+			Type:    convertFromNATSExportType(i.Type),
+		})
+	}
+
+	return out
+}
+
+func convertFromNATSExports(in jwt.Exports, warn func(format string, args ...any)) []v1alpha1.AccountExport {
+	if len(in) == 0 {
+		return nil
+	}
+
+	out := make([]v1alpha1.AccountExport, 0, len(in))
+
+	for _, e := range in {
+		if len(e.Revocations) > 0 {
+			warn("exports[%s].revocations dropped", e.Subject)
+		}
+
+		if e.ResponseThreshold != 0 {
+			warn("exports[%s].responseThreshold dropped", e.Subject)
+		}
+
+		if e.Advertise {
+			warn("exports[%s].advertise dropped", e.Subject)
+		}
+
+		if e.AllowTrace {
+			warn("exports[%s].allowTrace dropped", e.Subject)
+		}
+
+		if e.Description != "" || e.InfoURL != "" {
+			warn("exports[%s].description and infoURL dropped", e.Subject)
+		}
+
+		export := v1alpha1.AccountExport{
+			Name:                 e.Name,
+			Subject:              string(e.Subject),
+			Type:                 convertFromNATSExportType(e.Type),
+			TokenReq:             e.TokenReq,
+			ResponseType:         convertFromNATSResponseType(e.ResponseType),
+			AccountTokenPosition: e.AccountTokenPosition,
+		}
+
+		if e.Latency != nil {
+			export.ServiceLatency = &v1alpha1.AccountServiceLatency{
+				Sampling: int(e.Latency.Sampling),
+				Results:  string(e.Latency.Results),
+			}
+		}
+
+		out = append(out, export)
+	}
+
+	return out
+}
+
+// convertFromNATSNatsLimits only sets the limits which differ from defaults, so unset limits continue to follow the
+// defaults of pkg/nsc.
+func convertFromNATSNatsLimits(in, defaults jwt.NatsLimits) v1alpha1.NatsLimits {
+	return v1alpha1.NatsLimits{
+		Subs:    ptrIfChanged(in.Subs, defaults.Subs),
+		Data:    ptrIfChanged(in.Data, defaults.Data),
+		Payload: ptrIfChanged(in.Payload, defaults.Payload),
+	}
+}
+
+func convertFromNATSAccountLimits(in, defaults jwt.AccountLimits) v1alpha1.AccountLimits {
+	return v1alpha1.AccountLimits{
+		Imports:         ptrIfChanged(in.Imports, defaults.Imports),
+		Exports:         ptrIfChanged(in.Exports, defaults.Exports),
+		WildcardExports: ptrIfChanged(in.WildcardExports, defaults.WildcardExports),
+		DisallowBearer:  in.DisallowBearer,
+		Conn:            ptrIfChanged(in.Conn, defaults.Conn),
+		LeafNodeConn:    ptrIfChanged(in.LeafNodeConn, defaults.LeafNodeConn),
+	}
+}
+
+func convertFromNATSJetStreamLimits(in jwt.JetStreamLimits) v1alpha1.JetStreamLimits {
+	return v1alpha1.JetStreamLimits{
+		MemoryStorage:        in.MemoryStorage,
+		DiskStorage:          in.DiskStorage,
+		Streams:              in.Streams,
+		Consumer:             in.Consumer,
+		MaxAckPending:        in.MaxAckPending,
+		MemoryMaxStreamBytes: in.MemoryMaxStreamBytes,
+		DiskMaxStreamBytes:   in.DiskMaxStreamBytes,
+		MaxBytesRequired:     in.MaxBytesRequired,
+	}
+}
+
+// convertFromNATSOperatorLimits returns nil when the limits match the defaults for a new Account.
+func convertFromNATSOperatorLimits(in jwt.OperatorLimits) *v1alpha1.OperatorLimits {
+	out := &v1alpha1.OperatorLimits{
+		Nats:      convertFromNATSNatsLimits(in.NatsLimits, defaultNatsLimits),
+		Account:   convertFromNATSAccountLimits(in.AccountLimits, defaultAccountLimits),
+		JetStream: convertFromNATSJetStreamLimits(in.JetStreamLimits),
+	}
+
+	if len(in.JetStreamTieredLimits) > 0 {
+		out.TieredJetStream = make(map[string]v1alpha1.JetStreamLimits, len(in.JetStreamTieredLimits))
+
+		for tier, limits := range in.JetStreamTieredLimits {
+			out.TieredJetStream[tier] = convertFromNATSJetStreamLimits(limits)
+		}
+	}
+
+	empty := v1alpha1.OperatorLimits{}
+	if out.Nats == empty.Nats && out.Account == empty.Account && out.JetStream == empty.JetStream && out.TieredJetStream == nil {
+		return nil
+	}
+
+	return out
+}
+
+func convertFromNATSPermissions(in jwt.Permissions) *v1alpha1.UserPermissions {
+	out := &v1alpha1.UserPermissions{
+		Pub: v1alpha1.Permission{Allow: in.Pub.Allow, Deny: in.Pub.Deny},
+		Sub: v1alpha1.Permission{Allow: in.Sub.Allow, Deny: in.Sub.Deny},
+	}
+
+	if in.Resp != nil {
+		out.Resp = &v1alpha1.RespPermission{
+			MaxMsgs: in.Resp.MaxMsgs,
+			TTL:     metav1.Duration{Duration: in.Resp.Expires},
+		}
+	}
+
+	if len(out.Pub.Allow)+len(out.Pub.Deny)+len(out.Sub.Allow)+len(out.Sub.Deny) == 0 && out.Resp == nil {
+		return nil
+	}
+
+	return out
+}
+
+func convertFromNATSUserLimits(in jwt.Limits) v1alpha1.UserLimits {
+	out := v1alpha1.UserLimits{
+		NatsLimits: convertFromNATSNatsLimits(in.NatsLimits, defaultNatsLimits),
+		Src:        in.Src,
+		Locale:     in.Locale,
+	}
+
+	for _, t := range in.Times {
+		out.Times = append(out.Times, v1alpha1.StartEndTime{Start: t.Start, End: t.End})
+	}
+
+	return out
+}
+
+func convertFromNATSConnectionTypes(in jwt.StringList, warn func(format string, args ...any)) []v1alpha1.ConnectionType {
+	var out []v1alpha1.ConnectionType
+
+	for _, ct := range in {
+		switch ct {
+		case jwt.ConnectionTypeStandard:
+			out = append(out, v1alpha1.ConnectionTypeStandard)
+		case jwt.ConnectionTypeWebsocket:
+			out = append(out, v1alpha1.ConnectionTypeWebsocket)
+		case jwt.ConnectionTypeLeafnode:
+			out = append(out, v1alpha1.ConnectionTypeLeafnode)
+		case jwt.ConnectionTypeLeafnodeWS:
+			out = append(out, v1alpha1.ConnectionTypeLeafnodeWS)
+		case jwt.ConnectionTypeMqtt:
+			out = append(out, v1alpha1.ConnectionTypeMqtt)
+		case jwt.ConnectionTypeMqttWS:
+			out = append(out, v1alpha1.ConnectionTypeMqttWS)
+		default:
+			warn("allowedConnectionTypes %q dropped", ct)
+		}
+	}
+
+	return out
+}
+
+func ptrIfChanged[T comparable](v, def T) *T {
+	if v == def {
+		return nil
+	}
+
+	return &v
+}
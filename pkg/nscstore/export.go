@@ -0,0 +1,396 @@
+package nscstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	"github.com/nats-io/jwt/v2"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	accountsv1alpha1 "github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned/typed/accounts/v1alpha1"
+)
+
+// ExportOptions configures how resources are written to an nsc store.
+type ExportOptions struct {
+	// Namespace restricts the export to resources in this namespace, all namespaces are exported when empty.
+	Namespace string
+
+	// OmitSeeds skips writing seeds and creds to the keystore, so the export only contains public information and can
+	// be shared with read-only auditors.
+	OmitSeeds bool
+
+	// Force allows writing into a store directory which already contains entities, overwriting any with the same name.
+	Force bool
+}
+
+// ErrStoreNotEmpty is returned by Export when the stores directory already contains entities and ExportOptions.Force is
+// not set.
+var ErrStoreNotEmpty = errors.New("nsc store is not empty")
+
+// ExportResult summarises the entities written to the store.
+type ExportResult struct {
+	Operators   int
+	Accounts    int
+	Users       int
+	SigningKeys int
+
+	// Warnings describe resources which were skipped, typically because they are not yet Ready.
+	Warnings []string
+}
+
+// Exporter writes the JWTs and seeds of managed resources to an nsc store.
+type Exporter struct {
+	accounts accountsv1alpha1.AccountsV1alpha1Interface
+	core     corev1.CoreV1Interface
+}
+
+// NewExporter returns an Exporter which reads resources through the generated accounts clientset, and their Secrets
+// through the core clientset.
+func NewExporter(accounts accountsv1alpha1.AccountsV1alpha1Interface, core corev1.CoreV1Interface) *Exporter {
+	return &Exporter{
+		accounts: accounts,
+		core:     core,
+	}
+}
+
+// Export writes every Operator, Account and User to the store in the nsc layout, along with the seeds of all
+// Operators, Accounts, Users and SigningKeys and the creds of all Users unless opts.OmitSeeds is set. Entities are
+// named by the name claim of their JWT, which the controller sets to the resource name, so the export fails if two
+// resources of the same kind would be written to the same path, such as same-named Accounts in different namespaces.
+//
+// ErrStoreNotEmpty is returned without writing anything if the stores directory is not empty, unless opts.Force is set.
+// Seeds are named by their public key, so the keystore may be shared with existing stores.
+func (e *Exporter) Export(ctx context.Context, store Store, opts ExportOptions) (*ExportResult, error) {
+	if !opts.Force {
+		if err := checkEmpty(store.StoresDir); err != nil {
+			return nil, err
+		}
+	}
+
+	ex := &export{
+		Exporter: e,
+		ctx:      ctx,
+		store:    store,
+		opts:     opts,
+		result:   &ExportResult{},
+		written:  make(map[string]string),
+	}
+
+	if err := ex.run(); err != nil {
+		return nil, err
+	}
+
+	return ex.result, nil
+}
+
+type export struct {
+	*Exporter
+
+	ctx    context.Context
+	store  Store
+	opts   ExportOptions
+	result *ExportResult
+
+	// written maps each entity path written by this export to the resource it was written for.
+	written map[string]string
+}
+
+// checkEmpty returns ErrStoreNotEmpty if dir exists and contains any entries.
+func checkEmpty(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+
+		return fmt.Errorf("failed to read stores directory: %w", err)
+	}
+
+	if len(entries) > 0 {
+		return fmt.Errorf("%w: %s", ErrStoreNotEmpty, dir)
+	}
+
+	return nil
+}
+
+// writeEntity writes data to path for resource, failing if another resource was already written to path by this
+// export.
+func (ex *export) writeEntity(path string, data []byte, resource string) error {
+	if prev, ok := ex.written[path]; ok {
+		return fmt.Errorf("%s and %s would both be written to %s, export their namespaces separately", prev, resource, path)
+	}
+
+	ex.written[path] = resource
+
+	return writeFile(path, data)
+}
+
+func (ex *export) warnf(format string, args ...any) {
+	ex.result.Warnings = append(ex.result.Warnings, fmt.Sprintf(format, args...))
+}
+
+func (ex *export) run() error {
+	operators, err := ex.accounts.Operators(ex.opts.Namespace).List(ex.ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list operators: %w", err)
+	}
+
+	accounts, err := ex.accounts.Accounts(ex.opts.Namespace).List(ex.ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	users, err := ex.accounts.Users(ex.opts.Namespace).List(ex.ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	signingKeys, err := ex.accounts.SigningKeys(ex.opts.Namespace).List(ex.ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list signing keys: %w", err)
+	}
+
+	for i := range operators.Items {
+		operator := &operators.Items[i]
+
+		operatorName, err := ex.exportOperator(operator)
+		if err != nil {
+			return fmt.Errorf("operator %s/%s: %w", operator.Namespace, operator.Name, err)
+		}
+
+		if operatorName == "" {
+			continue
+		}
+
+		for j := range accounts.Items {
+			account := &accounts.Items[j]
+
+			if ref := account.Status.OperatorRef; ref == nil || ref.Namespace != operator.Namespace || ref.Name != operator.Name {
+				continue
+			}
+
+			accountName, err := ex.exportAccount(operatorName, account)
+			if err != nil {
+				return fmt.Errorf("account %s/%s: %w", account.Namespace, account.Name, err)
+			}
+
+			if accountName == "" {
+				continue
+			}
+
+			for k := range users.Items {
+				user := &users.Items[k]
+
+				if ref := user.Status.AccountRef; ref == nil || ref.Namespace != account.Namespace || ref.Name != account.Name {
+					continue
+				}
+
+				if err := ex.exportUser(operatorName, accountName, user); err != nil {
+					return fmt.Errorf("user %s/%s: %w", user.Namespace, user.Name, err)
+				}
+			}
+		}
+	}
+
+	if ex.opts.OmitSeeds {
+		return nil
+	}
+
+	for i := range signingKeys.Items {
+		sk := &signingKeys.Items[i]
+
+		ok, err := ex.writeSeed(sk.Namespace, sk.Status.KeyPair)
+		if err != nil {
+			return fmt.Errorf("signing key %s/%s: %w", sk.Namespace, sk.Name, err)
+		}
+
+		if !ok {
+			ex.warnf("SigningKey %s/%s: no key pair, skipping", sk.Namespace, sk.Name)
+
+			continue
+		}
+
+		ex.result.SigningKeys++
+	}
+
+	return nil
+}
+
+// exportOperator writes the operator JWT and seed, returning the name of the operator in the store or an empty string
+// if the operator was skipped.
+func (ex *export) exportOperator(operator *v1alpha1.Operator) (string, error) {
+	token, err := ex.readJWT(operator.Namespace, operator.Spec.JWTSecretName)
+	if err != nil {
+		return "", err
+	}
+
+	if token == "" {
+		ex.warnf("Operator %s/%s: JWT secret not found, skipping", operator.Namespace, operator.Name)
+
+		return "", nil
+	}
+
+	claims, err := jwt.DecodeOperatorClaims(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode operator JWT: %w", err)
+	}
+
+	name := claims.Name
+	if err := checkEntityName(name); err != nil {
+		return "", fmt.Errorf("Operator %s/%s: %w", operator.Namespace, operator.Name, err)
+	}
+
+	if err := ex.writeEntity(ex.store.operatorJWTPath(name), []byte(token),
+		fmt.Sprintf("Operator %s/%s", operator.Namespace, operator.Name)); err != nil {
+		return "", err
+	}
+
+	if err := writeStoreInfo(ex.store.operatorDir(name), name); err != nil {
+		return "", err
+	}
+
+	// the seed of an Operator with an offline identity is never in the cluster, Status.KeyPair only holds the public
+	// key.
+	if !ex.opts.OmitSeeds && operator.Spec.OfflineIdentity == nil {
+		if _, err := ex.writeSeed(operator.Namespace, operator.Status.KeyPair); err != nil {
+			return "", err
+		}
+	}
+
+	ex.result.Operators++
+
+	return name, nil
+}
+
+func (ex *export) exportAccount(operatorName string, account *v1alpha1.Account) (string, error) {
+	token, err := ex.readJWT(account.Namespace, account.Spec.JWTSecretName)
+	if err != nil {
+		return "", err
+	}
+
+	if token == "" {
+		ex.warnf("Account %s/%s: JWT secret not found, skipping", account.Namespace, account.Name)
+
+		return "", nil
+	}
+
+	claims, err := jwt.DecodeAccountClaims(token)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode account JWT: %w", err)
+	}
+
+	name := claims.Name
+	if err := checkEntityName(name); err != nil {
+		return "", fmt.Errorf("Account %s/%s: %w", account.Namespace, account.Name, err)
+	}
+
+	if err := ex.writeEntity(ex.store.accountJWTPath(operatorName, name), []byte(token),
+		fmt.Sprintf("Account %s/%s", account.Namespace, account.Name)); err != nil {
+		return "", err
+	}
+
+	if !ex.opts.OmitSeeds {
+		if _, err := ex.writeSeed(account.Namespace, account.Status.KeyPair); err != nil {
+			return "", err
+		}
+	}
+
+	ex.result.Accounts++
+
+	return name, nil
+}
+
+func (ex *export) exportUser(operatorName, accountName string, user *v1alpha1.User) error {
+	token, err := ex.readJWT(user.Namespace, user.Spec.JWTSecretName)
+	if err != nil {
+		return err
+	}
+
+	if token == "" {
+		ex.warnf("User %s/%s: JWT secret not found, skipping", user.Namespace, user.Name)
+
+		return nil
+	}
+
+	claims, err := jwt.DecodeUserClaims(token)
+	if err != nil {
+		return fmt.Errorf("failed to decode user JWT: %w", err)
+	}
+
+	name := claims.Name
+	if err := checkEntityName(name); err != nil {
+		return fmt.Errorf("User %s/%s: %w", user.Namespace, user.Name, err)
+	}
+
+	if err := ex.writeEntity(ex.store.userJWTPath(operatorName, accountName, name), []byte(token),
+		fmt.Sprintf("User %s/%s", user.Namespace, user.Name)); err != nil {
+		return err
+	}
+
+	if !ex.opts.OmitSeeds {
+		if _, err := ex.writeSeed(user.Namespace, user.Status.KeyPair); err != nil {
+			return err
+		}
+
+		secret, err := ex.core.Secrets(user.Namespace).Get(ex.ctx, user.Spec.CredentialsSecretName, metav1.GetOptions{})
+		if err != nil {
+			ex.warnf("User %s/%s: failed to get credentials secret: %s", user.Namespace, user.Name, err)
+		} else if creds, ok := secret.Data[v1alpha1.NatsSecretCredsKey]; ok {
+			if err := writeFile(ex.store.credsPath(operatorName, accountName, name), creds); err != nil {
+				return err
+			}
+		}
+	}
+
+	ex.result.Users++
+
+	return nil
+}
+
+// readJWT returns the JWT from the named Secret, or an empty string if the Secret does not exist.
+func (ex *export) readJWT(namespace, name string) (string, error) {
+	secret, err := ex.core.Secrets(namespace).Get(ex.ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return "", nil
+		}
+
+		return "", fmt.Errorf("failed to get JWT secret %s: %w", name, err)
+	}
+
+	return string(secret.Data[v1alpha1.NatsSecretJWTKey]), nil
+}
+
+// writeSeed copies the seed referenced by kp into the keystore, returning false if kp is nil.
+func (ex *export) writeSeed(namespace string, kp *v1alpha1.KeyPair) (bool, error) {
+	if kp == nil || kp.SeedSecretName == "" {
+		return false, nil
+	}
+
+	secret, err := ex.core.Secrets(namespace).Get(ex.ctx, kp.SeedSecretName, metav1.GetOptions{})
+	if err != nil {
+		return false, fmt.Errorf("failed to get seed secret %s: %w", kp.SeedSecretName, err)
+	}
+
+	seed, ok := secret.Data[kp.GetSeedSecretKey()]
+	if !ok {
+		return false, fmt.Errorf("seed secret %s is missing key %s", kp.SeedSecretName, kp.GetSeedSecretKey())
+	}
+
+	return true, writeFile(ex.store.keyPath(kp.PublicKey), seed)
+}
+
+func writeFile(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,369 @@
+package nscstore
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	accountsfake "github.com/versori-oss/nats-account-operator/pkg/generated/clientset/versioned/fake"
+)
+
+// testCluster builds the resources and Secrets the controller would have created for an Operator, Account, User and
+// SigningKey.
+type testCluster struct {
+	t       *testing.T
+	secrets []runtime.Object
+	objects []runtime.Object
+}
+
+func (c *testCluster) keyPair(name string, create func() (nkeys.KeyPair, error)) (nkeys.KeyPair, *v1alpha1.KeyPair) {
+	kp, err := create()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	pub, err := kp.PublicKey()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	seed, err := kp.Seed()
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	c.secret(name+"-seed", map[string][]byte{v1alpha1.NatsSecretSeedKey: seed})
+
+	return kp, &v1alpha1.KeyPair{PublicKey: pub, SeedSecretName: name + "-seed"}
+}
+
+func (c *testCluster) secret(name string, data map[string][]byte) {
+	c.secrets = append(c.secrets, &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "nats"},
+		Data:       data,
+	})
+}
+
+func (c *testCluster) jwt(name string, claims jwt.Claims, kp nkeys.KeyPair) string {
+	token, err := claims.Encode(kp)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+
+	c.secret(name+"-jwt", map[string][]byte{v1alpha1.NatsSecretJWTKey: []byte(token)})
+
+	return token
+}
+
+func TestExporter_Export(t *testing.T) {
+	c := &testCluster{t: t}
+
+	operatorKP, operatorKeyPair := c.keyPair("operator", nkeys.CreateOperator)
+	_, skKeyPair := c.keyPair("operator-sk", nkeys.CreateOperator)
+	accountKP, accountKeyPair := c.keyPair("account", nkeys.CreateAccount)
+	_, userKeyPair := c.keyPair("user", nkeys.CreateUser)
+
+	oc := jwt.NewOperatorClaims(operatorKeyPair.PublicKey)
+	oc.Name = "operator"
+	oc.SystemAccount = accountKeyPair.PublicKey
+	oc.SigningKeys.Add(skKeyPair.PublicKey)
+	c.jwt("operator", oc, operatorKP)
+
+	ac := jwt.NewAccountClaims(accountKeyPair.PublicKey)
+	ac.Name = "account"
+	c.jwt("account", ac, operatorKP)
+
+	uc := jwt.NewUserClaims(userKeyPair.PublicKey)
+	uc.Name = "user"
+	ujwt := c.jwt("user", uc, accountKP)
+
+	c.secret("user-creds", map[string][]byte{v1alpha1.NatsSecretCredsKey: []byte("creds:" + ujwt)})
+
+	meta := func(name string) metav1.ObjectMeta {
+		return metav1.ObjectMeta{Name: name, Namespace: "nats"}
+	}
+
+	c.objects = append(c.objects,
+		&v1alpha1.Operator{
+			ObjectMeta: meta("operator"),
+			Spec:       v1alpha1.OperatorSpec{JWTSecretName: "operator-jwt"},
+			Status:     v1alpha1.OperatorStatus{KeyPair: operatorKeyPair},
+		},
+		&v1alpha1.Account{
+			ObjectMeta: meta("account"),
+			Spec:       v1alpha1.AccountSpec{JWTSecretName: "account-jwt"},
+			Status: v1alpha1.AccountStatus{
+				KeyPair:     accountKeyPair,
+				OperatorRef: &v1alpha1.InferredObjectReference{Namespace: "nats", Name: "operator"},
+			},
+		},
+		&v1alpha1.User{
+			ObjectMeta: meta("user"),
+			Spec:       v1alpha1.UserSpec{JWTSecretName: "user-jwt", CredentialsSecretName: "user-creds"},
+			Status: v1alpha1.UserStatus{
+				KeyPair:    userKeyPair,
+				AccountRef: &v1alpha1.InferredObjectReference{Namespace: "nats", Name: "account"},
+			},
+		},
+	)
+
+	tests := []struct {
+		name      string
+		omitSeeds bool
+	}{
+		{name: "with seeds"},
+		{name: "omit seeds", omitSeeds: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store := Store{StoresDir: filepath.Join(dir, "stores"), KeysDir: filepath.Join(dir, "keys")}
+
+			accounts := accountsfake.NewSimpleClientset(c.objects...).AccountsV1alpha1()
+
+			// the object tracker guesses the resource for SigningKey as "signingkeies", so it must be created through
+			// the client rather than passed to NewSimpleClientset.
+			_, err := accounts.SigningKeys("nats").Create(context.Background(), &v1alpha1.SigningKey{
+				ObjectMeta: meta("operator-sk"),
+				Status:     v1alpha1.SigningKeyStatus{KeyPair: skKeyPair},
+			}, metav1.CreateOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			exporter := NewExporter(accounts, k8sfake.NewSimpleClientset(c.secrets...).CoreV1())
+
+			result, err := exporter.Export(context.Background(), store, ExportOptions{Namespace: "nats", OmitSeeds: tt.omitSeeds})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if result.Operators != 1 || result.Accounts != 1 || result.Users != 1 {
+				t.Errorf("exported %+v, want 1 operator, account and user", result)
+			}
+
+			wantSigningKeys := 1
+			if tt.omitSeeds {
+				wantSigningKeys = 0
+			}
+
+			if result.SigningKeys != wantSigningKeys {
+				t.Errorf("exported %d signing key seeds, want %d", result.SigningKeys, wantSigningKeys)
+			}
+
+			for _, path := range []string{
+				store.operatorJWTPath("operator"),
+				filepath.Join(store.operatorDir("operator"), infoFile),
+				store.accountJWTPath("operator", "account"),
+				store.userJWTPath("operator", "account", "user"),
+			} {
+				if _, err := os.Stat(path); err != nil {
+					t.Errorf("expected %s to exist: %v", path, err)
+				}
+			}
+
+			for _, path := range []string{
+				store.keyPath(operatorKeyPair.PublicKey),
+				store.keyPath(skKeyPair.PublicKey),
+				store.keyPath(accountKeyPair.PublicKey),
+				store.keyPath(userKeyPair.PublicKey),
+				store.credsPath("operator", "account", "user"),
+			} {
+				_, err := os.Stat(path)
+				if tt.omitSeeds && err == nil {
+					t.Errorf("expected %s to be omitted", path)
+				}
+
+				if !tt.omitSeeds && err != nil {
+					t.Errorf("expected %s to exist: %v", path, err)
+				}
+			}
+
+			if tt.omitSeeds {
+				return
+			}
+
+			// the exported store should import back to the same public keys
+			imported, err := Import(store, ImportOptions{Namespace: "nats"})
+			if err != nil {
+				t.Fatalf("failed to import exported store: %v", err)
+			}
+
+			var users int
+
+			for _, obj := range imported.Objects {
+				if _, ok := obj.(*v1alpha1.User); ok {
+					users++
+				}
+			}
+
+			if users != 1 {
+				t.Errorf("imported %d users from exported store, want 1, warnings: %v", users, imported.Warnings)
+			}
+		})
+	}
+}
+
+func TestExporter_Export_Overwrite(t *testing.T) {
+	c := &testCluster{t: t}
+
+	operatorKP, operatorKeyPair := c.keyPair("operator", nkeys.CreateOperator)
+	_, ordersKeyPair := c.keyPair("orders", nkeys.CreateAccount)
+	_, copyKeyPair := c.keyPair("orders-copy", nkeys.CreateAccount)
+
+	oc := jwt.NewOperatorClaims(operatorKeyPair.PublicKey)
+	oc.Name = "operator"
+	c.jwt("operator", oc, operatorKP)
+
+	// both Accounts have the name claim "orders", as same-named Accounts in different namespaces would
+	for _, kp := range []*v1alpha1.KeyPair{ordersKeyPair, copyKeyPair} {
+		ac := jwt.NewAccountClaims(kp.PublicKey)
+		ac.Name = "orders"
+		c.jwt(strings.TrimSuffix(kp.SeedSecretName, "-seed"), ac, operatorKP)
+	}
+
+	account := func(name string, kp *v1alpha1.KeyPair) *v1alpha1.Account {
+		return &v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "nats"},
+			Spec:       v1alpha1.AccountSpec{JWTSecretName: name + "-jwt"},
+			Status: v1alpha1.AccountStatus{
+				KeyPair:     kp,
+				OperatorRef: &v1alpha1.InferredObjectReference{Namespace: "nats", Name: "operator"},
+			},
+		}
+	}
+
+	operator := &v1alpha1.Operator{
+		ObjectMeta: metav1.ObjectMeta{Name: "operator", Namespace: "nats"},
+		Spec:       v1alpha1.OperatorSpec{JWTSecretName: "operator-jwt"},
+		Status:     v1alpha1.OperatorStatus{KeyPair: operatorKeyPair},
+	}
+
+	tests := []struct {
+		name          string
+		existing      bool
+		force         bool
+		duplicate     bool
+		wantErr       error
+		wantErrSubstr string
+	}{
+		{name: "empty store"},
+		{name: "non-empty store", existing: true, wantErr: ErrStoreNotEmpty},
+		{name: "non-empty store with force", existing: true, force: true},
+		{name: "duplicate names", duplicate: true, wantErrSubstr: "Account nats/orders and Account nats/orders-copy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store := Store{StoresDir: filepath.Join(dir, "stores"), KeysDir: filepath.Join(dir, "keys")}
+
+			if tt.existing {
+				if err := writeStoreInfo(store.operatorDir("live"), "live"); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			objects := []runtime.Object{operator, account("orders", ordersKeyPair)}
+			if tt.duplicate {
+				objects = append(objects, account("orders-copy", copyKeyPair))
+			}
+
+			exporter := NewExporter(accountsfake.NewSimpleClientset(objects...).AccountsV1alpha1(),
+				k8sfake.NewSimpleClientset(c.secrets...).CoreV1())
+
+			_, err := exporter.Export(context.Background(), store, ExportOptions{Namespace: "nats", Force: tt.force})
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Export() error = %v, want %v", err, tt.wantErr)
+				}
+
+				if _, err := os.Stat(store.operatorDir("operator")); !os.IsNotExist(err) {
+					t.Errorf("expected nothing to be written to a non-empty store, stat error = %v", err)
+				}
+			case tt.wantErrSubstr != "":
+				if err == nil || !strings.Contains(err.Error(), tt.wantErrSubstr) {
+					t.Fatalf("Export() error = %v, want it to contain %q", err, tt.wantErrSubstr)
+				}
+			case err != nil:
+				t.Fatalf("unexpected error: %v", err)
+			default:
+				if _, err := os.Stat(store.accountJWTPath("operator", "orders")); err != nil {
+					t.Errorf("expected account JWT to be written: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestExporter_Export_InvalidNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		claim   string
+		wantErr string
+	}{
+		{name: "empty", claim: "", wantErr: "name claim is empty"},
+		{name: "parent directory", claim: "../../escape", wantErr: "contains a path separator"},
+		{name: "absolute", claim: "/tmp/escape", wantErr: "contains a path separator"},
+		{name: "backslash", claim: `..\escape`, wantErr: "contains a path separator"},
+		{name: "dot dot", claim: "..", wantErr: `contains ".."`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &testCluster{t: t}
+
+			operatorKP, operatorKeyPair := c.keyPair("operator", nkeys.CreateOperator)
+			_, accountKeyPair := c.keyPair("orders", nkeys.CreateAccount)
+
+			oc := jwt.NewOperatorClaims(operatorKeyPair.PublicKey)
+			oc.Name = "operator"
+			c.jwt("operator", oc, operatorKP)
+
+			ac := jwt.NewAccountClaims(accountKeyPair.PublicKey)
+			ac.Name = tt.claim
+			c.jwt("orders", ac, operatorKP)
+
+			objects := []runtime.Object{
+				&v1alpha1.Operator{
+					ObjectMeta: metav1.ObjectMeta{Name: "operator", Namespace: "nats"},
+					Spec:       v1alpha1.OperatorSpec{JWTSecretName: "operator-jwt"},
+					Status:     v1alpha1.OperatorStatus{KeyPair: operatorKeyPair},
+				},
+				&v1alpha1.Account{
+					ObjectMeta: metav1.ObjectMeta{Name: "orders", Namespace: "nats"},
+					Spec:       v1alpha1.AccountSpec{JWTSecretName: "orders-jwt"},
+					Status: v1alpha1.AccountStatus{
+						KeyPair:     accountKeyPair,
+						OperatorRef: &v1alpha1.InferredObjectReference{Namespace: "nats", Name: "operator"},
+					},
+				},
+			}
+
+			dir := t.TempDir()
+			store := Store{StoresDir: filepath.Join(dir, "stores"), KeysDir: filepath.Join(dir, "keys")}
+
+			exporter := NewExporter(accountsfake.NewSimpleClientset(objects...).AccountsV1alpha1(),
+				k8sfake.NewSimpleClientset(c.secrets...).CoreV1())
+
+			_, err := exporter.Export(context.Background(), store, ExportOptions{Namespace: "nats"})
+			if err == nil || !strings.Contains(err.Error(), "Account nats/orders") || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Export() error = %v, want it to contain %q", err, tt.wantErr)
+			}
+		})
+	}
+}
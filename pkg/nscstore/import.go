@@ -0,0 +1,616 @@
+package nscstore
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/internal/controller/accounts/resources"
+)
+
+// ImportOptions configures how an nsc store is converted to Kubernetes resources.
+type ImportOptions struct {
+	// Namespace is the namespace all resources are created in.
+	Namespace string
+
+	// Operator restricts the import to the operator with this name in the store, all operators are imported when
+	// empty.
+	Operator string
+}
+
+// ImportResult contains the resources converted from an nsc store.
+type ImportResult struct {
+	// Objects are the resources to apply, ordered such that Secrets precede the resources which reference them.
+	Objects []client.Object
+
+	// Warnings describe claims in the store which cannot be represented by the v1alpha1 API, and were dropped.
+	Warnings []string
+}
+
+// Import reads every operator, account, user and signing key in the store and converts them to Operator, Account,
+// User and SigningKey resources. Seeds found in the keystore are written to Secrets which the resources adopt through
+// existingSeedSecretRef, so public keys are preserved. Operators without a seed in the keystore are imported with an
+// offlineIdentity, using the operator JWT from the store.
+func Import(store Store, opts ImportOptions) (*ImportResult, error) {
+	im := &importer{
+		store:       store,
+		namespace:   opts.Namespace,
+		accounts:    make(map[string]string),
+		signingKeys: make(map[string]string),
+		names:       make(map[string]importedName),
+	}
+
+	operators, err := listDirs(store.StoresDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list operators: %w", err)
+	}
+
+	found := false
+
+	for _, dir := range operators {
+		if opts.Operator != "" && dir != opts.Operator {
+			continue
+		}
+
+		found = true
+
+		if err := im.importOperator(dir); err != nil {
+			return nil, fmt.Errorf("operator %s: %w", dir, err)
+		}
+	}
+
+	if opts.Operator != "" && !found {
+		return nil, fmt.Errorf("operator %s not found in %s", opts.Operator, store.StoresDir)
+	}
+
+	result := &ImportResult{Warnings: im.warnings}
+
+	for _, objs := range [][]client.Object{im.secrets, im.operators, im.signingKeyObjs, im.accountObjs, im.users} {
+		result.Objects = append(result.Objects, objs...)
+	}
+
+	return result, nil
+}
+
+type importer struct {
+	store     Store
+	namespace string
+
+	// accounts and signingKeys map public keys to the name of the imported resource, so that issuers and references
+	// can be resolved.
+	accounts    map[string]string
+	signingKeys map[string]string
+
+	// names maps the kind and name of every resource to the entity it was imported from, so that two entities are
+	// never imported as the same resource.
+	names map[string]importedName
+
+	// operatorName is the resource name of the operator being imported, it prefixes the names of its accounts when
+	// they collide with those of another operator.
+	operatorName string
+
+	secrets        []client.Object
+	operators      []client.Object
+	signingKeyObjs []client.Object
+	accountObjs    []client.Object
+	users          []client.Object
+
+	warnings []string
+}
+
+type importedName struct {
+	entity   string
+	operator string
+}
+
+func (im *importer) warnf(resource, format string, args ...any) {
+	im.warnings = append(im.warnings, resource+": "+fmt.Sprintf(format, args...))
+}
+
+func (im *importer) warnFunc(resource string) func(format string, args ...any) {
+	return func(format string, args ...any) {
+		im.warnf(resource, format, args...)
+	}
+}
+
+func (im *importer) importOperator(dir string) error {
+	ojwt, err := readJWT(im.store.operatorJWTPath(dir))
+	if err != nil {
+		return fmt.Errorf("failed to read operator JWT: %w", err)
+	}
+
+	claims, err := jwt.DecodeOperatorClaims(ojwt)
+	if err != nil {
+		return fmt.Errorf("failed to decode operator JWT: %w", err)
+	}
+
+	im.operatorName = ""
+
+	name, err := im.resourceName("Operator", fmt.Sprintf("operator %q", claims.Name), claims.Name)
+	if err != nil {
+		return err
+	}
+
+	resource := "Operator " + name
+	im.operatorName = name
+
+	operator := &v1alpha1.Operator{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "Operator"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: im.namespace},
+		Spec: v1alpha1.OperatorSpec{
+			JWTSecretName: name + "-jwt",
+			SigningKeysSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{resources.LabelOperatorName: name},
+			},
+			AccountServerURL:      claims.AccountServerURL,
+			OperatorServiceURLs:   claims.OperatorServiceURLs,
+			StrictSigningKeyUsage: claims.StrictSigningKeyUsage,
+			AssertServerVersion:   claims.AssertServerVersion,
+			Tags:                  claims.Tags,
+		},
+	}
+
+	seed, err := im.store.readSeed(claims.Subject)
+	if err != nil {
+		return err
+	}
+
+	if seed != nil {
+		if err := im.addSeedSecret(resource, name, seed); err != nil {
+			return err
+		}
+
+		operator.Spec.ExistingSeedSecretRef = seedSecretRef(name)
+	} else {
+		im.warnf(resource, "identity seed not found in keystore, importing with offlineIdentity")
+
+		// the label is required for the controller to watch the Secret, see OperatorOfflineIdentity
+		if err := im.addSecret(resource, name+"-nsc-jwt", map[string][]byte{
+			v1alpha1.NatsSecretJWTKey: []byte(ojwt),
+		}, map[string]string{
+			resources.LabelSecretType: resources.LabelSecretTypeJWT,
+		}); err != nil {
+			return err
+		}
+
+		operator.Spec.OfflineIdentity = &v1alpha1.OperatorOfflineIdentity{
+			JWTSecretRef: v1.SecretKeySelector{
+				LocalObjectReference: v1.LocalObjectReference{Name: name + "-nsc-jwt"},
+				Key:                  v1alpha1.NatsSecretJWTKey,
+			},
+		}
+	}
+
+	if claims.Expires != 0 {
+		im.warnf(resource, "expiry dropped")
+	}
+
+	for _, sk := range claims.SigningKeys {
+		if err := im.importSigningKey(sk, v1alpha1.SigningKeyTypeOperator, name); err != nil {
+			return err
+		}
+	}
+
+	accounts, err := listDirs(filepath.Join(im.store.operatorDir(dir), accountsDir))
+	if err != nil {
+		return fmt.Errorf("failed to list accounts: %w", err)
+	}
+
+	for _, acc := range accounts {
+		if err := im.importAccount(dir, acc, claims.Subject, name); err != nil {
+			return fmt.Errorf("account %s: %w", acc, err)
+		}
+	}
+
+	if sysAcc, ok := im.accounts[claims.SystemAccount]; ok {
+		operator.Spec.SystemAccountRef = v1.LocalObjectReference{Name: sysAcc}
+	} else {
+		im.warnf(resource, "system account %q was not imported, spec.systemAccountRef must be set manually", claims.SystemAccount)
+	}
+
+	im.operators = append(im.operators, operator)
+
+	return nil
+}
+
+func (im *importer) importSigningKey(publicKey, ownerKind, ownerName string) error {
+	name, err := im.resourceName("SigningKey", "signing key "+publicKey, ownerName+"-sk-"+publicKey[1:9])
+	if err != nil {
+		return err
+	}
+
+	resource := "SigningKey " + name
+
+	seed, err := im.store.readSeed(publicKey)
+	if err != nil {
+		return err
+	}
+
+	if seed == nil {
+		im.warnf(resource, "seed for %s not found in keystore, skipping", publicKey)
+
+		return nil
+	}
+
+	labelKey := resources.LabelOperatorName
+	if ownerKind == v1alpha1.SigningKeyTypeAccount {
+		labelKey = resources.LabelAccountName
+	}
+
+	// SigningKeys have no adoption mode, instead the Secret is created ahead of the SigningKey with the labels the
+	// controller sets on seed Secrets, which also makes it visible to the controller's label-filtered Secret cache.
+	if err := im.addSecret(resource, name+"-seed", map[string][]byte{
+		v1alpha1.NatsSecretSeedKey:      seed,
+		v1alpha1.NatsSecretPublicKeyKey: []byte(publicKey),
+	}, map[string]string{
+		resources.LabelSecretType:     resources.LabelSecretTypeSeed,
+		resources.LabelSecretSeedType: resources.LabelSecretTypeSigningKey,
+		resources.LabelSigningKeyName: name,
+		labelKey:                      ownerName,
+	}); err != nil {
+		return err
+	}
+
+	im.signingKeyObjs = append(im.signingKeyObjs, &v1alpha1.SigningKey{
+		TypeMeta: metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "SigningKey"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: im.namespace,
+			Labels:    map[string]string{labelKey: ownerName},
+		},
+		Spec: v1alpha1.SigningKeySpec{
+			SeedSecretName: name + "-seed",
+			OwnerRef: v1alpha1.SigningKeyOwnerReference{
+				APIVersion: v1alpha1.GroupVersion.String(),
+				Kind:       ownerKind,
+				Name:       ownerName,
+			},
+		},
+	})
+
+	im.signingKeys[publicKey] = name
+
+	return nil
+}
+
+func (im *importer) importAccount(operatorDir, dir, operatorPublicKey, operatorName string) error {
+	ajwt, err := readJWT(im.store.accountJWTPath(operatorDir, dir))
+	if err != nil {
+		return fmt.Errorf("failed to read account JWT: %w", err)
+	}
+
+	claims, err := jwt.DecodeAccountClaims(ajwt)
+	if err != nil {
+		return fmt.Errorf("failed to decode account JWT: %w", err)
+	}
+
+	name, err := im.resourceName("Account", fmt.Sprintf("account %q", claims.Name), claims.Name)
+	if err != nil {
+		return err
+	}
+
+	resource := "Account " + name
+	warn := im.warnFunc(resource)
+
+	seed, err := im.store.readSeed(claims.Subject)
+	if err != nil {
+		return err
+	}
+
+	if seed == nil {
+		im.warnf(resource, "seed not found in keystore, skipping account and its users")
+
+		return nil
+	}
+
+	issuer, ok := im.issuerRef(claims.Issuer, operatorPublicKey, "Operator", operatorName)
+	if !ok {
+		im.warnf(resource, "issuer %s was not imported, skipping account and its users", claims.Issuer)
+
+		return nil
+	}
+
+	if err := im.addSeedSecret(resource, name, seed); err != nil {
+		return err
+	}
+
+	account := &v1alpha1.Account{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "Account"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: im.namespace},
+		Spec: v1alpha1.AccountSpec{
+			Issuer:                issuer,
+			JWTSecretName:         name + "-jwt",
+			ExistingSeedSecretRef: seedSecretRef(name),
+			SigningKeysSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{resources.LabelAccountName: name},
+			},
+			Imports: convertFromNATSImports(claims.Imports, warn),
+			Exports: convertFromNATSExports(claims.Exports, warn),
+			Limits:  convertFromNATSOperatorLimits(claims.Limits),
+		},
+	}
+
+	if len(claims.Revocations) > 0 {
+		warn("revocations dropped")
+	}
+
+	if !claims.DefaultPermissions.Pub.Empty() || !claims.DefaultPermissions.Sub.Empty() || claims.DefaultPermissions.Resp != nil {
+		warn("defaultPermissions dropped")
+	}
+
+	if len(claims.Mappings) > 0 {
+		warn("mappings dropped")
+	}
+
+	if claims.Authorization.IsEnabled() {
+		warn("authorization dropped, spec.authorization must reference the auth callout Users")
+	}
+
+	if claims.Trace != nil {
+		warn("trace dropped")
+	}
+
+	if len(claims.Tags) > 0 {
+		warn("tags dropped")
+	}
+
+	if claims.Description != "" || claims.InfoURL != "" {
+		warn("description and infoURL dropped")
+	}
+
+	if claims.Expires != 0 {
+		warn("expiry dropped")
+	}
+
+	im.accounts[claims.Subject] = name
+
+	for _, sk := range sortedKeys(claims.SigningKeys) {
+		if claims.SigningKeys[sk] != nil {
+			warn("scoped signing key %s dropped, users issued by it are skipped", sk)
+
+			continue
+		}
+
+		if err := im.importSigningKey(sk, v1alpha1.SigningKeyTypeAccount, name); err != nil {
+			return err
+		}
+	}
+
+	users, err := listJWTs(filepath.Join(im.store.accountDir(operatorDir, dir), usersDir))
+	if err != nil {
+		return fmt.Errorf("failed to list users: %w", err)
+	}
+
+	for _, usr := range users {
+		if err := im.importUser(operatorDir, dir, usr, claims.Subject, name); err != nil {
+			return fmt.Errorf("user %s: %w", usr, err)
+		}
+	}
+
+	im.accountObjs = append(im.accountObjs, account)
+
+	return nil
+}
+
+func (im *importer) importUser(operatorDir, accountDir, file, accountPublicKey, accountName string) error {
+	ujwt, err := readJWT(im.store.userJWTPath(operatorDir, accountDir, file))
+	if err != nil {
+		return fmt.Errorf("failed to read user JWT: %w", err)
+	}
+
+	claims, err := jwt.DecodeUserClaims(ujwt)
+	if err != nil {
+		return fmt.Errorf("failed to decode user JWT: %w", err)
+	}
+
+	name, err := im.resourceName("User", fmt.Sprintf("user %q", claims.Name), accountName+"-"+claims.Name)
+	if err != nil {
+		return err
+	}
+
+	resource := "User " + name
+	warn := im.warnFunc(resource)
+
+	seed, err := im.store.readSeed(claims.Subject)
+	if err != nil {
+		return err
+	}
+
+	if seed == nil {
+		im.warnf(resource, "seed not found in keystore, skipping")
+
+		return nil
+	}
+
+	issuer, ok := im.issuerRef(claims.Issuer, accountPublicKey, "Account", accountName)
+	if !ok {
+		im.warnf(resource, "issuer %s was not imported, skipping", claims.Issuer)
+
+		return nil
+	}
+
+	if err := im.addSeedSecret(resource, name, seed); err != nil {
+		return err
+	}
+
+	user := &v1alpha1.User{
+		TypeMeta:   metav1.TypeMeta{APIVersion: v1alpha1.GroupVersion.String(), Kind: "User"},
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: im.namespace},
+		Spec: v1alpha1.UserSpec{
+			Issuer:                 issuer,
+			JWTSecretName:          name + "-jwt",
+			ExistingSeedSecretRef:  seedSecretRef(name),
+			CredentialsSecretName:  name + "-creds",
+			Permissions:            convertFromNATSPermissions(claims.Permissions),
+			Limits:                 convertFromNATSUserLimits(claims.Limits),
+			AllowedConnectionTypes: convertFromNATSConnectionTypes(claims.AllowedConnectionTypes, warn),
+			Tags:                   claims.Tags,
+		},
+	}
+
+	if claims.BearerToken {
+		bearer := true
+		user.Spec.BearerToken = &bearer
+	}
+
+	if claims.Expires != 0 {
+		warn("expiry dropped")
+	}
+
+	im.users = append(im.users, user)
+
+	return nil
+}
+
+// issuerRef resolves the issuer public key of a JWT to either the owning resource, identified by ownerPublicKey, or
+// one of the imported SigningKeys.
+func (im *importer) issuerRef(issuer, ownerPublicKey, ownerKind, ownerName string) (v1alpha1.IssuerReference, bool) {
+	ref := v1alpha1.TypedObjectReference{
+		APIVersion: v1alpha1.GroupVersion.String(),
+		Namespace:  im.namespace,
+	}
+
+	if issuer == ownerPublicKey {
+		ref.Kind = ownerKind
+		ref.Name = ownerName
+
+		return v1alpha1.IssuerReference{Ref: ref}, true
+	}
+
+	sk, ok := im.signingKeys[issuer]
+	if !ok {
+		return v1alpha1.IssuerReference{}, false
+	}
+
+	ref.Kind = "SigningKey"
+	ref.Name = sk
+
+	return v1alpha1.IssuerReference{Ref: ref}, true
+}
+
+// addSeedSecret validates seed and adds a Secret holding it under the default seed key, named for adoption by the
+// resource called name.
+func (im *importer) addSeedSecret(resource, name string, seed []byte) error {
+	if _, err := nkeys.FromSeed(seed); err != nil {
+		return fmt.Errorf("invalid seed for %s: %w", name, err)
+	}
+
+	return im.addSecret(resource, name+"-nsc-seed", map[string][]byte{
+		v1alpha1.NatsSecretSeedKey: seed,
+	}, nil)
+}
+
+// addSecret adds a Secret called name for resource, failing if another resource already added a Secret with the name.
+func (im *importer) addSecret(resource, name string, data map[string][]byte, labels map[string]string) error {
+	if err := im.claimName("Secret", resource, name); err != nil {
+		return err
+	}
+
+	im.secrets = append(im.secrets, im.newSecret(name, data, labels))
+
+	return nil
+}
+
+func (im *importer) newSecret(name string, data map[string][]byte, labels map[string]string) *v1.Secret {
+	return &v1.Secret{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "Secret"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: im.namespace,
+			Labels:    labels,
+		},
+		Type: v1.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+func seedSecretRef(name string) *v1.SecretKeySelector {
+	return &v1.SecretKeySelector{
+		LocalObjectReference: v1.LocalObjectReference{Name: name + "-nsc-seed"},
+		Key:                  v1alpha1.NatsSecretSeedKey,
+	}
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// longestSecretSuffix is the longest suffix appended to a resource name to name its Secrets.
+const longestSecretSuffix = "-nsc-seed"
+
+// resourceName converts name, the nsc name of entity, into the name of a resource of kind. When an entity of another
+// operator was imported as a resource of the same kind and name, such as the system accounts of several operators, the
+// name is prefixed with the name of the operator being imported. An error naming entity is returned if the name is
+// still taken, or is not a valid resource name.
+func (im *importer) resourceName(kind, entity, name string) (string, error) {
+	name = sanitizeName(name)
+	if name == "" {
+		return "", fmt.Errorf("%s: name does not contain any characters valid in a resource name", entity)
+	}
+
+	if taken, ok := im.names[kind+"/"+name]; ok && im.operatorName != "" && taken.operator != im.operatorName {
+		prefixed := sanitizeName(im.operatorName + "-" + name)
+
+		im.warnf(kind+" "+prefixed, "renamed from %s, which %s of operator %s was imported as", name, taken.entity,
+			taken.operator)
+
+		name = prefixed
+	}
+
+	// Operator, Account and SigningKey names are also used as label values
+	errs := validation.IsDNS1123Subdomain(name + longestSecretSuffix)
+	if kind != "User" {
+		errs = append(errs, validation.IsDNS1123Label(name)...)
+	}
+
+	if len(errs) > 0 {
+		return "", fmt.Errorf("%s: invalid resource name %q: %s", entity, name, strings.Join(errs, ", "))
+	}
+
+	if err := im.claimName(kind, entity, name); err != nil {
+		return "", err
+	}
+
+	return name, nil
+}
+
+// claimName records that entity is imported as the resource of kind called name, failing if another entity already
+// was.
+func (im *importer) claimName(kind, entity, name string) error {
+	key := kind + "/" + name
+
+	if taken, ok := im.names[key]; ok {
+		return fmt.Errorf("%s and %s would both be imported as %s %s", taken.entity, entity, kind, name)
+	}
+
+	im.names[key] = importedName{entity: entity, operator: im.operatorName}
+
+	return nil
+}
+
+// sanitizeName converts an nsc entity name into a Kubernetes resource name by lower-casing it and replacing invalid
+// characters.
+func sanitizeName(name string) string {
+	name = invalidNameChars.ReplaceAllString(strings.ToLower(name), "-")
+
+	return strings.Trim(name, "-")
+}
+
+func sortedKeys(keys jwt.SigningKeys) []string {
+	out := make([]string, 0, len(keys))
+	for k := range keys {
+		out = append(out, k)
+	}
+
+	sort.Strings(out)
+
+	return out
+}
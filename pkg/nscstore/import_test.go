@@ -0,0 +1,418 @@
+package nscstore
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nkeys"
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/internal/controller/accounts/resources"
+)
+
+// testStore writes entities to a temporary nsc store and keystore.
+type testStore struct {
+	t     *testing.T
+	store Store
+}
+
+func newTestStore(t *testing.T) *testStore {
+	dir := t.TempDir()
+
+	return &testStore{
+		t: t,
+		store: Store{
+			StoresDir: filepath.Join(dir, "stores"),
+			KeysDir:   filepath.Join(dir, "keys"),
+		},
+	}
+}
+
+func (s *testStore) key(create func() (nkeys.KeyPair, error), writeSeed bool) (nkeys.KeyPair, string) {
+	kp, err := create()
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	pub, err := kp.PublicKey()
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	if writeSeed {
+		seed, err := kp.Seed()
+		if err != nil {
+			s.t.Fatal(err)
+		}
+
+		s.write(s.store.keyPath(pub), string(seed)+"\n")
+	}
+
+	return kp, pub
+}
+
+func (s *testStore) write(path, data string) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		s.t.Fatal(err)
+	}
+
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		s.t.Fatal(err)
+	}
+}
+
+func (s *testStore) encode(claims jwt.Claims, kp nkeys.KeyPair) string {
+	token, err := claims.Encode(kp)
+	if err != nil {
+		s.t.Fatal(err)
+	}
+
+	return token
+}
+
+func TestImport(t *testing.T) {
+	s := newTestStore(t)
+
+	operatorKP, operatorPub := s.key(nkeys.CreateOperator, true)
+	operatorSKKP, operatorSKPub := s.key(nkeys.CreateOperator, true)
+	_, sysPub := s.key(nkeys.CreateAccount, true)
+	accKP, accPub := s.key(nkeys.CreateAccount, true)
+	_, accSKPub := s.key(nkeys.CreateAccount, true)
+	_, scopedSKPub := s.key(nkeys.CreateAccount, true)
+	_, userPub := s.key(nkeys.CreateUser, true)
+	_, noSeedUserPub := s.key(nkeys.CreateUser, false)
+
+	oc := jwt.NewOperatorClaims(operatorPub)
+	oc.Name = "MyOperator"
+	oc.SigningKeys.Add(operatorSKPub)
+	oc.SystemAccount = sysPub
+	oc.StrictSigningKeyUsage = true
+	s.write(s.store.operatorJWTPath("MyOperator"), s.encode(oc, operatorKP))
+
+	sc := jwt.NewAccountClaims(sysPub)
+	sc.Name = "SYS"
+	s.write(s.store.accountJWTPath("MyOperator", "SYS"), s.encode(sc, operatorSKKP))
+
+	ac := jwt.NewAccountClaims(accPub)
+	ac.Name = "orders"
+	ac.Exports.Add(&jwt.Export{Name: "orders", Subject: "orders.>", Type: jwt.Service, ResponseType: jwt.ResponseTypeStream, Advertise: true})
+	ac.Imports.Add(&jwt.Import{Name: "billing", Subject: "billing.>", Account: sysPub, Type: jwt.Stream})
+	ac.Limits.Conn = 10
+	ac.Limits.JetStreamLimits.DiskStorage = 1024
+	ac.Mappings = jwt.Mapping{"a": []jwt.WeightedMapping{{Subject: "b"}}}
+	ac.SigningKeys.Add(accSKPub)
+	scope := jwt.NewUserScope()
+	scope.Key = scopedSKPub
+	ac.SigningKeys.AddScopedSigner(scope)
+	s.write(s.store.accountJWTPath("MyOperator", "orders"), s.encode(ac, operatorSKKP))
+
+	uc := jwt.NewUserClaims(userPub)
+	uc.Name = "app"
+	uc.Pub.Allow.Add("orders.>")
+	uc.Limits.Payload = 512
+	uc.AllowedConnectionTypes.Add(jwt.ConnectionTypeStandard)
+	uc.BearerToken = true
+	s.write(s.store.userJWTPath("MyOperator", "orders", "app"), s.encode(uc, accKP))
+
+	nc := jwt.NewUserClaims(noSeedUserPub)
+	nc.Name = "noseed"
+	s.write(s.store.userJWTPath("MyOperator", "orders", "noseed"), s.encode(nc, accKP))
+
+	result, err := Import(s.store, ImportOptions{Namespace: "nats"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	objects := make(map[string]any)
+	for _, obj := range result.Objects {
+		objects[obj.GetObjectKind().GroupVersionKind().Kind+"/"+obj.GetName()] = obj
+	}
+
+	operator, ok := objects["Operator/myoperator"].(*v1alpha1.Operator)
+	if !ok {
+		t.Fatalf("operator not imported, got %v", keys(objects))
+	}
+
+	if operator.Spec.ExistingSeedSecretRef == nil || operator.Spec.ExistingSeedSecretRef.Name != "myoperator-nsc-seed" {
+		t.Errorf("operator existingSeedSecretRef = %+v", operator.Spec.ExistingSeedSecretRef)
+	}
+
+	if operator.Spec.SystemAccountRef.Name != "sys" {
+		t.Errorf("operator systemAccountRef = %q, want sys", operator.Spec.SystemAccountRef.Name)
+	}
+
+	if !operator.Spec.StrictSigningKeyUsage {
+		t.Errorf("operator strictSigningKeyUsage not imported")
+	}
+
+	operatorSKName := "myoperator-sk-" + strings.ToLower(operatorSKPub[1:9])
+	if _, ok := objects["SigningKey/"+operatorSKName].(*v1alpha1.SigningKey); !ok {
+		t.Errorf("operator signing key not imported, got %v", keys(objects))
+	}
+
+	signingKeySecret := func(skName, ownerLabel, ownerName, publicKey string) {
+		t.Helper()
+
+		secret, ok := objects["Secret/"+skName+"-seed"].(*v1.Secret)
+		if !ok || string(secret.Data[v1alpha1.NatsSecretPublicKeyKey]) != publicKey {
+			t.Errorf("signing key secret %s-seed not imported", skName)
+
+			return
+		}
+
+		wantLabels := map[string]string{
+			resources.LabelSecretType:     resources.LabelSecretTypeSeed,
+			resources.LabelSecretSeedType: resources.LabelSecretTypeSigningKey,
+			resources.LabelSigningKeyName: skName,
+			ownerLabel:                    ownerName,
+		}
+
+		if !reflect.DeepEqual(secret.Labels, wantLabels) {
+			t.Errorf("signing key secret %s-seed labels = %v, want %v", skName, secret.Labels, wantLabels)
+		}
+	}
+
+	signingKeySecret(operatorSKName, resources.LabelOperatorName, "myoperator", operatorSKPub)
+	signingKeySecret("orders-sk-"+strings.ToLower(accSKPub[1:9]), resources.LabelAccountName, "orders", accSKPub)
+
+	account, ok := objects["Account/orders"].(*v1alpha1.Account)
+	if !ok {
+		t.Fatalf("account not imported, got %v", keys(objects))
+	}
+
+	if account.Spec.Issuer.Ref.Kind != "SigningKey" || account.Spec.Issuer.Ref.Name != operatorSKName {
+		t.Errorf("account issuer = %+v, want SigningKey %s", account.Spec.Issuer.Ref, operatorSKName)
+	}
+
+	wantExports := []v1alpha1.AccountExport{{Name: "orders", Subject: "orders.>", Type: v1alpha1.ImportExportTypeService, ResponseType: v1alpha1.ResponseTypeStream}}
+	if !reflect.DeepEqual(account.Spec.Exports, wantExports) {
+		t.Errorf("account exports = %+v, want %+v", account.Spec.Exports, wantExports)
+	}
+
+	wantImports := []v1alpha1.AccountImport{{Name: "billing", Subject: "billing.>", Account: sysPub, Type: v1alpha1.ImportExportTypeStream}}
+	if !reflect.DeepEqual(account.Spec.Imports, wantImports) {
+		t.Errorf("account imports = %+v, want %+v", account.Spec.Imports, wantImports)
+	}
+
+	if l := account.Spec.Limits; l == nil || l.Account.Conn == nil || *l.Account.Conn != 10 || l.JetStream.DiskStorage != 1024 || l.Nats.Subs != nil {
+		t.Errorf("account limits = %+v", l)
+	}
+
+	if _, ok := objects["SigningKey/orders-sk-"+strings.ToLower(accSKPub[1:9])]; !ok {
+		t.Errorf("account signing key not imported, got %v", keys(objects))
+	}
+
+	if _, ok := objects["SigningKey/orders-sk-"+strings.ToLower(scopedSKPub[1:9])]; ok {
+		t.Errorf("scoped account signing key should not be imported")
+	}
+
+	user, ok := objects["User/orders-app"].(*v1alpha1.User)
+	if !ok {
+		t.Fatalf("user not imported, got %v", keys(objects))
+	}
+
+	if user.Spec.Issuer.Ref.Kind != "Account" || user.Spec.Issuer.Ref.Name != "orders" {
+		t.Errorf("user issuer = %+v, want Account orders", user.Spec.Issuer.Ref)
+	}
+
+	if user.Spec.Permissions == nil || !reflect.DeepEqual(user.Spec.Permissions.Pub.Allow, []string{"orders.>"}) {
+		t.Errorf("user permissions = %+v", user.Spec.Permissions)
+	}
+
+	if user.Spec.Limits.Payload == nil || *user.Spec.Limits.Payload != 512 || user.Spec.Limits.Subs != nil {
+		t.Errorf("user limits = %+v", user.Spec.Limits)
+	}
+
+	if user.Spec.BearerToken == nil || !*user.Spec.BearerToken {
+		t.Errorf("user bearer token not imported")
+	}
+
+	if !reflect.DeepEqual(user.Spec.AllowedConnectionTypes, []v1alpha1.ConnectionType{v1alpha1.ConnectionTypeStandard}) {
+		t.Errorf("user allowed connection types = %v", user.Spec.AllowedConnectionTypes)
+	}
+
+	if _, ok := objects["User/orders-noseed"]; ok {
+		t.Errorf("user without seed should not be imported")
+	}
+
+	for _, want := range []string{
+		"Account orders: exports[orders.>].advertise dropped",
+		"Account orders: mappings dropped",
+		"Account orders: scoped signing key " + scopedSKPub + " dropped",
+		"User orders-noseed: seed not found in keystore",
+	} {
+		if !containsPrefix(result.Warnings, want) {
+			t.Errorf("missing warning %q, got %v", want, result.Warnings)
+		}
+	}
+}
+
+func TestImport_OfflineOperator(t *testing.T) {
+	s := newTestStore(t)
+
+	operatorKP, operatorPub := s.key(nkeys.CreateOperator, false)
+
+	oc := jwt.NewOperatorClaims(operatorPub)
+	oc.Name = "offline"
+	ojwt := s.encode(oc, operatorKP)
+	s.write(s.store.operatorJWTPath("offline"), ojwt)
+
+	result, err := Import(s.store, ImportOptions{Namespace: "nats", Operator: "offline"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var (
+		operator *v1alpha1.Operator
+		secret   *v1.Secret
+	)
+
+	for _, obj := range result.Objects {
+		switch o := obj.(type) {
+		case *v1alpha1.Operator:
+			operator = o
+		case *v1.Secret:
+			secret = o
+		}
+	}
+
+	if operator == nil || operator.Spec.OfflineIdentity == nil {
+		t.Fatalf("expected operator with offlineIdentity, got %+v", operator)
+	}
+
+	if secret == nil || secret.Name != operator.Spec.OfflineIdentity.JWTSecretRef.Name || string(secret.Data[v1alpha1.NatsSecretJWTKey]) != ojwt {
+		t.Errorf("offline JWT secret = %+v", secret)
+	}
+
+	if secret != nil && secret.Labels[resources.LabelSecretType] != resources.LabelSecretTypeJWT {
+		t.Errorf("offline JWT secret labels = %v, want %s=%s so it is watched", secret.Labels,
+			resources.LabelSecretType, resources.LabelSecretTypeJWT)
+	}
+
+	if _, err := Import(s.store, ImportOptions{Operator: "missing"}); err == nil {
+		t.Errorf("expected error importing missing operator")
+	}
+}
+
+func TestImport_Names(t *testing.T) {
+	// operator writes an operator called name, with a system account called SYS and an account for each of accounts.
+	operator := func(s *testStore, name string, accounts ...string) {
+		operatorKP, operatorPub := s.key(nkeys.CreateOperator, true)
+		_, sysPub := s.key(nkeys.CreateAccount, true)
+
+		oc := jwt.NewOperatorClaims(operatorPub)
+		oc.Name = name
+		oc.SystemAccount = sysPub
+		s.write(s.store.operatorJWTPath(name), s.encode(oc, operatorKP))
+
+		for i, acc := range append([]string{"SYS"}, accounts...) {
+			pub := sysPub
+			if i > 0 {
+				_, pub = s.key(nkeys.CreateAccount, true)
+			}
+
+			ac := jwt.NewAccountClaims(pub)
+			ac.Name = acc
+			s.write(s.store.accountJWTPath(name, pub), s.encode(ac, operatorKP))
+		}
+	}
+
+	tests := []struct {
+		name         string
+		setup        func(s *testStore)
+		wantAccounts []string
+		wantErr      string
+	}{
+		{
+			name: "system accounts of several operators",
+			setup: func(s *testStore) {
+				operator(s, "a", "orders")
+				operator(s, "b")
+			},
+			wantAccounts: []string{"b-sys", "orders", "sys"},
+		},
+		{
+			name:    "accounts with the same resource name",
+			setup:   func(s *testStore) { operator(s, "a", "Orders", "orders") },
+			wantErr: `would both be imported as Account orders`,
+		},
+		{
+			name:    "operators with the same resource name",
+			setup:   func(s *testStore) { operator(s, "a"); operator(s, "A_") },
+			wantErr: `would both be imported as Operator a`,
+		},
+		{
+			name:    "no valid characters",
+			setup:   func(s *testStore) { operator(s, "a", "日本") },
+			wantErr: `account "日本": name does not contain any characters valid in a resource name`,
+		},
+		{
+			name:    "too long",
+			setup:   func(s *testStore) { operator(s, "a", strings.Repeat("x", 64)) },
+			wantErr: `invalid resource name`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t)
+			tt.setup(s)
+
+			result, err := Import(s.store, ImportOptions{Namespace: "nats"})
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Import() error = %v, want %q", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			var accounts []string
+
+			for _, obj := range result.Objects {
+				if acc, ok := obj.(*v1alpha1.Account); ok {
+					accounts = append(accounts, acc.Name)
+				}
+			}
+
+			sort.Strings(accounts)
+
+			if !reflect.DeepEqual(accounts, tt.wantAccounts) {
+				t.Errorf("accounts = %v, want %v", accounts, tt.wantAccounts)
+			}
+
+			if !containsPrefix(result.Warnings, "Account b-sys: renamed from sys") {
+				t.Errorf("warnings = %v, want the rename of b's system account", result.Warnings)
+			}
+		})
+	}
+}
+
+func containsPrefix(list []string, prefix string) bool {
+	for _, s := range list {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func keys(m map[string]any) []string {
+	out := make([]string, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+
+	return out
+}
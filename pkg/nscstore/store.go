@@ -0,0 +1,193 @@
+// Package nscstore reads and writes the directory layout used by the nsc tool, allowing existing nsc-managed
+// deployments to be migrated to Kubernetes resources, and managed resources to be exported for use with nsc.
+//
+// An nsc deployment is split into two directories:
+//
+//	<stores>/<operator>/<operator>.jwt
+//	<stores>/<operator>/accounts/<account>/<account>.jwt
+//	<stores>/<operator>/accounts/<account>/users/<user>.jwt
+//
+//	<keys>/keys/<kind>/<pk[1:3]>/<pk>.nk
+//	<keys>/creds/<operator>/<account>/<user>.creds
+//
+// where <stores> defaults to ~/.local/share/nats/nsc/stores and <keys> defaults to ~/.local/share/nats/nsc/keys.
+package nscstore
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/nats-io/jwt/v2"
+)
+
+const (
+	jwtExtension   = ".jwt"
+	nkeyExtension  = ".nk"
+	credsExtension = ".creds"
+
+	accountsDir = "accounts"
+	usersDir    = "users"
+	keysDir     = "keys"
+	credsDir    = "creds"
+
+	// infoFile is written to the root of each operator store, nsc uses it to identify the store.
+	infoFile = ".nsc"
+)
+
+// Store references an nsc store directory and its associated keystore.
+type Store struct {
+	// StoresDir is the directory containing one sub-directory per operator.
+	StoresDir string
+
+	// KeysDir is the keystore directory, nkeys are stored under KeysDir/keys and creds under KeysDir/creds.
+	KeysDir string
+}
+
+// DefaultStore returns the Store at the default nsc locations, honouring the NKEYS_PATH environment variable used by
+// nsc to relocate the keystore.
+func DefaultStore() (Store, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return Store{}, fmt.Errorf("failed to resolve home directory: %w", err)
+	}
+
+	base := filepath.Join(home, ".local", "share", "nats", "nsc")
+
+	keys := os.Getenv("NKEYS_PATH")
+	if keys == "" {
+		keys = filepath.Join(base, "keys")
+	}
+
+	return Store{
+		StoresDir: filepath.Join(base, "stores"),
+		KeysDir:   keys,
+	}, nil
+}
+
+func (s Store) operatorDir(operator string) string {
+	return filepath.Join(s.StoresDir, operator)
+}
+
+func (s Store) operatorJWTPath(operator string) string {
+	return filepath.Join(s.operatorDir(operator), operator+jwtExtension)
+}
+
+func (s Store) accountDir(operator, account string) string {
+	return filepath.Join(s.operatorDir(operator), accountsDir, account)
+}
+
+func (s Store) accountJWTPath(operator, account string) string {
+	return filepath.Join(s.accountDir(operator, account), account+jwtExtension)
+}
+
+func (s Store) userJWTPath(operator, account, user string) string {
+	return filepath.Join(s.accountDir(operator, account), usersDir, user+jwtExtension)
+}
+
+func (s Store) keyPath(publicKey string) string {
+	return filepath.Join(s.KeysDir, keysDir, publicKey[0:1], publicKey[1:3], publicKey+nkeyExtension)
+}
+
+func (s Store) credsPath(operator, account, user string) string {
+	return filepath.Join(s.KeysDir, credsDir, operator, account, user+credsExtension)
+}
+
+// checkEntityName returns an error if the name claim of a JWT cannot be used as a single path element, since entities
+// are written to paths built from their names. It prevents a crafted name writing outside of the store.
+func checkEntityName(name string) error {
+	switch {
+	case name == "":
+		return fmt.Errorf("name claim is empty")
+	case strings.ContainsAny(name, `/\`):
+		return fmt.Errorf("name claim %q contains a path separator", name)
+	case strings.Contains(name, ".."):
+		return fmt.Errorf("name claim %q contains %q", name, "..")
+	}
+
+	return nil
+}
+
+// storeInfo is the content of the infoFile at the root of each operator store.
+type storeInfo struct {
+	Name string `json:"name"`
+	Kind string `json:"kind"`
+}
+
+func writeStoreInfo(dir, name string) error {
+	data, err := json.Marshal(storeInfo{Name: name, Kind: jwt.OperatorClaim})
+	if err != nil {
+		return err
+	}
+
+	return writeFile(filepath.Join(dir, infoFile), data)
+}
+
+// readSeed returns the seed for publicKey from the keystore, or nil if the keystore does not contain it.
+func (s Store) readSeed(publicKey string) ([]byte, error) {
+	data, err := os.ReadFile(s.keyPath(publicKey))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("failed to read seed for %s: %w", publicKey, err)
+	}
+
+	return []byte(strings.TrimSpace(string(data))), nil
+}
+
+// listDirs returns the names of the directories within dir, or nil if dir does not exist.
+func listDirs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var names []string
+
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+
+	return names, nil
+}
+
+// listJWTs returns the names, without extension, of the JWT files within dir, or nil if dir does not exist.
+func listJWTs(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, err
+	}
+
+	var names []string
+
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), jwtExtension) {
+			names = append(names, strings.TrimSuffix(e.Name(), jwtExtension))
+		}
+	}
+
+	return names, nil
+}
+
+func readJWT(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
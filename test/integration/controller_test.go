@@ -0,0 +1,204 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nats-io/jwt/v2"
+	"github.com/nats-io/nats.go"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	"github.com/versori-oss/nats-account-operator/pkg/apis"
+)
+
+const (
+	timeout  = 60 * time.Second
+	interval = 250 * time.Millisecond
+)
+
+var _ = Describe("trust hierarchy", Ordered, func() {
+	var (
+		ctx       = context.Background()
+		namespace string
+		srv       *natsServer
+	)
+
+	secretData := func(name, key string) func() ([]byte, error) {
+		return func() ([]byte, error) {
+			secret := &corev1.Secret{}
+			if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, secret); err != nil {
+				return nil, err
+			}
+
+			value, ok := secret.Data[key]
+			if !ok {
+				return nil, fmt.Errorf("secret %s is missing key %s", name, key)
+			}
+
+			return value, nil
+		}
+	}
+
+	ready := func(obj interface {
+		client.Object
+		v1alpha1.StatusAccessor
+		v1alpha1.ConditionSetAccessor
+	}) func() (*apis.Condition, error) {
+		return func() (*apis.Condition, error) {
+			if err := k8sClient.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return nil, err
+			}
+
+			return obj.GetConditionSet().Manage(obj.GetStatus()).GetTopLevelCondition(), nil
+		}
+	}
+
+	beReady := WithTransform(func(c *apis.Condition) bool { return c.IsTrue() }, BeTrue())
+
+	BeforeAll(func() {
+		ns := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{GenerateName: "nats-"}}
+		Expect(k8sClient.Create(ctx, ns)).To(Succeed())
+
+		namespace = ns.Name
+		port := reservePort()
+
+		By("creating a bootstrapped Operator")
+		operator := &v1alpha1.Operator{
+			ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: namespace},
+			Spec: v1alpha1.OperatorSpec{
+				JWTSecretName:    "op-jwt",
+				SeedSecretName:   "op-seed",
+				SystemAccountRef: corev1.LocalObjectReference{Name: "sys"},
+				AccountServerURL: fmt.Sprintf("nats://127.0.0.1:%d", port),
+				Bootstrap:        &v1alpha1.OperatorBootstrap{},
+			},
+		}
+		Expect(k8sClient.Create(ctx, operator)).To(Succeed())
+
+		By("waiting for the Operator JWT to reference the system Account and operator SigningKey")
+		var operatorJWT string
+
+		Eventually(func(g Gomega) {
+			token, err := secretData("op-jwt", v1alpha1.NatsSecretJWTKey)()
+			g.Expect(err).NotTo(HaveOccurred())
+
+			claims, err := jwt.DecodeOperatorClaims(string(token))
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(claims.SystemAccount).NotTo(BeEmpty())
+
+			sk := &v1alpha1.SigningKey{}
+			g.Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "op-sk"}, sk)).To(Succeed())
+			g.Expect(sk.Status.KeyPair).NotTo(BeNil())
+			g.Expect(claims.SigningKeys.Contains(sk.Status.KeyPair.PublicKey)).To(BeTrue())
+
+			operatorJWT = string(token)
+		}, timeout, interval).Should(Succeed())
+
+		By("starting nats-server with the system Account preloaded")
+		claims, err := jwt.DecodeOperatorClaims(operatorJWT)
+		Expect(err).NotTo(HaveOccurred())
+
+		systemAccountJWT, err := secretData("sys-jwt", v1alpha1.NatsSecretJWTKey)()
+		Expect(err).NotTo(HaveOccurred())
+
+		srv = startNATSServer(port, operatorJWT, claims.SystemAccount, string(systemAccountJWT))
+	})
+
+	It("bootstraps a Ready Operator, system Account and system User", func() {
+		Eventually(ready(&v1alpha1.Operator{ObjectMeta: metav1.ObjectMeta{Name: "op", Namespace: namespace}}), timeout, interval).
+			Should(beReady)
+
+		operator := &v1alpha1.Operator{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "op"}, operator)).To(Succeed())
+
+		Eventually(func() bool {
+			Expect(k8sClient.Get(ctx, client.ObjectKeyFromObject(operator), operator)).To(Succeed())
+
+			return operator.Status.GetCondition(v1alpha1.OperatorConditionBootstrapReady).IsTrue()
+		}, timeout, interval).Should(BeTrue())
+
+		Expect(operator.Status.ObservedGeneration).To(Equal(operator.Generation))
+	})
+
+	It("pushes Accounts to the resolver and Users can connect with their creds", func() {
+		account := &v1alpha1.Account{
+			ObjectMeta: metav1.ObjectMeta{Name: "app", Namespace: namespace},
+			Spec: v1alpha1.AccountSpec{
+				Issuer: v1alpha1.IssuerReference{Ref: v1alpha1.TypedObjectReference{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       "SigningKey",
+					Name:       "op-sk",
+				}},
+				JWTSecretName:  "app-jwt",
+				SeedSecretName: "app-seed",
+			},
+		}
+		Expect(k8sClient.Create(ctx, account)).To(Succeed())
+
+		user := &v1alpha1.User{
+			ObjectMeta: metav1.ObjectMeta{Name: "app-user", Namespace: namespace},
+			Spec: v1alpha1.UserSpec{
+				Issuer: v1alpha1.IssuerReference{Ref: v1alpha1.TypedObjectReference{
+					APIVersion: v1alpha1.GroupVersion.String(),
+					Kind:       "Account",
+					Name:       "app",
+				}},
+				JWTSecretName:         "app-user-jwt",
+				SeedSecretName:        "app-user-seed",
+				CredentialsSecretName: "app-user-creds",
+			},
+		}
+		Expect(k8sClient.Create(ctx, user)).To(Succeed())
+
+		Eventually(ready(account), timeout, interval).Should(beReady)
+		Expect(srv.HasAccount(account.Status.KeyPair.PublicKey)).To(BeTrue())
+
+		Eventually(ready(user), timeout, interval).Should(beReady)
+
+		creds, err := secretData("app-user-creds", v1alpha1.NatsSecretCredsKey)()
+		Expect(err).NotTo(HaveOccurred())
+
+		credsFile := filepath.Join(GinkgoT().TempDir(), "nats.creds")
+		Expect(os.WriteFile(credsFile, creds, 0o600)).To(Succeed())
+
+		nc, err := nats.Connect(srv.ClientURL(), nats.UserCredentials(credsFile))
+		Expect(err).NotTo(HaveOccurred())
+
+		defer nc.Close()
+
+		sub, err := nc.SubscribeSync("greeting")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(nc.Publish("greeting", []byte("hello"))).To(Succeed())
+
+		msg, err := sub.NextMsg(5 * time.Second)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(msg.Data)).To(Equal("hello"))
+	})
+
+	It("removes deleted Accounts from the resolver", func() {
+		account := &v1alpha1.Account{}
+		Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: namespace, Name: "app"}, account)).To(Succeed())
+
+		pk := account.Status.KeyPair.PublicKey
+		Expect(srv.HasAccount(pk)).To(BeTrue())
+
+		Expect(k8sClient.Delete(ctx, account)).To(Succeed())
+
+		Eventually(func() bool {
+			return errors.IsNotFound(k8sClient.Get(ctx, client.ObjectKeyFromObject(account), account))
+		}, timeout, interval).Should(BeTrue())
+
+		Eventually(func() bool { return srv.HasAccount(pk) }, timeout, interval).Should(BeFalse())
+	})
+})
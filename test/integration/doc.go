@@ -0,0 +1,4 @@
+// Package integration runs every reconciler against envtest and an in-process nats-server in operator mode with a full
+// account resolver. The tests are behind the integration build tag since they need the envtest binaries, run them with
+// `make test-integration`.
+package integration
@@ -0,0 +1,82 @@
+//go:build integration
+
+package integration
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/nats-io/nats-server/v2/server"
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+// natsServer is an in-process nats-server in operator mode with a full account resolver, configured the same way as
+// the servers the operator pushes Account JWTs to in a cluster.
+type natsServer struct {
+	*server.Server
+
+	resolverDir string
+}
+
+// reservePort returns a free port on the loopback interface, so the account server URL can be set on the Operator
+// before the nats-server is started with the Operator JWT.
+func reservePort() int {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	Expect(err).NotTo(HaveOccurred())
+
+	defer l.Close()
+
+	return l.Addr().(*net.TCPAddr).Port
+}
+
+// startNATSServer starts a nats-server listening on port trusting operatorJWT, with the system Account JWT preloaded
+// into the resolver so the operator can connect as the system account to push the remaining Accounts.
+func startNATSServer(port int, operatorJWT, systemAccount, systemAccountJWT string) *natsServer {
+	dir := GinkgoT().TempDir()
+	resolverDir := filepath.Join(dir, "jwt")
+
+	config := fmt.Sprintf(`
+listen: 127.0.0.1:%d
+operator: %s
+system_account: %s
+resolver: {
+  type: full
+  dir: %q
+  allow_delete: true
+  interval: "2m"
+}
+resolver_preload: {
+  %s: %s
+}
+`, port, operatorJWT, systemAccount, resolverDir, systemAccount, systemAccountJWT)
+
+	configFile := filepath.Join(dir, "nats-server.conf")
+	Expect(os.WriteFile(configFile, []byte(config), 0o600)).To(Succeed())
+
+	opts, err := server.ProcessConfigFile(configFile)
+	Expect(err).NotTo(HaveOccurred())
+
+	opts.NoLog = true
+	opts.NoSigs = true
+
+	srv, err := server.NewServer(opts)
+	Expect(err).NotTo(HaveOccurred())
+
+	srv.Start()
+	DeferCleanup(srv.Shutdown)
+
+	Expect(srv.ReadyForConnections(5*time.Second)).To(BeTrue(), "nats-server not ready for connections")
+
+	return &natsServer{Server: srv, resolverDir: resolverDir}
+}
+
+// HasAccount returns true if the resolver has stored the JWT for the Account with the public key pk.
+func (s *natsServer) HasAccount(pk string) bool {
+	_, err := os.Stat(filepath.Join(s.resolverDir, pk+".jwt"))
+
+	return err == nil
+}
@@ -0,0 +1,100 @@
+//go:build integration
+
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/envtest"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	"github.com/versori-oss/nats-account-operator/api/accounts/v1alpha1"
+	accountscontroller "github.com/versori-oss/nats-account-operator/internal/controller/accounts"
+	"github.com/versori-oss/nats-account-operator/pkg/nsc"
+)
+
+var (
+	k8sClient client.Client
+	testEnv   *envtest.Environment
+	cancel    context.CancelFunc
+)
+
+func TestIntegration(t *testing.T) {
+	RegisterFailHandler(Fail)
+
+	RunSpecs(t, "Integration Suite")
+}
+
+var _ = BeforeSuite(func() {
+	logf.SetLogger(zap.New(zap.WriteTo(GinkgoWriter), zap.UseDevMode(true)))
+
+	By("bootstrapping test environment")
+	testEnv = &envtest.Environment{
+		CRDDirectoryPaths:     []string{filepath.Join("..", "..", "config", "crd", "bases")},
+		ErrorIfCRDPathMissing: true,
+	}
+
+	cfg, err := testEnv.Start()
+	Expect(err).NotTo(HaveOccurred())
+
+	scheme := clientgoscheme.Scheme
+	Expect(v1alpha1.AddToScheme(scheme)).To(Succeed())
+
+	k8sClient, err = client.New(cfg, client.Options{Scheme: scheme})
+	Expect(err).NotTo(HaveOccurred())
+
+	By("starting the controller manager")
+	mgr, err := ctrl.NewManager(cfg, ctrl.Options{
+		Scheme:  scheme,
+		Cache:   cache.Options{ByObject: accountscontroller.CacheByObject()},
+		Metrics: metricsserver.Options{BindAddress: "0"},
+	})
+	Expect(err).NotTo(HaveOccurred())
+
+	Expect(accountscontroller.SetupFieldIndexes(context.Background(), mgr.GetFieldIndexer())).To(Succeed())
+
+	mgrClient := accountscontroller.NewClient(mgr.GetClient(), mgr.GetAPIReader())
+
+	newBase := func() *accountscontroller.BaseReconciler {
+		return &accountscontroller.BaseReconciler{
+			Client:            mgrClient,
+			Scheme:            mgr.GetScheme(),
+			AccountServer:     nsc.NATSAccountServer{},
+			SystemCredentials: nsc.NewSystemAccountLoader(mgrClient),
+		}
+	}
+
+	Expect((&accountscontroller.OperatorReconciler{BaseReconciler: newBase()}).SetupWithManager(mgr)).To(Succeed())
+	Expect((&accountscontroller.AccountReconciler{BaseReconciler: newBase()}).SetupWithManager(mgr)).To(Succeed())
+	Expect((&accountscontroller.SigningKeyReconciler{BaseReconciler: newBase()}).SetupWithManager(mgr)).To(Succeed())
+	Expect((&accountscontroller.UserReconciler{BaseReconciler: newBase()}).SetupWithManager(mgr)).To(Succeed())
+
+	var ctx context.Context
+	ctx, cancel = context.WithCancel(context.Background())
+
+	go func() {
+		defer GinkgoRecover()
+
+		Expect(mgr.Start(ctx)).To(Succeed())
+	}()
+})
+
+var _ = AfterSuite(func() {
+	By("tearing down the test environment")
+
+	if cancel != nil {
+		cancel()
+	}
+
+	Expect(testEnv.Stop()).To(Succeed())
+})